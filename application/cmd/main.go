@@ -6,11 +6,14 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // member time zones are loaded without system tzdata
 
 	"github.com/ExchangeDiary/exchange-diary/application/controller"
 	"github.com/ExchangeDiary/exchange-diary/application/middleware"
 	"github.com/ExchangeDiary/exchange-diary/application/route"
 	"github.com/ExchangeDiary/exchange-diary/docs"
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
 	"github.com/ExchangeDiary/exchange-diary/domain/service"
 	"github.com/ExchangeDiary/exchange-diary/infrastructure"
 	"github.com/ExchangeDiary/exchange-diary/infrastructure/clients/firebase"
@@ -52,6 +55,7 @@ const (
 	//
 	versionPrefix = "/v1"
 	defaultPhase  = "dev"
+	prodPhase     = "prod"
 	configPath    = "./infrastructure/configs"
 )
 
@@ -70,13 +74,18 @@ func main() {
 	if err != nil {
 		panic("Failed to load config file: " + err.Error())
 	}
+	if conf.Invite.SecretKey == "" {
+		panic("Room invite secret key is not configured. (invite.secret-key or ROOM_INVITE_SECRET_KEY env)")
+	}
 	logger.Info("cold start google cloud storage client")
 	storageClient := cloudstorage.GetClient()
 	defer storageClient.Close()
 
-	logger.Info("cold start google cloud tasks client")
-	taskClient := tasks.GetClient()
-	defer taskClient.Close()
+	if conf.Scheduler.Kind == configs.CloudTasksScheduler {
+		logger.Info("cold start google cloud tasks client")
+		taskClient := tasks.GetClient()
+		defer taskClient.Close()
+	}
 
 	logger.Info("cold start firebase messaging app")
 	firebase.GetClient()
@@ -98,29 +107,79 @@ func bootstrap() *gin.Engine {
 	memberRepository := persistence.NewMemberRepository(db)
 	memberDeviceRepository := persistence.NewMemberDeviceRepository(db)
 	alarmRepository := persistence.NewAlarmRepository(db)
-
+	diaryRepository := persistence.NewDiaryRepository(db)
+	diaryDraftRepository := persistence.NewDiaryDraftRepository(db)
+	readMarkerRepository := persistence.NewReadMarkerRepository(db)
+	reactionRepository := persistence.NewReactionRepository(db)
+	commentRepository := persistence.NewCommentRepository(db)
+	exportRepository := persistence.NewExportRepository(db)
+	scheduledTaskRepository := persistence.NewScheduledTaskRepository(db)
+	taskNameRepository := persistence.NewTaskNameRepository(db)
+	outboxRepository := persistence.NewOutboxRepository(db)
+	taskExecutionRepository := persistence.NewTaskExecutionRepository(db)
+	adminAuditRepository := persistence.NewAdminAuditRepository(db)
+	swapRequestRepository := persistence.NewSwapRequestRepository(db)
+	roomInviteRepository := persistence.NewRoomInviteRepository(db)
+	joinAttemptRepository := persistence.NewJoinAttemptRepository(db)
+	unitOfWork := persistence.NewUnitOfWork(db)
+
+	scheduler := newScheduler(scheduledTaskRepository, taskNameRepository)
 	roomMemberService := service.NewRoomMemberService(roomMemberRepository, memberRepository)
 	memberService := service.NewMemberService(memberRepository)
-	roomService := service.NewRoomService(roomRepository, roomMemberService)
+	alarmService := service.NewAlarmService(unitOfWork, memberService, memberDeviceRepository, alarmRepository, conf.Scheduler.CallbackURL)
+	joinAttemptService := service.NewJoinAttemptService(joinAttemptRepository, memberService, alarmService)
+	roomService := service.NewRoomService(roomRepository, roomMemberService, readMarkerRepository, unitOfWork, joinAttemptService, diaryRepository)
+	roomCatalogService := service.NewRoomCatalogService(newRoomCatalog(conf.Catalog))
 	authCodeVerifier := service.NewTokenVerifier(service.AuthCodeSecretKey)
 	refreshTokenVerifier := service.NewTokenVerifier(service.AccessTokenSecretKey)
+	roomInviteVerifier := service.NewTokenVerifier(conf.Invite.SecretKey)
 	tokenService := service.NewTokenService(memberService, authCodeVerifier, refreshTokenVerifier, memberDeviceRepository)
+	roomInviteService := service.NewRoomInviteService(roomInviteRepository, roomService, roomInviteVerifier, conf.Invite.DeepLinkURL)
 	fileService := service.NewFileService()
-	alarmService := service.NewAlarmService(memberService, memberDeviceRepository, alarmRepository)
-	taskService := service.NewTaskService(alarmService, roomService, memberService)
+	diaryService := service.NewDiaryService(diaryRepository, diaryDraftRepository, memberRepository, readMarkerRepository, roomService, fileService)
+	diaryDraftService := service.NewDiaryDraftService(diaryDraftRepository, roomService)
+	reactionService := service.NewReactionService(reactionRepository, roomService, memberService, alarmService)
+	commentService := service.NewCommentService(commentRepository, memberRepository, roomService, alarmService)
+	exportService := service.NewExportService(exportRepository, roomService, diaryService, fileService, alarmService)
+	bookService := service.NewBookService(roomService, diaryService, fileService)
+	swapRequestService := service.NewSwapRequestService(unitOfWork, swapRequestRepository, roomService, memberService, alarmService, conf.Scheduler.CallbackURL)
+	taskService := service.NewTaskService(unitOfWork, taskExecutionRepository, scheduler, alarmService, roomService, memberService, diaryService, swapRequestService)
+	if localScheduler, ok := scheduler.(service.LocalScheduler); ok {
+		localScheduler.Start(taskService.Handle)
+	}
+	outboxRelay := service.NewOutboxRelay(outboxRepository, scheduler)
+	outboxRelay.Start()
+	adminTaskService := service.NewAdminTaskService(unitOfWork, adminAuditRepository, taskService, roomService, memberService)
+	reconcileService := service.NewReconcileService(unitOfWork, roomRepository, memberRepository, taskExecutionRepository, scheduler, conf.Scheduler.CallbackURL)
+	if conf.Scheduler.ReconcileInterval > 0 {
+		reconcileService.Start(conf.Scheduler.ReconcileInterval, conf.Scheduler.ReconcileDryRun)
+	}
 
 	memberController := controller.NewMemberController(memberService)
 	authController := controller.NewAuthController(conf.Client, memberService, tokenService)
 	tokenController := controller.NewTokenController(tokenService)
-	roomController := controller.NewRoomController(roomService, taskService)
+	roomController := controller.NewRoomController(roomService, taskService, roomCatalogService)
 	fileController := controller.NewFileController(fileService)
 	taskController := controller.NewTaskController(taskService, memberService)
 	alarmController := controller.NewAlarmController(alarmService, memberService)
+	diaryController := controller.NewDiaryController(diaryService, roomService, taskService, reactionService)
+	diaryDraftController := controller.NewDiaryDraftController(diaryDraftService, diaryService, taskService)
+	commentController := controller.NewCommentController(commentService, diaryService, roomService)
+	exportController := controller.NewExportController(exportService)
+	bookController := controller.NewBookController(bookService)
+	adminController := controller.NewAdminController(adminTaskService)
+	swapRequestController := controller.NewSwapRequestController(swapRequestService)
+	roomInviteController := controller.NewRoomInviteController(roomInviteService)
 
 	authenticationFilter := middleware.NewAuthenticationFilter(authCodeVerifier)
+	taskAuthenticationFilter := middleware.NewTaskAuthenticationFilter(conf.TaskAuth)
+	adminAuthorizationFilter := middleware.NewAdminAuthorizationFilter(conf.Admin)
 
 	// init server
 	server := gin.New()
+	if err := server.SetTrustedProxies(conf.Server.TrustedProxies); err != nil {
+		panic("Invalid trusted proxies: " + err.Error())
+	}
 
 	// set swagger
 	swagger(server)
@@ -133,7 +192,10 @@ func bootstrap() *gin.Engine {
 	v1 := server.Group(versionPrefix)
 	route.AuthRoutes(v1, authController)
 	route.TokenRoutes(v1, tokenController)
-	route.TaskRoutes(v1, taskController)
+	route.TaskRoutes(v1, taskController, taskAuthenticationFilter.Authenticate())
+	if phase != prodPhase {
+		route.TaskMockRoutes(v1, taskController, taskAuthenticationFilter.Authenticate())
+	}
 	route.MemberNoAuthRoutes(v1, memberController)
 	v1.Use(authenticationFilter.Authenticate())
 
@@ -141,10 +203,43 @@ func bootstrap() *gin.Engine {
 	route.MemberRoutes(v1, memberController)
 	route.FileRoutes(v1, fileController)
 	route.AlarmRoutes(v1, alarmController)
+	route.DiaryRoutes(v1, diaryController)
+	route.DiaryDraftRoutes(v1, diaryDraftController)
+	route.CommentRoutes(v1, commentController)
+	route.ExportRoutes(v1, exportController)
+	route.BookRoutes(v1, bookController)
+	route.SwapRequestRoutes(v1, swapRequestController)
+	route.RoomInviteRoutes(v1, roomInviteController)
+	route.AdminRoutes(v1, adminController, adminAuthorizationFilter.Authorize())
 
 	return server
 }
 
+// newScheduler selects scheduler implementation by phase config.
+func newScheduler(str repository.ScheduledTaskRepository, tnr repository.TaskNameRepository) service.Scheduler {
+	switch conf.Scheduler.Kind {
+	case configs.CloudTasksScheduler:
+		return tasks.NewScheduler(tasks.GetClient(), tnr)
+	default:
+		logger.Info("local scheduler is used", zap.Int("workers", conf.Scheduler.Workers))
+		return service.NewLocalScheduler(str, conf.Scheduler.Workers)
+	}
+}
+
+// newRoomCatalog seeds room catalog from config.
+func newRoomCatalog(conf configs.RoomCatalog) *entity.RoomCatalog {
+	catalog := &entity.RoomCatalog{Periods: conf.Periods}
+	for _, theme := range conf.Themes {
+		catalog.Themes = append(catalog.Themes, entity.RoomTheme{
+			ID:            theme.ID,
+			Name:          theme.Name,
+			Hashtag:       theme.Hashtag,
+			BackgroundURL: theme.BackgroundURL,
+		})
+	}
+	return catalog
+}
+
 func swagger(server *gin.Engine) {
 	docs.SwaggerInfo.BasePath = versionPrefix
 	server.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
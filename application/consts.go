@@ -5,4 +5,6 @@ const (
 	CurrentMemberKey = "currentMember"
 	// AuthCodeKey is a auth code url query parameter
 	AuthCodeKey = "authcode"
+	// TaskCallbackPath is an endpoint which google cloud tasks calls back
+	TaskCallbackPath = "/v1/tasks/callback"
 )
@@ -0,0 +1,299 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/application"
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/service"
+	"github.com/ExchangeDiary/exchange-diary/domain/vo"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminController handles /v1/admin api
+type AdminController interface {
+	GetTasks() gin.HandlerFunc
+	RunTask() gin.HandlerFunc
+	RescheduleTask() gin.HandlerFunc
+	CancelTask() gin.HandlerFunc
+	GetAudits() gin.HandlerFunc
+}
+
+type adminController struct {
+	adminTaskService service.AdminTaskService
+}
+
+// NewAdminController ...
+func NewAdminController(ats service.AdminTaskService) AdminController {
+	return &adminController{adminTaskService: ats}
+}
+
+type responseAdminTask struct {
+	TaskID      string      `json:"taskId"`
+	AccountID   uint        `json:"accountId"`
+	Email       string      `json:"email,omitempty"`
+	Code        vo.TaskCode `json:"code" example:"MEMBER_BEFORE_4HR"`
+	Turn        uint        `json:"turn"`
+	DueAt       *time.Time  `json:"dueAt,omitempty"`
+	ScheduledAt *time.Time  `json:"scheduledAt,omitempty"`
+}
+
+type responseAdminAudit struct {
+	ID        uint       `json:"id"`
+	AdminID   uint       `json:"adminId"`
+	Action    string     `json:"action" enums:"RUN_TASK,RESCHEDULE_TASK,CANCEL_TASK"`
+	RoomID    uint       `json:"roomId"`
+	TaskID    string     `json:"taskId"`
+	Detail    string     `json:"detail"`
+	Error     string     `json:"error,omitempty"`
+	CreatedAt *time.Time `json:"createdAt"`
+}
+
+type rescheduleTaskRequest struct {
+	ScheduledAt time.Time `json:"scheduledAt" binding:"required"`
+}
+
+// taskParams is a path of a task: /admin/rooms/:room_id/tasks/:account_id/:code
+type taskParams struct {
+	RoomID    uint
+	AccountID uint
+	Code      vo.TaskCode
+}
+
+func parseTaskParams(c *gin.Context) (*taskParams, error) {
+	roomID, err := application.ParseUint(c.Param("room_id"))
+	if err != nil {
+		return nil, err
+	}
+	accountID, err := application.ParseUint(c.Param("account_id"))
+	if err != nil {
+		return nil, err
+	}
+	code := vo.TaskCode(c.Param("code"))
+	if !code.IsScheduled() {
+		return nil, errors.New("invalid task code: " + string(code))
+	}
+	return &taskParams{RoomID: roomID, AccountID: accountID, Code: code}, nil
+}
+
+// @Summary      get tasks of a room
+// @Description  교환일기방의 모든 멤버에 대해, 스케쥴러에 대기중인 task 목록을 스케쥴 시간순으로 조회한다. (admin only)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        room_id  path  int  true  "교환일기방 ID"  Format(uint)
+// @Success      200  {array}   responseAdminTask
+// @Failure      400
+// @Failure      403
+// @Failure      404
+// @Router       /admin/rooms/{room_id}/tasks [get]
+// @Security ApiKeyAuth
+func (ac *adminController) GetTasks() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roomID, err := application.ParseUint(c.Param("room_id"))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		tasks, err := ac.adminTaskService.ListTasks(roomID)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(adminErrorStatus(err), err.Error())
+			return
+		}
+		res := []responseAdminTask{}
+		for _, task := range tasks {
+			item := responseAdminTask{
+				TaskID:    task.TaskID,
+				AccountID: task.AccountID,
+				Email:     task.Email,
+				Code:      task.Code,
+				Turn:      task.Turn,
+				DueAt:     task.DueAt,
+			}
+			if !task.ScheduledAt.IsZero() {
+				scheduledAt := task.ScheduledAt
+				item.ScheduledAt = &scheduledAt
+			}
+			res = append(res, item)
+		}
+		c.JSON(http.StatusOK, res)
+	}
+}
+
+// @Summary      run a task
+// @Description  대기중인 task를 바로 실행한다. (admin only)
+// @Description  * 예약된 task는 이후에 전달되더라도 중복으로 처리되어 실행되지 않는다.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        room_id     path  int     true  "교환일기방 ID"  Format(uint)
+// @Param        account_id  path  int     true  "task가 등록된 턴 멤버 ID"  Format(uint)
+// @Param        code        path  string  true  "task code (ROOM_PERIOD_FIN, MEMBER_ON_DUTY, MEMBER_POSTED_DIARY, MEMBER_BEFORE_{n}HR, MEMBER_BEFORE_{n}MIN)"
+// @Success      204
+// @Failure      400
+// @Failure      403
+// @Failure      404
+// @Failure      409 "already executed or running task"
+// @Failure      422 "task can not be executed. (ex. stale turn, deleted room)"
+// @Router       /admin/rooms/{room_id}/tasks/{account_id}/{code}/run [post]
+// @Security ApiKeyAuth
+func (ac *adminController) RunTask() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		params, err := parseTaskParams(c)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := ac.adminTaskService.RunTask(currentMember.ID, params.RoomID, params.AccountID, params.Code); err != nil {
+			logger.Error(err.Error())
+			c.JSON(adminErrorStatus(err), err.Error())
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// @Summary      reschedule a task
+// @Description  대기중인 task의 스케쥴 시간을 변경한다. (admin only)
+// @Description  * 현재 턴의 ROOM_PERIOD_FIN task는 교환일기방의 마감 시간(dueAt)과 알림 task들도 함께 변경된다.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        room_id     path  int                    true  "교환일기방 ID"  Format(uint)
+// @Param        account_id  path  int                    true  "task가 등록된 턴 멤버 ID"  Format(uint)
+// @Param        code        path  string                 true  "task code (ROOM_PERIOD_FIN, MEMBER_ON_DUTY, MEMBER_POSTED_DIARY, MEMBER_BEFORE_{n}HR, MEMBER_BEFORE_{n}MIN)"
+// @Param        task        body  rescheduleTaskRequest  true  "변경할 스케쥴 시간 (미래)"
+// @Success      204
+// @Failure      400
+// @Failure      403
+// @Failure      404
+// @Router       /admin/rooms/{room_id}/tasks/{account_id}/{code} [patch]
+// @Security ApiKeyAuth
+func (ac *adminController) RescheduleTask() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		params, err := parseTaskParams(c)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		var req rescheduleTaskRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := ac.adminTaskService.RescheduleTask(currentMember.ID, params.RoomID, params.AccountID, params.Code, req.ScheduledAt); err != nil {
+			logger.Error(err.Error())
+			c.JSON(adminErrorStatus(err), err.Error())
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// @Summary      cancel a task
+// @Description  대기중인 task를 삭제한다. 삭제된 교환일기방의 task도 삭제할 수 있다. (admin only)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        room_id     path  int     true  "교환일기방 ID"  Format(uint)
+// @Param        account_id  path  int     true  "task가 등록된 턴 멤버 ID"  Format(uint)
+// @Param        code        path  string  true  "task code (ROOM_PERIOD_FIN, MEMBER_ON_DUTY, MEMBER_POSTED_DIARY, MEMBER_BEFORE_{n}HR, MEMBER_BEFORE_{n}MIN)"
+// @Success      204
+// @Failure      400
+// @Failure      403
+// @Failure      404
+// @Router       /admin/rooms/{room_id}/tasks/{account_id}/{code} [delete]
+// @Security ApiKeyAuth
+func (ac *adminController) CancelTask() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		params, err := parseTaskParams(c)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := ac.adminTaskService.CancelTask(currentMember.ID, params.RoomID, params.AccountID, params.Code); err != nil {
+			logger.Error(err.Error())
+			c.JSON(adminErrorStatus(err), err.Error())
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// @Summary      get audits of a room
+// @Description  교환일기방 task에 대한 admin 작업 이력을 최신순으로 조회한다. (admin only)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        room_id  path   int  true   "교환일기방 ID"  Format(uint)
+// @Param        limit    query  int  false  "limit"   Format(uint)
+// @Param        offset   query  int  false  "offset"  Format(uint)
+// @Success      200  {array}   responseAdminAudit
+// @Failure      400
+// @Failure      403
+// @Router       /admin/rooms/{room_id}/audits [get]
+// @Security ApiKeyAuth
+func (ac *adminController) GetAudits() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roomID, err := application.ParseUint(c.Param("room_id"))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		limit, offset := application.GetLimitAndOffset(c)
+
+		audits, err := ac.adminTaskService.ListAudits(roomID, limit, offset)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(adminErrorStatus(err), err.Error())
+			return
+		}
+		res := []responseAdminAudit{}
+		for _, audit := range audits {
+			res = append(res, responseAdminAudit{
+				ID:        audit.ID,
+				AdminID:   audit.AdminID,
+				Action:    string(audit.Action),
+				RoomID:    audit.RoomID,
+				TaskID:    audit.TaskID,
+				Detail:    audit.Detail,
+				Error:     audit.Error,
+				CreatedAt: audit.CreatedAt,
+			})
+		}
+		c.JSON(http.StatusOK, res)
+	}
+}
+
+// adminErrorStatus maps task domain error to http status code
+func adminErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrRoomNotFound), errors.Is(err, entity.ErrScheduledTaskNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entity.ErrInvalidScheduledAt):
+		return http.StatusBadRequest
+	case errors.Is(err, entity.ErrTaskAlreadyExecuted), errors.Is(err, entity.ErrTaskInProgress):
+		return http.StatusConflict
+	case entity.IsPermanent(err):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ExchangeDiary/exchange-diary/application"
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/service"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/book"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// BookController handles /v1/rooms/:room_id/book api
+type BookController interface {
+	Get() gin.HandlerFunc
+}
+
+type bookController struct {
+	bookService service.BookService
+}
+
+// NewBookController is a bookController's constructor
+func NewBookController(bs service.BookService) BookController {
+	return &bookController{bookService: bs}
+}
+
+// @Summary      render a room as a book
+// @Description  교환일기방의 교환일기를 인쇄용 책으로 만든다.
+// @Description  * 표지(방 이름, 테마), 작성자별 목차, 작성 순서대로의 교환일기로 구성된다.
+// @Description  * 사진은 본문에 포함되며, 음성은 QR 코드로 연결된다.
+// @Description  * format: pdf(default) 또는 html
+// @Tags         rooms
+// @Produce      application/pdf
+// @Produce      text/html
+// @Param        room_id  path   int     true   "교환일기방 ID"  Format(uint)
+// @Param        format   query  string  false  "pdf | html"  default(pdf)
+// @Success      200  {file}  file
+// @Failure      400
+// @Failure      401
+// @Router       /rooms/{room_id}/book [get]
+// @Security ApiKeyAuth
+func (bc *bookController) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		roomID, err := application.ParseUint(c.Param("room_id"))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		format, err := book.ParseFormat(c.Query("format"))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		rendered, err := bc.bookService.Render(roomID, currentMember.ID, format)
+		if err != nil {
+			logger.Error(err.Error())
+			if errors.Is(err, entity.ErrNotJoinedRoom) {
+				c.JSON(http.StatusUnauthorized, err.Error())
+				return
+			}
+			c.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="room-%d.%s"`, roomID, format))
+		c.Data(http.StatusOK, format.ContentType(), rendered)
+	}
+}
@@ -0,0 +1,226 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/application"
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/service"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// CommentController handles /v1/rooms/:room_id/diaries/:diary_id/comments api
+type CommentController interface {
+	GetAll() gin.HandlerFunc
+	Post() gin.HandlerFunc
+	Delete() gin.HandlerFunc
+}
+
+type commentController struct {
+	commentService service.CommentService
+	diaryService   service.DiaryService
+	roomService    service.RoomService
+}
+
+// NewCommentController is a commentController's constructor
+func NewCommentController(cs service.CommentService, ds service.DiaryService, rs service.RoomService) CommentController {
+	return &commentController{
+		commentService: cs,
+		diaryService:   ds,
+		roomService:    rs,
+	}
+}
+
+type responseComment struct {
+	ID        uint               `json:"id"`
+	ParentID  *uint              `json:"parentId,omitempty"`
+	Body      string             `json:"body"`
+	Author    *responseMember    `json:"author"`
+	IsDeleted bool               `json:"isDeleted,omitempty"`
+	Replies   *[]responseComment `json:"replies,omitempty"`
+	CreatedAt *time.Time         `json:"createdAt"`
+}
+
+func toResponseComment(comment *entity.Comment) responseComment {
+	res := responseComment{
+		ID:        comment.ID,
+		ParentID:  comment.ParentID,
+		Body:      comment.Body,
+		IsDeleted: comment.IsDeleted(),
+		CreatedAt: comment.CreatedAt,
+	}
+	// 삭제된 댓글은 답글이 남아있는 경우에만 내용 없이 노출된다.
+	if comment.IsDeleted() {
+		res.Body = ""
+	}
+	if comment.Author != nil {
+		res.Author = toResponseMember(comment.Author)
+	}
+	if comment.Replies != nil {
+		replies := []responseComment{}
+		for i := range *comment.Replies {
+			replies = append(replies, toResponseComment(&(*comment.Replies)[i]))
+		}
+		res.Replies = &replies
+	}
+	return res
+}
+
+type listResponseComment struct {
+	Comments []responseComment `json:"comments"`
+}
+
+// @Summary      List comments
+// @Description  교환일기의 댓글 리스트 (작성순)
+// @Description  * 댓글 단위로 limit/offset 페이지네이션 하며, 답글은 댓글의 replies에 모두 포함된다. (default limit: 10, 1 ~ 100)
+// @Description  * 삭제된 댓글은 답글이 남아있는 경우에만 isDeleted: true로 노출된다.
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        room_id   path   int  true   "교환일기방 ID"  Format(uint)
+// @Param        diary_id  path   int  true   "교환일기 ID"  Format(uint)
+// @Param        limit     query  int  false  "limit"  default(10)
+// @Param        offset    query  int  false  "offset"  default(0)
+// @Success      200  {object}   listResponseComment
+// @Failure      400
+// @Failure      401
+// @Router       /rooms/{room_id}/diaries/{diary_id}/comments [get]
+// @Security ApiKeyAuth
+func (cc *commentController) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		diary, ok := getJoinedRoomDiary(c, cc.roomService, cc.diaryService)
+		if !ok {
+			return
+		}
+		limit, offset, err := application.GetValidLimitAndOffset(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		comments, err := cc.commentService.GetAll(diary.ID, limit, offset)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+		res := listResponseComment{Comments: []responseComment{}}
+		for i := range *comments {
+			res.Comments = append(res.Comments, toResponseComment(&(*comments)[i]))
+		}
+		c.JSON(http.StatusOK, res)
+	}
+}
+
+type postRequestComment struct {
+	Body     string `json:"body" example:"오늘도 고생했어!"`
+	ParentID *uint  `json:"parentId,omitempty"`
+}
+
+// @Summary      post a comment
+// @Description  교환일기 댓글 작성
+// @Description  * parentId를 전달하면 해당 댓글의 답글로 작성된다. 답글에는 답글을 달 수 없다.
+// @Description  * 교환일기 작성자와 (답글인 경우) 댓글 작성자에게 알림이 전송된다.
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        room_id   path  int                 true  "교환일기방 ID"  Format(uint)
+// @Param        diary_id  path  int                 true  "교환일기 ID"  Format(uint)
+// @Param        comment   body  postRequestComment  true  "댓글 작성 요청 body"
+// @Success      201  {object}   responseComment
+// @Failure      400
+// @Failure      401
+// @Failure      404
+// @Router       /rooms/{room_id}/diaries/{diary_id}/comments [post]
+// @Security ApiKeyAuth
+func (cc *commentController) Post() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		var req postRequestComment
+		if err := c.BindJSON(&req); err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		diary, ok := getJoinedRoomDiary(c, cc.roomService, cc.diaryService)
+		if !ok {
+			return
+		}
+
+		comment, err := entity.NewComment(diary.ID, currentMember.ID, req.Body)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		comment.ParentID = req.ParentID
+		if comment, err = cc.commentService.Create(diary, comment); err != nil {
+			logger.Error(err.Error())
+			c.JSON(commentErrorStatus(err), err.Error())
+			return
+		}
+		c.JSON(http.StatusCreated, toResponseComment(comment))
+	}
+}
+
+// @Summary      delete a comment
+// @Description  교환일기 댓글 삭제 (작성자만 가능)
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        room_id     path  int  true  "교환일기방 ID"  Format(uint)
+// @Param        diary_id    path  int  true  "교환일기 ID"  Format(uint)
+// @Param        comment_id  path  int  true  "댓글 ID"  Format(uint)
+// @Success      204
+// @Failure      400
+// @Failure      401
+// @Failure      404
+// @Router       /rooms/{room_id}/diaries/{diary_id}/comments/{comment_id} [delete]
+// @Security ApiKeyAuth
+func (cc *commentController) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		commentID, err := application.ParseUint(c.Param("comment_id"))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		diary, ok := getJoinedRoomDiary(c, cc.roomService, cc.diaryService)
+		if !ok {
+			return
+		}
+
+		comment, err := cc.commentService.Get(commentID)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(commentErrorStatus(err), err.Error())
+			return
+		}
+		if comment.DiaryID != diary.ID {
+			c.JSON(http.StatusNotFound, entity.ErrCommentNotFound.Error())
+			return
+		}
+		if err := cc.commentService.Delete(comment, currentMember.ID); err != nil {
+			logger.Error(err.Error())
+			c.JSON(commentErrorStatus(err), err.Error())
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// commentErrorStatus maps comment domain error to http status code
+func commentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrCommentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entity.ErrNotCommentAuthor):
+		return http.StatusUnauthorized
+	default:
+		return http.StatusBadRequest
+	}
+}
@@ -0,0 +1,652 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/application"
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/service"
+	"github.com/ExchangeDiary/exchange-diary/domain/vo"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// DiaryController handles /v1/rooms/:room_id/diaries api
+type DiaryController interface {
+	Get() gin.HandlerFunc
+	GetAll() gin.HandlerFunc
+	Post() gin.HandlerFunc
+	Patch() gin.HandlerFunc
+	Delete() gin.HandlerFunc
+	Read() gin.HandlerFunc
+	React() gin.HandlerFunc
+	Search() gin.HandlerFunc
+}
+
+type diaryController struct {
+	diaryService    service.DiaryService
+	roomService     service.RoomService
+	taskService     service.TaskService
+	reactionService service.ReactionService
+}
+
+// NewDiaryController is a diaryController's constructor
+func NewDiaryController(ds service.DiaryService, rs service.RoomService, ts service.TaskService, rcs service.ReactionService) DiaryController {
+	return &diaryController{
+		diaryService:    ds,
+		roomService:     rs,
+		taskService:     ts,
+		reactionService: rcs,
+	}
+}
+
+type responseDiary struct {
+	ID         uint            `json:"id"`
+	RoomID     uint            `json:"roomId"`
+	Turn       uint            `json:"turn"`
+	Title      string          `json:"title"`
+	Body       string          `json:"body"`
+	Author     *responseMember `json:"author"`
+	PhotoUUID  string          `json:"photoUUID,omitempty"`
+	PhotoURL   string          `json:"photoURL,omitempty"`
+	PhotoHash  string          `json:"photoHash,omitempty"`
+	AudioUUID  string          `json:"audioUUID,omitempty"`
+	AudioURL   string          `json:"audioURL,omitempty"`
+	AudioHash  string          `json:"audioHash,omitempty"`
+	AudioTitle string          `json:"audioTitle,omitempty"`
+	AudioPitch string          `json:"audioPitch,omitempty"`
+	Reactions  map[string]uint `json:"reactions"`
+	MyReaction string          `json:"myReaction,omitempty"`
+	CreatedAt  *time.Time      `json:"createdAt"`
+}
+
+func toResponseDiary(diary *entity.Diary) responseDiary {
+	res := responseDiary{
+		ID:         diary.ID,
+		RoomID:     diary.RoomID,
+		Turn:       diary.Turn,
+		Title:      diary.Title,
+		Body:       diary.Body,
+		PhotoUUID:  diary.PhotoUUID,
+		PhotoURL:   diary.PhotoURL,
+		PhotoHash:  diary.PhotoHash,
+		AudioUUID:  diary.AudioUUID,
+		AudioURL:   diary.AudioURL,
+		AudioHash:  diary.AudioHash,
+		AudioTitle: diary.AudioTitle,
+		AudioPitch: diary.AudioPitch,
+		Reactions:  toResponseReactions(diary.Reactions),
+		CreatedAt:  diary.CreatedAt,
+	}
+	if diary.Author != nil {
+		res.Author = toResponseMember(diary.Author)
+	}
+	return res
+}
+
+func toResponseReactions(counts entity.ReactionCounts) map[string]uint {
+	res := map[string]uint{}
+	for emoji, count := range counts {
+		res[string(emoji)] = count
+	}
+	return res
+}
+
+func toResponseMember(member *entity.Member) *responseMember {
+	return &responseMember{
+		ID:         member.ID,
+		NickName:   member.Name,
+		ProfileURL: member.ProfileURL,
+	}
+}
+
+type summaryResponseDiary struct {
+	ID        uint            `json:"id"`
+	Turn      uint            `json:"turn"`
+	Title     string          `json:"title"`
+	Summary   string          `json:"summary"`
+	HasPhoto  bool            `json:"hasPhoto"`
+	HasAudio  bool            `json:"hasAudio"`
+	Reactions map[string]uint `json:"reactions"`
+	Author    *responseMember `json:"author"`
+	CreatedAt *time.Time      `json:"createdAt"`
+}
+
+type listResponseDiary struct {
+	Diaries    []summaryResponseDiary `json:"diaries"`
+	NextCursor uint                   `json:"nextCursor,omitempty"`
+}
+
+// @Summary      List diaries
+// @Description  교환일기방의 교환일기 리스트 (최신순)
+// @Description  * limit/offset 또는 cursor로 페이지네이션 한다. (default limit: 10, 1 ~ 100)
+// @Description  * cursor에는 이전 응답의 nextCursor를 넣어주면 된다.
+// @Tags         diaries
+// @Accept       json
+// @Produce      json
+// @Param        room_id  path   int  true   "교환일기방 ID"  Format(uint)
+// @Param        limit    query  int  false  "limit"  default(10)
+// @Param        offset   query  int  false  "offset"  default(0)
+// @Param        cursor   query  int  false  "이전 페이지의 nextCursor"
+// @Success      200  {object}   listResponseDiary
+// @Failure      400
+// @Failure      401
+// @Router       /rooms/{room_id}/diaries [get]
+// @Security ApiKeyAuth
+func (dc *diaryController) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		roomID, err := application.ParseUint(c.Param("room_id"))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		limit, offset, err := application.GetValidLimitAndOffset(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		cursor, err := application.ParseUint(c.Query("cursor"))
+		if err != nil {
+			cursor = 0
+		}
+
+		room, err := dc.roomService.Get(roomID, entity.Ignore)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+		if !room.IsAlreadyJoined(currentMember.ID) {
+			c.JSON(http.StatusUnauthorized, "Only member or master can access")
+			return
+		}
+
+		diaries, err := dc.diaryService.GetAll(roomID, limit, offset, cursor)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+		if diaries, err = dc.reactionService.PopulateReactions(diaries); err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		res := listResponseDiary{Diaries: []summaryResponseDiary{}}
+		for _, diary := range *diaries {
+			summary := summaryResponseDiary{
+				ID:        diary.ID,
+				Turn:      diary.Turn,
+				Title:     diary.Title,
+				Summary:   diary.Summary(),
+				HasPhoto:  diary.HasPhoto(),
+				HasAudio:  diary.HasAudio(),
+				Reactions: toResponseReactions(diary.Reactions),
+				CreatedAt: diary.CreatedAt,
+			}
+			if diary.Author != nil {
+				summary.Author = toResponseMember(diary.Author)
+			}
+			res.Diaries = append(res.Diaries, summary)
+		}
+		if len(*diaries) > 0 && uint(len(*diaries)) == limit {
+			res.NextCursor = (*diaries)[len(*diaries)-1].ID
+		}
+		c.JSON(http.StatusOK, res)
+	}
+}
+
+// @Summary      get a diary
+// @Description  교환일기 상세
+// @Description  * reactions는 emoji별 반응 수, myReaction은 내가 남긴 반응이다.
+// @Tags         diaries
+// @Accept       json
+// @Produce      json
+// @Param        room_id   path  int  true  "교환일기방 ID"  Format(uint)
+// @Param        diary_id  path  int  true  "교환일기 ID"  Format(uint)
+// @Success      200  {object}   responseDiary
+// @Failure      400
+// @Failure      401
+// @Router       /rooms/{room_id}/diaries/{diary_id} [get]
+// @Security ApiKeyAuth
+func (dc *diaryController) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		diary, ok := dc.getJoinedRoomDiary(c)
+		if !ok {
+			return
+		}
+		diary, err := dc.reactionService.PopulateReaction(diary)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		res := toResponseDiary(diary)
+		reaction, err := dc.reactionService.GetMine(diary.ID, currentMember.ID)
+		switch {
+		case err == nil:
+			res.MyReaction = string(reaction.Emoji)
+		case !errors.Is(err, entity.ErrReactionNotFound):
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.JSON(http.StatusOK, res)
+	}
+}
+
+type postRequestDiary struct {
+	Title      string `json:"title" example:"고영희의 하루"`
+	Body       string `json:"body" example:"오늘은 풀을 뜯어 먹었다."`
+	PhotoUUID  string `json:"photoUUID,omitempty" example:"e4947e0c-490b-4588-a14d-e74dd3b8371f"`
+	PhotoURL   string `json:"photoURL,omitempty"`
+	AudioUUID  string `json:"audioUUID,omitempty" example:"ad5bb198-942f-4ddf-a248-3aaa4bba3b9b"`
+	AudioURL   string `json:"audioURL,omitempty"`
+	AudioTitle string `json:"audioTitle,omitempty" example:"LastDayOnEarth"`
+	AudioPitch string `json:"audioPitch,omitempty" example:"1.5"`
+}
+
+func (p *postRequestDiary) ToEntity(roomID, authorID uint) (*entity.Diary, error) {
+	diary, err := entity.NewDiary(roomID, authorID, p.Title, p.Body)
+	if err != nil {
+		return nil, err
+	}
+	if p.PhotoUUID != "" {
+		diary.AttachPhoto(p.PhotoUUID, p.PhotoURL)
+	}
+	if p.AudioUUID != "" {
+		diary.AttachAudio(p.AudioUUID, p.AudioURL, p.AudioTitle, p.AudioPitch)
+	}
+	return diary, nil
+}
+
+// @Summary      post a diary
+// @Description  교환일기 작성
+// @Description  * 현재 작성 차례인 멤버만 작성할 수 있다.
+// @Description  * 사진/음성은 files api로 먼저 업로드한 뒤, uuid와 url을 함께 전달한다.
+// @Description  * 작성이 완료되면 멤버들에게 새글 알림이 전송되고, 다음 턴으로 넘어간다.
+// @Description  * 보관된(archived) 교환일기방에는 작성할 수 없다. (409)
+// @Tags         diaries
+// @Accept       json
+// @Produce      json
+// @Param        room_id  path  int  true  "교환일기방 ID"  Format(uint)
+// @Param        diary  body  postRequestDiary  true  "교환일기 작성 요청 body"
+// @Success      201  {object}   responseDiary
+// @Failure      400
+// @Failure      401
+// @Failure      409
+// @Router       /rooms/{room_id}/diaries [post]
+// @Security ApiKeyAuth
+func (dc *diaryController) Post() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		roomID, err := application.ParseUint(c.Param("room_id"))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		var req postRequestDiary
+		if err := c.BindJSON(&req); err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		diary, err := req.ToEntity(roomID, currentMember.ID)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		diary, err = dc.diaryService.Create(diary)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(diaryErrorStatus(err), err.Error())
+			return
+		}
+
+		// register MemberPostedDiaryCode callback task
+		// the diary is already posted, so a failure is only logged. (the turn is passed by ROOM_PERIOD_FIN task anyway)
+		if _, err := dc.taskService.RegisterMemberPostedDiaryTask(roomID, application.GetTaskCallbackURL(c)); err != nil {
+			logger.Error(err.Error())
+		}
+		c.JSON(http.StatusCreated, toResponseDiary(diary))
+	}
+}
+
+// @Summary      update a diary
+// @Description  교환일기 수정
+// @Description  * 한번 작성된 교환일기는 수정할 수 없다. 항상 409를 응답한다.
+// @Tags         diaries
+// @Accept       json
+// @Produce      json
+// @Param        room_id   path  int  true  "교환일기방 ID"  Format(uint)
+// @Param        diary_id  path  int  true  "교환일기 ID"  Format(uint)
+// @Failure      400
+// @Failure      401
+// @Failure      409
+// @Router       /rooms/{room_id}/diaries/{diary_id} [patch]
+// @Security ApiKeyAuth
+func (dc *diaryController) Patch() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		diary, ok := dc.getJoinedRoomDiary(c)
+		if !ok {
+			return
+		}
+		if _, err := dc.diaryService.Update(diary); err != nil {
+			logger.Error(err.Error())
+			c.JSON(diaryErrorStatus(err), err.Error())
+			return
+		}
+		c.JSON(http.StatusOK, toResponseDiary(diary))
+	}
+}
+
+// @Summary      delete a diary
+// @Description  교환일기 삭제
+// @Description  * 한번 작성된 교환일기는 삭제할 수 없다. 항상 409를 응답한다.
+// @Tags         diaries
+// @Accept       json
+// @Produce      json
+// @Param        room_id   path  int  true  "교환일기방 ID"  Format(uint)
+// @Param        diary_id  path  int  true  "교환일기 ID"  Format(uint)
+// @Failure      400
+// @Failure      401
+// @Failure      409
+// @Router       /rooms/{room_id}/diaries/{diary_id} [delete]
+// @Security ApiKeyAuth
+func (dc *diaryController) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		diary, ok := dc.getJoinedRoomDiary(c)
+		if !ok {
+			return
+		}
+		if err := dc.diaryService.Delete(diary); err != nil {
+			logger.Error(err.Error())
+			c.JSON(diaryErrorStatus(err), err.Error())
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// @Summary      read a diary
+// @Description  교환일기 읽음 처리
+// @Description  * 해당 교환일기까지 읽은 것으로 처리되며, 교환일기방의 unreadCount가 갱신된다.
+// @Description  * 이전 교환일기를 읽더라도 읽음 위치는 뒤로 돌아가지 않는다.
+// @Tags         diaries
+// @Accept       json
+// @Produce      json
+// @Param        room_id   path  int  true  "교환일기방 ID"  Format(uint)
+// @Param        diary_id  path  int  true  "교환일기 ID"  Format(uint)
+// @Success      204
+// @Failure      400
+// @Failure      401
+// @Router       /rooms/{room_id}/diaries/{diary_id}/read [post]
+// @Security ApiKeyAuth
+func (dc *diaryController) Read() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		diary, ok := dc.getJoinedRoomDiary(c)
+		if !ok {
+			return
+		}
+		if err := dc.diaryService.MarkAsRead(diary, currentMember.ID); err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+type reactRequestDiary struct {
+	Emoji string `json:"emoji" example:"HEART" enums:"HEART,LAUGH,SAD,SURPRISE,THUMBS_UP"`
+}
+
+type reactResponseDiary struct {
+	MyReaction string          `json:"myReaction,omitempty"`
+	Reactions  map[string]uint `json:"reactions"`
+}
+
+// @Summary      react on a diary
+// @Description  교환일기에 emoji로 반응한다. 멤버당 하나의 반응만 남길 수 있다.
+// @Description  * 같은 emoji로 다시 요청하면 반응이 취소된다.
+// @Description  * 다른 emoji로 요청하면 반응이 변경된다.
+// @Description  * 새로 반응한 경우, 작성자에게 알림이 전송된다. (작성자의 alarm_flag가 켜진 경우)
+// @Tags         diaries
+// @Accept       json
+// @Produce      json
+// @Param        room_id   path  int                true  "교환일기방 ID"  Format(uint)
+// @Param        diary_id  path  int                true  "교환일기 ID"  Format(uint)
+// @Param        reaction  body  reactRequestDiary  true  "반응 요청 body"
+// @Success      200  {object}   reactResponseDiary
+// @Failure      400
+// @Failure      401
+// @Router       /rooms/{room_id}/diaries/{diary_id}/reactions [put]
+// @Security ApiKeyAuth
+func (dc *diaryController) React() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		var req reactRequestDiary
+		if err := c.BindJSON(&req); err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		emoji, err := entity.ParseEmoji(req.Emoji)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		diary, ok := dc.getJoinedRoomDiary(c)
+		if !ok {
+			return
+		}
+
+		reaction, err := dc.reactionService.Toggle(diary, currentMember.ID, emoji)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+		if diary, err = dc.reactionService.PopulateReaction(diary); err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		res := reactResponseDiary{Reactions: toResponseReactions(diary.Reactions)}
+		if reaction != nil {
+			res.MyReaction = string(reaction.Emoji)
+		}
+		c.JSON(http.StatusOK, res)
+	}
+}
+
+func (dc *diaryController) getJoinedRoomDiary(c *gin.Context) (diary *entity.Diary, ok bool) {
+	return getJoinedRoomDiary(c, dc.roomService, dc.diaryService)
+}
+
+const searchDateLayout = "2006-01-02"
+
+type searchResponseDiary struct {
+	ID        uint            `json:"id"`
+	RoomID    uint            `json:"roomId"`
+	Turn      uint            `json:"turn"`
+	Title     string          `json:"title"`
+	Snippet   string          `json:"snippet"`
+	Author    *responseMember `json:"author"`
+	CreatedAt *time.Time      `json:"createdAt"`
+}
+
+type listSearchResponseDiary struct {
+	Diaries []searchResponseDiary `json:"diaries"`
+}
+
+// @Summary      Search diaries
+// @Description  참여중인 모든 교환일기방의 교환일기를 제목/본문으로 검색한다. (관련도순)
+// @Description  * 검색어는 공백으로 구분되며, 모든 검색어를 포함한 교환일기만 검색된다.
+// @Description  * title, snippet의 검색어는 <em> 태그로 감싸지며, 나머지는 html escape 된다.
+// @Description  * from, to는 YYYY-MM-DD 형식이며 둘 다 포함된다.
+// @Tags         diaries
+// @Accept       json
+// @Produce      json
+// @Param        q          query  string  true   "검색어"
+// @Param        room_id    query  int     false  "교환일기방 ID"  Format(uint)
+// @Param        author_id  query  int     false  "작성자 ID"  Format(uint)
+// @Param        from       query  string  false  "작성일 시작 (YYYY-MM-DD)"
+// @Param        to         query  string  false  "작성일 끝 (YYYY-MM-DD)"
+// @Param        limit      query  int     false  "limit (1 ~ 100)"  default(10)
+// @Param        offset     query  int     false  "offset"  default(0)
+// @Success      200  {object}   listSearchResponseDiary
+// @Failure      400
+// @Failure      401
+// @Router       /diaries/search [get]
+// @Security ApiKeyAuth
+func (dc *diaryController) Search() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		cond, roomID, err := parseSearchQuery(c)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		diaries, err := dc.diaryService.Search(currentMember.ID, roomID, cond)
+		if err != nil {
+			logger.Error(err.Error())
+			if errors.Is(err, entity.ErrNotJoinedRoom) {
+				c.JSON(http.StatusUnauthorized, err.Error())
+				return
+			}
+			c.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		terms := cond.Terms()
+		res := listSearchResponseDiary{Diaries: []searchResponseDiary{}}
+		for _, diary := range *diaries {
+			title, snippet := diary.Highlight(terms)
+			result := searchResponseDiary{
+				ID:        diary.ID,
+				RoomID:    diary.RoomID,
+				Turn:      diary.Turn,
+				Title:     title,
+				Snippet:   snippet,
+				CreatedAt: diary.CreatedAt,
+			}
+			if diary.Author != nil {
+				result.Author = toResponseMember(diary.Author)
+			}
+			res.Diaries = append(res.Diaries, result)
+		}
+		c.JSON(http.StatusOK, res)
+	}
+}
+
+func parseSearchQuery(c *gin.Context) (cond vo.DiarySearchVO, roomID uint, err error) {
+	cond.Keyword = strings.TrimSpace(c.Query("q"))
+	if cond.Keyword == "" {
+		return cond, 0, errors.New("q is required")
+	}
+	if cond.Limit, cond.Offset, err = application.GetValidLimitAndOffset(c); err != nil {
+		return cond, 0, err
+	}
+
+	if v := c.Query("room_id"); v != "" {
+		if roomID, err = application.ParseUint(v); err != nil {
+			return cond, 0, err
+		}
+	}
+	if v := c.Query("author_id"); v != "" {
+		if cond.AuthorID, err = application.ParseUint(v); err != nil {
+			return cond, 0, err
+		}
+	}
+	if v := c.Query("from"); v != "" {
+		from, err := time.Parse(searchDateLayout, v)
+		if err != nil {
+			return cond, 0, err
+		}
+		cond.From = &from
+	}
+	if v := c.Query("to"); v != "" {
+		to, err := time.Parse(searchDateLayout, v)
+		if err != nil {
+			return cond, 0, err
+		}
+		// to is inclusive
+		to = to.AddDate(0, 0, 1)
+		cond.To = &to
+	}
+	return cond, roomID, nil
+}
+
+// getJoinedRoomDiary returns a diary of path parameters, only if current member joined the room.
+// If it fails, error response is written and ok is false.
+func getJoinedRoomDiary(c *gin.Context, rs service.RoomService, ds service.DiaryService) (diary *entity.Diary, ok bool) {
+	currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+	roomID, err := application.ParseUint(c.Param("room_id"))
+	if err != nil {
+		logger.Error(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	diaryID, err := application.ParseUint(c.Param("diary_id"))
+	if err != nil {
+		logger.Error(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	room, err := rs.Get(roomID, entity.Ignore)
+	if err != nil {
+		logger.Error(err.Error())
+		c.JSON(http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	if !room.IsAlreadyJoined(currentMember.ID) {
+		c.JSON(http.StatusUnauthorized, "Only member or master can access")
+		return nil, false
+	}
+
+	diary, err = ds.Get(diaryID)
+	if err != nil {
+		logger.Error(err.Error())
+		c.JSON(http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	if diary.RoomID != room.ID {
+		c.JSON(http.StatusBadRequest, "Diary does not belong to the room")
+		return nil, false
+	}
+	return diary, true
+}
+
+// diaryErrorStatus maps diary domain error to http status code
+func diaryErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrNotDiaryTurn):
+		return http.StatusUnauthorized
+	case errors.Is(err, entity.ErrImmutableDiary), errors.Is(err, entity.ErrLockedAttachment), errors.Is(err, entity.ErrRoomArchived):
+		return http.StatusConflict
+	case errors.Is(err, entity.ErrDraftVersionConflict):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, entity.ErrDraftNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
@@ -0,0 +1,271 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/application"
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/service"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ifMatchHeader = "If-Match"
+	etagHeader    = "ETag"
+)
+
+// DiaryDraftController handles /v1/rooms/:room_id/diaries/draft api
+type DiaryDraftController interface {
+	Get() gin.HandlerFunc
+	Put() gin.HandlerFunc
+	Delete() gin.HandlerFunc
+	Publish() gin.HandlerFunc
+}
+
+type diaryDraftController struct {
+	diaryDraftService service.DiaryDraftService
+	diaryService      service.DiaryService
+	taskService       service.TaskService
+}
+
+// NewDiaryDraftController is a diaryDraftController's constructor
+func NewDiaryDraftController(dds service.DiaryDraftService, ds service.DiaryService, ts service.TaskService) DiaryDraftController {
+	return &diaryDraftController{
+		diaryDraftService: dds,
+		diaryService:      ds,
+		taskService:       ts,
+	}
+}
+
+type responseDiaryDraft struct {
+	RoomID     uint       `json:"roomId"`
+	Turn       uint       `json:"turn"`
+	Version    uint       `json:"version"`
+	Title      string     `json:"title"`
+	Body       string     `json:"body"`
+	PhotoUUID  string     `json:"photoUUID,omitempty"`
+	PhotoURL   string     `json:"photoURL,omitempty"`
+	AudioUUID  string     `json:"audioUUID,omitempty"`
+	AudioURL   string     `json:"audioURL,omitempty"`
+	AudioTitle string     `json:"audioTitle,omitempty"`
+	AudioPitch string     `json:"audioPitch,omitempty"`
+	UpdatedAt  *time.Time `json:"updatedAt"`
+}
+
+func toResponseDiaryDraft(draft *entity.DiaryDraft) responseDiaryDraft {
+	return responseDiaryDraft{
+		RoomID:     draft.RoomID,
+		Turn:       draft.Turn,
+		Version:    draft.Version,
+		Title:      draft.Title,
+		Body:       draft.Body,
+		PhotoUUID:  draft.PhotoUUID,
+		PhotoURL:   draft.PhotoURL,
+		AudioUUID:  draft.AudioUUID,
+		AudioURL:   draft.AudioURL,
+		AudioTitle: draft.AudioTitle,
+		AudioPitch: draft.AudioPitch,
+		UpdatedAt:  draft.UpdatedAt,
+	}
+}
+
+// @Summary      get a diary draft
+// @Description  현재 턴에 작성중인 교환일기 임시저장본
+// @Description  * 응답 헤더의 ETag를 임시저장(PUT) 요청의 If-Match 헤더로 전달해야 한다.
+// @Tags         diaries
+// @Accept       json
+// @Produce      json
+// @Param        room_id  path  int  true  "교환일기방 ID"  Format(uint)
+// @Success      200  {object}   responseDiaryDraft
+// @Failure      400
+// @Failure      401
+// @Failure      404
+// @Router       /rooms/{room_id}/diaries/draft [get]
+// @Security ApiKeyAuth
+func (ddc *diaryDraftController) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		roomID, err := application.ParseUint(c.Param("room_id"))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		draft, err := ddc.diaryDraftService.Get(roomID, currentMember.ID)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(diaryErrorStatus(err), err.Error())
+			return
+		}
+		c.Header(etagHeader, draft.ETag())
+		c.JSON(http.StatusOK, toResponseDiaryDraft(draft))
+	}
+}
+
+type putRequestDiaryDraft struct {
+	Title      string `json:"title" example:"고영희의 하루"`
+	Body       string `json:"body" example:"오늘은 풀을 뜯어 먹었다."`
+	PhotoUUID  string `json:"photoUUID,omitempty" example:"e4947e0c-490b-4588-a14d-e74dd3b8371f"`
+	PhotoURL   string `json:"photoURL,omitempty"`
+	AudioUUID  string `json:"audioUUID,omitempty" example:"ad5bb198-942f-4ddf-a248-3aaa4bba3b9b"`
+	AudioURL   string `json:"audioURL,omitempty"`
+	AudioTitle string `json:"audioTitle,omitempty" example:"LastDayOnEarth"`
+	AudioPitch string `json:"audioPitch,omitempty" example:"1.5"`
+}
+
+func (p *putRequestDiaryDraft) ToEntity(roomID, memberID, version uint) *entity.DiaryDraft {
+	draft := entity.NewDiaryDraft(roomID, memberID, 0)
+	draft.Version = version
+	draft.Title = p.Title
+	draft.Body = p.Body
+	draft.PhotoUUID = p.PhotoUUID
+	draft.PhotoURL = p.PhotoURL
+	draft.AudioUUID = p.AudioUUID
+	draft.AudioURL = p.AudioURL
+	draft.AudioTitle = p.AudioTitle
+	draft.AudioPitch = p.AudioPitch
+	return draft
+}
+
+// @Summary      autosave a diary draft
+// @Description  현재 턴에 작성중인 교환일기 임시저장 (현재 작성 차례인 멤버만 가능)
+// @Description  * 최초 저장시에는 If-Match 헤더 없이 요청한다.
+// @Description  * 이후에는 마지막으로 받은 ETag를 If-Match 헤더로 전달해야 하며, 다른 기기에서 먼저 저장한 경우 412를 응답한다.
+// @Description  * 사진/음성은 files api로 먼저 업로드한 뒤, uuid와 url을 함께 전달한다.
+// @Tags         diaries
+// @Accept       json
+// @Produce      json
+// @Param        room_id   path    int                   true   "교환일기방 ID"  Format(uint)
+// @Param        If-Match  header  string                false  "마지막으로 받은 ETag"
+// @Param        draft     body    putRequestDiaryDraft  true   "교환일기 임시저장 요청 body"
+// @Success      200  {object}   responseDiaryDraft
+// @Failure      400
+// @Failure      401
+// @Failure      409
+// @Failure      412
+// @Router       /rooms/{room_id}/diaries/draft [put]
+// @Security ApiKeyAuth
+func (ddc *diaryDraftController) Put() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		roomID, err := application.ParseUint(c.Param("room_id"))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		version, err := entity.ParseETag(c.GetHeader(ifMatchHeader))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		var req putRequestDiaryDraft
+		if err := c.BindJSON(&req); err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		draft, err := ddc.diaryDraftService.Save(req.ToEntity(roomID, currentMember.ID, version))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(diaryErrorStatus(err), err.Error())
+			return
+		}
+		c.Header(etagHeader, draft.ETag())
+		c.JSON(http.StatusOK, toResponseDiaryDraft(draft))
+	}
+}
+
+// @Summary      discard a diary draft
+// @Description  현재 턴에 작성중인 교환일기 임시저장본 삭제
+// @Tags         diaries
+// @Accept       json
+// @Produce      json
+// @Param        room_id  path  int  true  "교환일기방 ID"  Format(uint)
+// @Success      204
+// @Failure      400
+// @Failure      401
+// @Failure      404
+// @Router       /rooms/{room_id}/diaries/draft [delete]
+// @Security ApiKeyAuth
+func (ddc *diaryDraftController) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		roomID, err := application.ParseUint(c.Param("room_id"))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := ddc.diaryDraftService.Discard(roomID, currentMember.ID); err != nil {
+			logger.Error(err.Error())
+			c.JSON(diaryErrorStatus(err), err.Error())
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// @Summary      publish a diary draft
+// @Description  임시저장본을 교환일기로 작성한다. 임시저장본은 삭제된다.
+// @Description  * If-Match 헤더를 전달하면, 해당 버전의 임시저장본일 경우에만 작성된다.
+// @Tags         diaries
+// @Accept       json
+// @Produce      json
+// @Param        room_id   path    int     true   "교환일기방 ID"  Format(uint)
+// @Param        If-Match  header  string  false  "마지막으로 받은 ETag"
+// @Success      201  {object}   responseDiary
+// @Failure      400
+// @Failure      401
+// @Failure      404
+// @Failure      409
+// @Failure      412
+// @Router       /rooms/{room_id}/diaries/draft/publish [post]
+// @Security ApiKeyAuth
+func (ddc *diaryDraftController) Publish() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		roomID, err := application.ParseUint(c.Param("room_id"))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		version, err := entity.ParseETag(c.GetHeader(ifMatchHeader))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		draft, err := ddc.diaryDraftService.Get(roomID, currentMember.ID)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(diaryErrorStatus(err), err.Error())
+			return
+		}
+		if version != 0 && version != draft.Version {
+			c.JSON(http.StatusPreconditionFailed, entity.ErrDraftVersionConflict.Error())
+			return
+		}
+
+		diary, err := ddc.diaryService.Publish(draft)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(diaryErrorStatus(err), err.Error())
+			return
+		}
+
+		// register MemberPostedDiaryCode callback task
+		// the diary is already posted, so a failure is only logged. (the turn is passed by ROOM_PERIOD_FIN task anyway)
+		if _, err := ddc.taskService.RegisterMemberPostedDiaryTask(roomID, application.GetTaskCallbackURL(c)); err != nil {
+			logger.Error(err.Error())
+		}
+		c.JSON(http.StatusCreated, toResponseDiary(diary))
+	}
+}
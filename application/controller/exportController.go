@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/application"
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/service"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// ExportController handles /v1/rooms/:room_id/exports api
+type ExportController interface {
+	Get() gin.HandlerFunc
+	Post() gin.HandlerFunc
+}
+
+type exportController struct {
+	exportService service.ExportService
+}
+
+// NewExportController is a exportController's constructor
+func NewExportController(es service.ExportService) ExportController {
+	return &exportController{exportService: es}
+}
+
+type responseExport struct {
+	ID          uint       `json:"id"`
+	RoomID      uint       `json:"roomId"`
+	Status      string     `json:"status" enums:"PENDING,RUNNING,DONE,FAILED"`
+	URL         string     `json:"url,omitempty"`
+	DownloadURL string     `json:"downloadURL,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   *time.Time `json:"createdAt"`
+	FinishedAt  *time.Time `json:"finishedAt,omitempty"`
+}
+
+func toResponseExport(export *entity.Export) responseExport {
+	return responseExport{
+		ID:          export.ID,
+		RoomID:      export.RoomID,
+		Status:      string(export.Status),
+		URL:         export.URL,
+		DownloadURL: export.DownloadURL,
+		Error:       export.Error,
+		CreatedAt:   export.CreatedAt,
+		FinishedAt:  export.FinishedAt,
+	}
+}
+
+// @Summary      export a room
+// @Description  교환일기방의 모든 교환일기를 ZIP 파일로 내보낸다.
+// @Description  * 내보내기는 비동기로 진행되며, 완료되면 알림이 전송된다.
+// @Description  * 진행 상태는 GET /rooms/{room_id}/exports/{export_id}로 조회한다.
+// @Description  * 이미 진행중인 내보내기가 있다면, 해당 내보내기를 응답한다.
+// @Description  * 단, 30분 이상 끝나지 않은 내보내기는 FAILED 처리되고 새 내보내기가 시작된다.
+// @Description  * ZIP: manifest.json, diaries/{turn}.md, attachments/{turn}_{photo|audio}_{uuid}
+// @Tags         exports
+// @Accept       json
+// @Produce      json
+// @Param        room_id  path  int  true  "교환일기방 ID"  Format(uint)
+// @Success      202  {object}   responseExport
+// @Failure      400
+// @Failure      401
+// @Router       /rooms/{room_id}/exports [post]
+// @Security ApiKeyAuth
+func (ec *exportController) Post() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		roomID, err := application.ParseUint(c.Param("room_id"))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		export, err := ec.exportService.Request(roomID, currentMember.ID)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(exportErrorStatus(err), err.Error())
+			return
+		}
+		c.JSON(http.StatusAccepted, toResponseExport(export))
+	}
+}
+
+// @Summary      get an export
+// @Description  교환일기방 내보내기 진행 상태 (요청한 멤버만 조회 가능)
+// @Description  * status가 DONE이 되면 downloadURL로 ZIP 파일을 받을 수 있다.
+// @Tags         exports
+// @Accept       json
+// @Produce      json
+// @Param        room_id    path  int  true  "교환일기방 ID"  Format(uint)
+// @Param        export_id  path  int  true  "내보내기 ID"  Format(uint)
+// @Success      200  {object}   responseExport
+// @Failure      400
+// @Failure      404
+// @Router       /rooms/{room_id}/exports/{export_id} [get]
+// @Security ApiKeyAuth
+func (ec *exportController) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		roomID, err := application.ParseUint(c.Param("room_id"))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		exportID, err := application.ParseUint(c.Param("export_id"))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		export, err := ec.exportService.Get(exportID, currentMember.ID)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(exportErrorStatus(err), err.Error())
+			return
+		}
+		if export.RoomID != roomID {
+			c.JSON(http.StatusNotFound, entity.ErrExportNotFound.Error())
+			return
+		}
+		c.JSON(http.StatusOK, toResponseExport(export))
+	}
+}
+
+// exportErrorStatus maps export domain error to http status code
+func exportErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrNotJoinedRoom):
+		return http.StatusUnauthorized
+	case errors.Is(err, entity.ErrExportNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
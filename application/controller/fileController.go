@@ -1,10 +1,12 @@
 package controller
 
 import (
+	"errors"
 	"mime/multipart"
 	"net/http"
 
 	"github.com/ExchangeDiary/exchange-diary/application"
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
 	"github.com/ExchangeDiary/exchange-diary/domain/service"
 	"github.com/ExchangeDiary/exchange-diary/infrastructure/clients/google/cloudstorage"
 	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
@@ -12,8 +14,6 @@ import (
 	"github.com/gin-gonic/gin/binding"
 )
 
-const bucketName = "voda_bucket"
-
 // FileController handles /v1/files api
 type FileController interface {
 	// Get() gin.HandlerFunc
@@ -72,6 +72,7 @@ type filePostResponse struct {
 // @Param        audio  formData  file  false  "audio"
 // @Success      200  {object}   filePostResponse
 // @Failure      400
+// @Failure      409  "이미 작성된 교환일기에 첨부된 파일은 덮어쓸 수 없다."
 // @Failure      500
 // @Router       /rooms/{room_id}/files [post]
 // @Security ApiKeyAuth
@@ -104,7 +105,7 @@ func (fc *fileController) Post() gin.HandlerFunc {
 			vItem, err := fc.fileService.UploadFile(bkt, roomID, fileForm.AudioUUID, fileForm.Audio, service.AudioType)
 			if err != nil {
 				logger.Error(err.Error())
-				c.JSON(http.StatusInternalServerError, gin.H{
+				c.JSON(fileErrorStatus(err), gin.H{
 					"message": err.Error(),
 					"error":   true,
 				})
@@ -118,7 +119,7 @@ func (fc *fileController) Post() gin.HandlerFunc {
 			vItem, err := fc.fileService.UploadFile(bkt, roomID, fileForm.PhotoUUID, fileForm.Photo, service.PhotoType)
 			if err != nil {
 				logger.Error(err.Error())
-				c.JSON(http.StatusInternalServerError, gin.H{
+				c.JSON(fileErrorStatus(err), gin.H{
 					"message": err.Error(),
 					"error":   true,
 				})
@@ -131,8 +132,16 @@ func (fc *fileController) Post() gin.HandlerFunc {
 	}
 }
 
+// fileErrorStatus maps upload error to http status code
+func fileErrorStatus(err error) int {
+	if errors.Is(err, entity.ErrLockedAttachment) {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
 func (fc *fileController) getBucket() (*cloudstorage.VBucket, error) {
-	bkt, err := cloudstorage.GetClient().VBucket(bucketName)
+	bkt, err := cloudstorage.GetClient().VBucket(service.BucketName)
 	if err != nil {
 		return nil, err
 	}
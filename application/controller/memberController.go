@@ -7,6 +7,7 @@ import (
 
 	"github.com/ExchangeDiary/exchange-diary/domain/entity"
 	"github.com/ExchangeDiary/exchange-diary/domain/service"
+	"github.com/ExchangeDiary/exchange-diary/domain/vo"
 	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
 	"github.com/gin-gonic/gin"
 )
@@ -30,6 +31,11 @@ type memberRequest struct {
 	ProfileURL string `json:"profile_url,omitempty"`
 	AuthType   string `json:"auth_type,omitempty"`
 	AlarmFlag  bool   `json:"alarm_flag,omitempty"`
+	// reminder offsets (minutes before due time) which member does not want to be reminded at. empty array opts in every reminder.
+	ReminderOptOuts *[]uint `json:"reminder_opt_outs,omitempty" example:"30"`
+	// IANA time zone name
+	TimeZone   string             `json:"time_zone,omitempty" example:"Asia/Seoul"`
+	QuietHours *quietHoursRequest `json:"quiet_hours,omitempty"`
 }
 
 type memberResponse struct {
@@ -38,6 +44,41 @@ type memberResponse struct {
 	ProfileURL string `json:"profile_url,omitempty"`
 	AuthType   string `json:"auth_type"`
 	AlarmFlag  bool   `json:"alarm_flag,omitempty"`
+	// minutes
+	ReminderOptOuts []uint              `json:"reminder_opt_outs"`
+	TimeZone        string              `json:"time_zone" example:"Asia/Seoul"`
+	QuietHours      *quietHoursResponse `json:"quiet_hours,omitempty"`
+}
+
+// quietHoursRequest is HH:MM formatted local time window when non-urgent pushes are deferred.
+// start equal to end disables quiet hours.
+type quietHoursRequest struct {
+	Start string `json:"start" example:"23:00"`
+	End   string `json:"end" example:"08:00"`
+}
+
+type quietHoursResponse struct {
+	Start string `json:"start" example:"23:00"`
+	End   string `json:"end" example:"08:00"`
+}
+
+func toMemberResponse(member *entity.Member) memberResponse {
+	response := memberResponse{
+		Email:           member.Email,
+		Name:            member.Name,
+		ProfileURL:      member.ProfileURL,
+		AuthType:        member.AuthType,
+		AlarmFlag:       member.AlarmFlag,
+		ReminderOptOuts: entity.DurationsToMinutes(member.ReminderOptOuts),
+		TimeZone:        member.Location().String(),
+	}
+	if member.QuietHours.IsEnabled() {
+		response.QuietHours = &quietHoursResponse{
+			Start: member.QuietHours.StartClock(),
+			End:   member.QuietHours.EndClock(),
+		}
+	}
+	return response
 }
 
 // NewMemberController ...
@@ -65,13 +106,7 @@ func (mc *memberController) Get() gin.HandlerFunc {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		response := memberResponse{
-			Email:      member.Email,
-			Name:       member.Name,
-			ProfileURL: member.ProfileURL,
-			AuthType:   member.AuthType,
-			AlarmFlag:  member.AlarmFlag,
-		}
+		response := toMemberResponse(member)
 		c.JSON(http.StatusOK, response)
 	}
 }
@@ -101,19 +136,15 @@ func (mc *memberController) Post() gin.HandlerFunc {
 			c.JSON(http.StatusBadRequest, err.Error())
 			return
 		}
-		response := memberResponse{
-			Email:      member.Email,
-			Name:       member.Name,
-			ProfileURL: member.ProfileURL,
-			AuthType:   member.AuthType,
-			AlarmFlag:  member.AlarmFlag,
-		}
+		response := toMemberResponse(member)
 		c.JSON(http.StatusCreated, response)
 	}
 }
 
 // @Summary Member 수정
 // @Description	 해당 member를 수정한다.
+// @Description  * reminder_opt_outs: 받지 않을 마감 전 알림 시간(분) array. 모든 교환일기방에 적용된다.
+// @Description  * time_zone, quiet_hours: 방해금지 시간(time_zone 기준 HH:MM)에는 마감 전 알림을 제외한 알림이 방해금지 시간이 끝난 뒤에 전송된다. (알림 목록에는 바로 추가된다.)
 // @Tags         members
 // @Accept       json
 // @Produce      json
@@ -136,24 +167,24 @@ func (mc *memberController) Patch() gin.HandlerFunc {
 			c.JSON(http.StatusBadRequest, err.Error())
 			return
 		}
-		patchedMember, err := mc.memberService.Update(patchMember(member, request))
+		member, err = patchMember(member, request)
 		if err != nil {
 			logger.Error(err.Error())
-			c.JSON(http.StatusInternalServerError, err.Error())
+			c.JSON(http.StatusBadRequest, err.Error())
 			return
 		}
-		response := memberResponse{
-			Email:      patchedMember.Email,
-			Name:       patchedMember.Name,
-			ProfileURL: patchedMember.ProfileURL,
-			AuthType:   patchedMember.AuthType,
-			AlarmFlag:  patchedMember.AlarmFlag,
+		patchedMember, err := mc.memberService.Update(member)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusInternalServerError, err.Error())
+			return
 		}
+		response := toMemberResponse(patchedMember)
 		c.JSON(http.StatusOK, response)
 	}
 }
 
-func patchMember(original *entity.Member, patch memberRequest) *entity.Member {
+func patchMember(original *entity.Member, patch memberRequest) (*entity.Member, error) {
 	isIdentical := true
 	if patch.Name != "" {
 		original.Name = patch.Name
@@ -167,10 +198,28 @@ func patchMember(original *entity.Member, patch memberRequest) *entity.Member {
 		original.AlarmFlag = patch.AlarmFlag
 		isIdentical = false
 	}
+	if patch.ReminderOptOuts != nil {
+		original.ReminderOptOuts = entity.MinutesToDurations(*patch.ReminderOptOuts)
+		isIdentical = false
+	}
+	if patch.TimeZone != "" {
+		if err := original.SetTimeZone(patch.TimeZone); err != nil {
+			return nil, err
+		}
+		isIdentical = false
+	}
+	if patch.QuietHours != nil {
+		quietHours, err := vo.ParseQuietHours(patch.QuietHours.Start, patch.QuietHours.End)
+		if err != nil {
+			return nil, err
+		}
+		original.QuietHours = quietHours
+		isIdentical = false
+	}
 	if isIdentical == false {
 		original.UpdatedAt = time.Now()
 	}
-	return original
+	return original, nil
 }
 
 // @Summary Member 삭제
@@ -1,13 +1,13 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/ExchangeDiary/exchange-diary/application"
 	"github.com/ExchangeDiary/exchange-diary/domain/entity"
 	"github.com/ExchangeDiary/exchange-diary/domain/service"
-	"github.com/ExchangeDiary/exchange-diary/infrastructure/clients/google/tasks"
 	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
 	"github.com/gin-gonic/gin"
 )
@@ -22,18 +22,25 @@ type RoomController interface {
 	Join() gin.HandlerFunc
 	Leave() gin.HandlerFunc
 	GetOrders() gin.HandlerFunc
+	SkipTurn() gin.HandlerFunc
+	SetAway() gin.HandlerFunc
+	GetForm() gin.HandlerFunc
+	Archive() gin.HandlerFunc
+	Unarchive() gin.HandlerFunc
 }
 
 type roomController struct {
-	roomService service.RoomService
-	taskService service.TaskService
+	roomService        service.RoomService
+	taskService        service.TaskService
+	roomCatalogService service.RoomCatalogService
 }
 
 // NewRoomController is a roomController's constructor
-func NewRoomController(rs service.RoomService, ts service.TaskService) RoomController {
+func NewRoomController(rs service.RoomService, ts service.TaskService, rcs service.RoomCatalogService) RoomController {
 	return &roomController{
-		roomService: rs,
-		taskService: ts,
+		roomService:        rs,
+		taskService:        ts,
+		roomCatalogService: rcs,
 	}
 }
 
@@ -44,23 +51,81 @@ type responseMember struct {
 }
 
 type responseRoom struct {
-	ID        uint              `json:"id"`
-	Name      *string           `json:"name"`
-	Orders    []uint            `json:"orders"`
-	Members   *[]responseMember `json:"members"`
-	CreatedAt *time.Time        `json:"createdAt"`
-	UpdatedAt *time.Time        `json:"updatedAt"`
+	ID          uint              `json:"id"`
+	Name        *string           `json:"name"`
+	Theme       string            `json:"theme"`
+	Hashtag     string            `json:"hashtag,omitempty" example:"#새벽감성"`
+	Orders      []uint            `json:"orders"`
+	Members     *[]responseMember `json:"members"`
+	UnreadCount uint              `json:"unreadCount"`
+	DueAt       *time.Time        `json:"dueAt"`
+	LastDiaryAt *time.Time        `json:"lastDiaryAt,omitempty"`
+	EndAt       *time.Time        `json:"endAt,omitempty"`
+	ArchivedAt  *time.Time        `json:"archivedAt,omitempty"`
+	CreatedAt   *time.Time        `json:"createdAt"`
+	UpdatedAt   *time.Time        `json:"updatedAt"`
+}
+
+func (rc *roomController) toResponseRoom(room *entity.Room) responseRoom {
+	members := []responseMember{}
+	for _, member := range *room.Members {
+		members = append(members, responseMember{
+			ID:         member.ID,
+			NickName:   member.Name,
+			ProfileURL: member.ProfileURL,
+		})
+	}
+	res := responseRoom{
+		ID:          room.ID,
+		Name:        &room.Name,
+		Theme:       room.Theme,
+		Orders:      room.Orders,
+		Members:     &members,
+		UnreadCount: room.UnreadCount,
+		DueAt:       room.DueAt,
+		LastDiaryAt: room.LastDiaryAt,
+		EndAt:       room.EndAt,
+		ArchivedAt:  room.ArchivedAt,
+		CreatedAt:   room.CreatedAt,
+		UpdatedAt:   room.UpdatedAt,
+	}
+	if theme, ok := rc.roomCatalogService.Get().Theme(room.Theme); ok {
+		res.Hashtag = theme.Hashtag
+	}
+	return res
 }
 
 type listResponseRoom struct {
-	Rooms []responseRoom `json:"rooms"`
+	Rooms         []responseRoom `json:"rooms"`
+	ArchivedRooms []responseRoom `json:"archivedRooms"`
+}
+
+type responseRoomGroup struct {
+	Theme responseRoomTheme `json:"theme"`
+	Count int               `json:"count"`
+	Rooms []responseRoom    `json:"rooms"`
+}
+
+type groupedListResponseRoom struct {
+	Groups        []responseRoomGroup `json:"groups"`
+	ArchivedRooms []responseRoom      `json:"archivedRooms"`
 }
 
+// roomListGroupByTheme is a value of group query which buckets rooms by theme
+const roomListGroupByTheme = "theme"
+
 // @Summary      List rooms
 // @Description  참여중인 교환일기방 리스트
+// @Description  * theme: 해당 테마(GET /rooms/form 의 theme id)의 방만 조회
+// @Description  * sort: updated(default, 최근 수정순), last_diary(최근 일기 작성순), due_at(마감 임박순), created(최근 생성순)
+// @Description  * group=theme: 테마별로 묶어서 {groups: [{theme, count, rooms}]} 형태로 반환 (테마 순서는 GET /rooms/form 순서)
+// @Description  * 보관된(archived) 방은 rooms/groups에 포함되지 않고 archivedRooms로 따로 반환된다. (같은 theme, sort 적용)
 // @Tags         rooms
 // @Accept       json
 // @Produce      json
+// @Param        theme  query  string  false  "theme id"
+// @Param        sort   query  string  false  "updated | last_diary | due_at | created"  default(updated)
+// @Param        group  query  string  false  "theme"
 // @Success      200  {object}   listResponseRoom
 // @Failure      400
 // @Router       /rooms [get]
@@ -68,39 +133,76 @@ type listResponseRoom struct {
 func (rc *roomController) GetAll() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
-		rooms, err := rc.roomService.GetAllJoinedRooms(currentMember.ID)
+		option, err := parseRoomListOption(c)
 		if err != nil {
 			logger.Error(err.Error())
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		roomsResponse := []responseRoom{}
-		for _, room := range *rooms {
-			members := []responseMember{}
-			for _, member := range *room.Members {
-				members = append(members, responseMember{
-					ID:         member.ID,
-					NickName:   member.Name,
-					ProfileURL: member.ProfileURL,
+		group := c.Query("group")
+		if group != "" && group != roomListGroupByTheme {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "group must be theme"})
+			return
+		}
+
+		rooms, err := rc.roomService.GetAllJoinedRooms(currentMember.ID, option)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		active, archived := rooms.SplitArchived()
+		archivedResponse := []responseRoom{}
+		for i := range archived {
+			archivedResponse = append(archivedResponse, rc.toResponseRoom(&archived[i]))
+		}
+
+		if group == roomListGroupByTheme {
+			res := groupedListResponseRoom{Groups: []responseRoomGroup{}, ArchivedRooms: archivedResponse}
+			for _, g := range rc.roomCatalogService.GroupRooms(active) {
+				roomsResponse := []responseRoom{}
+				for i := range g.Rooms {
+					roomsResponse = append(roomsResponse, rc.toResponseRoom(&g.Rooms[i]))
+				}
+				res.Groups = append(res.Groups, responseRoomGroup{
+					Theme: responseRoomTheme{
+						ID:            g.Theme.ID,
+						Name:          g.Theme.Name,
+						Hashtag:       g.Theme.Hashtag,
+						BackgroundURL: g.Theme.BackgroundURL,
+					},
+					Count: len(roomsResponse),
+					Rooms: roomsResponse,
 				})
 			}
-			roomsResponse = append(roomsResponse, responseRoom{
-				ID:        room.ID,
-				Name:      &room.Name,
-				Orders:    room.Orders,
-				Members:   &members,
-				CreatedAt: room.CreatedAt,
-				UpdatedAt: room.UpdatedAt,
-			})
+			c.JSON(http.StatusOK, res)
+			return
+		}
+
+		roomsResponse := []responseRoom{}
+		for i := range active {
+			roomsResponse = append(roomsResponse, rc.toResponseRoom(&active[i]))
 		}
-		c.JSON(http.StatusOK, listResponseRoom{Rooms: roomsResponse})
+		c.JSON(http.StatusOK, listResponseRoom{Rooms: roomsResponse, ArchivedRooms: archivedResponse})
+	}
+}
+
+// parseRoomListOption reads theme and sort query.
+// theme is not validated against the catalog, so that rooms of a theme removed from the catalog can be filtered too.
+func parseRoomListOption(c *gin.Context) (entity.RoomListOption, error) {
+	sort, err := entity.ParseRoomSort(c.Query("sort"))
+	if err != nil {
+		return entity.RoomListOption{}, err
 	}
+	return entity.RoomListOption{Theme: c.Query("theme"), Sort: sort}, nil
 }
 
 type detailResponseRoom struct {
 	ID            uint              `json:"id"`
 	Name          *string           `json:"name"`
 	Members       *[]responseMember `json:"members"`
+	UnreadCount   uint              `json:"unreadCount"`
 	CreatedAt     *time.Time        `json:"createdAt"`
 	UpdatedAt     *time.Time        `json:"updatedAt"`
 	Theme         *string           `json:"theme,omitempty"`
@@ -109,6 +211,14 @@ type detailResponseRoom struct {
 	Code          *string           `json:"code,omitempty"`
 	Hint          *string           `json:"hint,omitempty"`
 	IsMaster      bool              `json:"isMaster,omitempty"`
+	// minutes before due time when turn member is reminded
+	ReminderOffsets []uint `json:"reminderOffsets" example:"240,60"`
+	// current member is skipped on turn rotation until this time
+	AwayUntil *time.Time `json:"awayUntil,omitempty"`
+	// the room is archived at this time
+	EndAt *time.Time `json:"endAt,omitempty"`
+	// archived room is read-only. (no turn rotation, diary and join)
+	ArchivedAt *time.Time `json:"archivedAt,omitempty"`
 }
 
 // @Summary      get a room
@@ -141,6 +251,11 @@ func (rc *roomController) Get() gin.HandlerFunc {
 			c.JSON(http.StatusUnauthorized, "Only member or master can access")
 			return
 		}
+		if room, err = rc.roomService.PopulateUnreadCount(room, currentMember.ID); err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
 
 		members := []responseMember{}
 		for _, member := range *room.Members {
@@ -151,15 +266,22 @@ func (rc *roomController) Get() gin.HandlerFunc {
 			})
 		}
 		res := detailResponseRoom{
-			ID:            room.ID,
-			Name:          &room.Name,
-			Theme:         &room.Theme,
-			Period:        room.Period,
-			Members:       &members,
-			TurnAccountID: room.TurnAccountID,
-			CreatedAt:     room.CreatedAt,
-			UpdatedAt:     room.UpdatedAt,
-			IsMaster:      room.IsMaster(currentMember.ID),
+			ID:              room.ID,
+			Name:            &room.Name,
+			Theme:           &room.Theme,
+			Period:          room.Period,
+			Members:         &members,
+			TurnAccountID:   room.TurnAccountID,
+			UnreadCount:     room.UnreadCount,
+			CreatedAt:       room.CreatedAt,
+			UpdatedAt:       room.UpdatedAt,
+			IsMaster:        room.IsMaster(currentMember.ID),
+			ReminderOffsets: entity.DurationsToMinutes(room.ReminderOffsets),
+			EndAt:           room.EndAt,
+			ArchivedAt:      room.ArchivedAt,
+		}
+		if until, ok := room.AwayUntil[currentMember.ID]; ok && time.Now().Before(until) {
+			res.AwayUntil = &until
 		}
 		c.JSON(http.StatusOK, res)
 	}
@@ -222,8 +344,10 @@ type postRequestRoom struct {
 	Name   string `json:"name" example:"고영희방"`
 	Code   string `json:"code" example:"제민욱"`
 	Hint   string `json:"hint" example:"레오의 본명은?"`
-	Period uint8  `json:"period" example:"5"`
-	Theme  string `json:"theme" example:"1"`
+	Period uint8  `json:"period" binding:"required" example:"5"` // one of GET /rooms/form periods
+	Theme  string `json:"theme" binding:"required" example:"1"`  // one of GET /rooms/form theme ids
+	// the room is archived at this time. (omit for no end)
+	EndAt *time.Time `json:"endAt,omitempty" example:"2022-12-31T00:00:00+09:00"`
 }
 
 type postResponseRoom struct {
@@ -232,6 +356,8 @@ type postResponseRoom struct {
 
 // @Summary      create a room
 // @Description  교환일기방 생성
+// @Description  * theme, period는 GET /rooms/form 에서 내려준 값 중 하나여야 한다.
+// @Description  * endAt을 지정하면 해당 시각에 교환일기방이 보관(archived)된다. 첫 작성 마감시각 이후여야 한다.
 // @Tags         rooms
 // @Accept       json
 // @Produce      json
@@ -249,7 +375,12 @@ func (rc *roomController) Post() gin.HandlerFunc {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		room, err := rc.roomService.Create(currentMember.ID, req.Name, req.Code, req.Hint, req.Theme, req.Period)
+		if err := rc.roomCatalogService.Validate(req.Theme, req.Period); err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+		room, err := rc.roomService.Create(currentMember.ID, req.Name, req.Code, req.Hint, req.Theme, req.Period, req.EndAt)
 		if err != nil {
 			logger.Error(err.Error())
 			c.JSON(http.StatusBadRequest, err.Error())
@@ -257,18 +388,19 @@ func (rc *roomController) Post() gin.HandlerFunc {
 		}
 
 		// register RoomPeriodFinCode callback task
-		taskClient := tasks.GetClient()
-		if _, err := rc.taskService.RegisterRoomPeriodFINTask(
-			taskClient,
-			application.GetCurrentURL(c),
-			room.ID,
-			room.TurnAccountID,
-			room.DueAt,
-		); err != nil {
+		if _, err := rc.taskService.RegisterRoomPeriodFINTask(application.GetTaskCallbackURL(c), room); err != nil {
 			logger.Error(err.Error())
 			c.JSON(http.StatusBadRequest, err.Error())
 			return
 		}
+		// register RoomEndCode callback task
+		if room.EndAt != nil {
+			if _, err := rc.taskService.RegisterRoomEndTask(application.GetTaskCallbackURL(c), room); err != nil {
+				logger.Error(err.Error())
+				c.JSON(http.StatusBadRequest, err.Error())
+				return
+			}
+		}
 
 		res := postResponseRoom{RoomID: room.ID}
 		c.JSON(http.StatusOK, res)
@@ -278,17 +410,25 @@ func (rc *roomController) Post() gin.HandlerFunc {
 type patchRequestRoom struct {
 	Code   string `json:"code,omitempty"`
 	Hint   string `json:"hint,omitempty"`
+	Theme  string `json:"theme,omitempty"`
 	Period uint8  `json:"period,omitempty"`
 	Orders []uint `json:"orders,omitempty"`
+	// minutes before due time. (ex. [1440, 240, 30] : 24h, 4h, 30m) empty array turns off reminders.
+	ReminderOffsets *[]uint `json:"reminderOffsets,omitempty"`
 }
 
-func (p *patchRequestRoom) ToEntity(room *entity.Room) *entity.Room {
+func (p *patchRequestRoom) ToEntity(room *entity.Room) (*entity.Room, error) {
 	if p.Code != "" {
-		room.Code = p.Code
+		if err := room.SetCode(p.Code); err != nil {
+			return nil, err
+		}
 	}
 	if p.Hint != "" {
 		room.Hint = p.Hint
 	}
+	if p.Theme != "" {
+		room.Theme = p.Theme
+	}
 	if p.Period != 0 {
 		// update DueAt, if period is changed
 		// it will applied next turn!
@@ -298,15 +438,29 @@ func (p *patchRequestRoom) ToEntity(room *entity.Room) *entity.Room {
 		room.DueAt = &newDueAt
 	}
 	if p.Orders != nil {
-		room.Orders = p.Orders
+		room.SetOrders(p.Orders)
 	}
-	return room
+	if p.ReminderOffsets != nil {
+		if err := room.SetReminderOffsets(entity.MinutesToDurations(*p.ReminderOffsets)); err != nil {
+			return nil, err
+		}
+	} else if p.isPeriodChanged() {
+		// offsets must be shorter than the new period
+		if err := room.SetReminderOffsets(room.ReminderOffsets); err != nil {
+			return nil, err
+		}
+	}
+	return room, nil
 }
 
 func (p *patchRequestRoom) isPeriodChanged() bool {
 	return p.Period != 0
 }
 
+func (p *patchRequestRoom) isReminderChanged() bool {
+	return p.ReminderOffsets != nil
+}
+
 type patchResponseRoom struct {
 	RoomID uint `json:"roomId"`
 }
@@ -314,8 +468,12 @@ type patchResponseRoom struct {
 // @Summary      update a room
 // @Description  교환일기방 업데이트 (master only)
 // @Description  1. 작성주기 변경 (period)
-// @Description  2. 코드/힌트 변경 (code, hint)
+// @Description  2. 코드/힌트/테마 변경 (code, hint, theme)
+// @Description     theme, period는 GET /rooms/form 에서 내려준 값 중 하나여야 한다.
 // @Description  3. 작성순서 변경(orders) : member id를 array로 넣어주면 된다.
+// @Description  4. 알림 시간 변경(reminderOffsets) : 마감 몇 분 전에 알림을 보낼지 분 단위 array로 넣어주면 된다. (최대 5개, 작성주기보다 짧아야 한다.)
+// @Description     현재 턴의 알림도 바로 변경된다.
+// @Description  * 보관된(archived) 교환일기방은 수정할 수 없다. (409)
 // @Tags         rooms
 // @Accept       json
 // @Produce      json
@@ -323,6 +481,7 @@ type patchResponseRoom struct {
 // @Param        room  body 	patchRequestRoom  true "교환일기방 수정 요청 body"
 // @Success      200  {object}   patchResponseRoom
 // @Failure      400
+// @Failure      409
 // @Router       /rooms/{id} [patch]
 // @Security ApiKeyAuth
 func (rc *roomController) Patch() gin.HandlerFunc {
@@ -353,8 +512,29 @@ func (rc *roomController) Patch() gin.HandlerFunc {
 			c.JSON(http.StatusUnauthorized, "Only master can patch room")
 			return
 		}
+		if room.IsArchived() {
+			c.JSON(http.StatusConflict, entity.ErrRoomArchived.Error())
+			return
+		}
 
-		_, err = rc.roomService.Update(req.ToEntity(room))
+		if err := rc.roomCatalogService.Validate(req.Theme, req.Period); err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+		patched, err := req.ToEntity(room)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+		// reminder tasks of current turn are replaced in the same transaction with the room update.
+		// the request is patched again on the locked room, since reminders are compared with the saved offsets.
+		if req.isReminderChanged() {
+			_, err = rc.taskService.UpdateReminderOffsets(application.GetTaskCallbackURL(c), room.ID, req.ToEntity)
+		} else {
+			_, err = rc.roomService.Update(patched)
+		}
 		if err != nil {
 			logger.Error(err.Error())
 			c.JSON(http.StatusBadRequest, err.Error())
@@ -414,6 +594,8 @@ type verifyRequestRoom struct {
 
 // @Summary      join a room
 // @Description  교환일기방 참여코드 체크 후, 교환일기방 멤버로 추가
+// @Description  * 참여코드를 여러 번 틀리면 (같은 방에서 5번, 같은 IP에서 20번) 점점 길게 참여가 제한되고 429를 반환한다.
+// @Description  * 멤버의 참여가 제한되면 마스터에게 알림이 전송된다.
 // @Tags         rooms
 // @Accept       json
 // @Produce      json
@@ -421,7 +603,9 @@ type verifyRequestRoom struct {
 // @Param        room  body 	verifyRequestRoom  true "교환일기방 참여 요청 body"
 // @Success      201
 // @Failure      400
-// @Failure      401
+// @Failure      401 "invalid code"
+// @Failure      409 "archived room"
+// @Failure      429 "too many failed attempts"
 // @Router       /rooms/{id}/join [post]
 // @Security ApiKeyAuth
 func (rc *roomController) Join() gin.HandlerFunc {
@@ -440,10 +624,10 @@ func (rc *roomController) Join() gin.HandlerFunc {
 			return
 		}
 
-		ok, err := rc.roomService.JoinRoom(roomID, currentMember.ID, req.Code)
+		ok, err := rc.roomService.JoinRoom(roomID, currentMember.ID, req.Code, c.ClientIP())
 		if err != nil {
 			logger.Error(err.Error())
-			c.JSON(http.StatusBadRequest, err.Error())
+			c.JSON(joinErrorStatus(err), err.Error())
 			return
 		}
 		if !ok {
@@ -455,6 +639,20 @@ func (rc *roomController) Join() gin.HandlerFunc {
 	}
 }
 
+// joinErrorStatus maps room join error to http status code
+func joinErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrInvalidRoomCode):
+		return http.StatusUnauthorized
+	case errors.Is(err, entity.ErrRoomJoinLocked):
+		return http.StatusTooManyRequests
+	case errors.Is(err, entity.ErrRoomArchived):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
 // @Summary      leave a room
 // @Description  교환일기방 나가기
 // @Description  1. 교환일기방 마스터일 경우
@@ -484,3 +682,225 @@ func (rc *roomController) Leave() gin.HandlerFunc {
 		c.Status(http.StatusNoContent)
 	}
 }
+
+// @Summary      skip my turn
+// @Description  현재 작성 차례인 멤버가 일기를 작성하지 않고 바로 다음 멤버에게 차례를 넘긴다.
+// @Description  * 작성주기가 끝났을 때와 동일하게 다음 멤버(부재중인 멤버는 건너뛴다)에게 차례가 넘어가고 알림이 전송된다.
+// @Tags         rooms
+// @Accept       json
+// @Produce      json
+// @Param        id  path  int  true  "교환일기방 ID"  Format(uint)
+// @Success      204
+// @Failure      400
+// @Failure      401 "only current turn member can skip"
+// @Failure      409 "archived room"
+// @Router       /rooms/{id}/turn/skip [post]
+// @Security ApiKeyAuth
+func (rc *roomController) SkipTurn() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		roomID, err := application.ParseUint(c.Param("room_id"))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := rc.taskService.SkipTurn(roomID, currentMember.ID, application.GetTaskCallbackURL(c)); err != nil {
+			logger.Error(err.Error())
+			c.JSON(diaryErrorStatus(err), err.Error())
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+type awayRequestRoom struct {
+	// null comes back right away
+	AwayUntil *time.Time `json:"awayUntil" example:"2022-08-01T00:00:00+09:00"`
+}
+
+type awayResponseRoom struct {
+	RoomID    uint       `json:"roomId"`
+	AwayUntil *time.Time `json:"awayUntil,omitempty"`
+}
+
+// @Summary      set away
+// @Description  교환일기방 부재 설정. awayUntil까지 작성 차례가 돌아오지 않고 건너뛴다.
+// @Description  * 모든 멤버가 부재중이라면 원래 순서대로 차례가 넘어간다.
+// @Description  * awayUntil을 null로 보내면 부재 설정이 해제된다.
+// @Tags         rooms
+// @Accept       json
+// @Produce      json
+// @Param        id    path  int              true  "교환일기방 ID"  Format(uint)
+// @Param        away  body  awayRequestRoom  true  "부재 설정 요청 body"
+// @Success      200  {object}  awayResponseRoom
+// @Failure      400
+// @Failure      401
+// @Router       /rooms/{id}/away [put]
+// @Security ApiKeyAuth
+func (rc *roomController) SetAway() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		roomID, err := application.ParseUint(c.Param("room_id"))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		var req awayRequestRoom
+		if err := c.BindJSON(&req); err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if _, err := rc.roomService.SetAway(roomID, currentMember.ID, req.AwayUntil); err != nil {
+			logger.Error(err.Error())
+			if errors.Is(err, entity.ErrNotJoinedRoom) {
+				c.JSON(http.StatusUnauthorized, err.Error())
+				return
+			}
+			c.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.JSON(http.StatusOK, awayResponseRoom{RoomID: roomID, AwayUntil: req.AwayUntil})
+	}
+}
+
+type archiveResponseRoom struct {
+	RoomID     uint       `json:"roomId"`
+	ArchivedAt *time.Time `json:"archivedAt,omitempty"`
+	DueAt      *time.Time `json:"dueAt,omitempty"`
+}
+
+// @Summary      archive a room
+// @Description  교환일기방 보관 (master only)
+// @Description  * 작성 차례가 더 이상 넘어가지 않고, 예약된 알림/마감 task가 모두 취소된다.
+// @Description  * 보관된 교환일기방은 읽기만 가능하다. (일기 작성, 임시저장, 참여, 초대, 순서 교환 불가)
+// @Tags         rooms
+// @Accept       json
+// @Produce      json
+// @Param        id  path  int  true  "교환일기방 ID"  Format(uint)
+// @Success      200  {object}  archiveResponseRoom
+// @Failure      400
+// @Failure      401
+// @Failure      409 "already archived"
+// @Router       /rooms/{id}/archive [post]
+// @Security ApiKeyAuth
+func (rc *roomController) Archive() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		room, ok := rc.getMasterRoom(c, "Only master can archive room")
+		if !ok {
+			return
+		}
+		archived, err := rc.taskService.ArchiveRoom(room.ID)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(archiveErrorStatus(err), err.Error())
+			return
+		}
+		c.JSON(http.StatusOK, archiveResponseRoom{RoomID: archived.ID, ArchivedAt: archived.ArchivedAt})
+	}
+}
+
+// @Summary      unarchive a room
+// @Description  교환일기방 보관 해제 (master only)
+// @Description  * 현재 작성 차례인 멤버부터 지금 시각 기준으로 작성주기가 다시 시작된다. (dueAt = now + period)
+// @Description  * 종료시각(endAt)이 이미 지났다면 종료시각은 해제된다.
+// @Tags         rooms
+// @Accept       json
+// @Produce      json
+// @Param        id  path  int  true  "교환일기방 ID"  Format(uint)
+// @Success      200  {object}  archiveResponseRoom
+// @Failure      400
+// @Failure      401
+// @Failure      409 "not archived"
+// @Router       /rooms/{id}/unarchive [post]
+// @Security ApiKeyAuth
+func (rc *roomController) Unarchive() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		room, ok := rc.getMasterRoom(c, "Only master can unarchive room")
+		if !ok {
+			return
+		}
+		unarchived, err := rc.taskService.UnarchiveRoom(room.ID, application.GetTaskCallbackURL(c))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(archiveErrorStatus(err), err.Error())
+			return
+		}
+		c.JSON(http.StatusOK, archiveResponseRoom{RoomID: unarchived.ID, DueAt: unarchived.DueAt})
+	}
+}
+
+// getMasterRoom returns the room of path param only if current member is the master. Otherwise it writes error response.
+func (rc *roomController) getMasterRoom(c *gin.Context, unauthorizedMessage string) (*entity.Room, bool) {
+	currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+	roomID, err := application.ParseUint(c.Param("room_id"))
+	if err != nil {
+		logger.Error(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	room, err := rc.roomService.Get(roomID, entity.Ignore)
+	if err != nil {
+		logger.Error(err.Error())
+		c.JSON(http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	if !room.IsMaster(currentMember.ID) {
+		c.JSON(http.StatusUnauthorized, unauthorizedMessage)
+		return nil, false
+	}
+	return room, true
+}
+
+// archiveErrorStatus maps room archive error to http status code
+func archiveErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrRoomArchived), errors.Is(err, entity.ErrRoomNotArchived):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+type responseRoomTheme struct {
+	ID            string `json:"id" example:"2"`
+	Name          string `json:"name" example:"잠 못 드는 밤"`
+	Hashtag       string `json:"hashtag" example:"#새벽감성"`
+	BackgroundURL string `json:"backgroundUrl"`
+}
+
+type formResponseRoom struct {
+	Themes  []responseRoomTheme `json:"themes"`
+	Periods []uint8             `json:"periods" example:"1,3,5,7"` // days
+}
+
+// @Summary      room create form
+// @Description  교환일기방 생성/수정 양식 (theme, 작성주기 dropdown list)
+// @Description  * 서버에서 관리하며, 교환일기방 생성/수정시 이 중 하나를 선택해야 한다.
+// @Tags         rooms
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}   formResponseRoom
+// @Failure      401
+// @Router       /rooms/form [get]
+// @Security ApiKeyAuth
+func (rc *roomController) GetForm() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		catalog := rc.roomCatalogService.Get()
+		res := formResponseRoom{Themes: []responseRoomTheme{}, Periods: []uint8{}}
+		for _, theme := range catalog.Themes {
+			res.Themes = append(res.Themes, responseRoomTheme{
+				ID:            theme.ID,
+				Name:          theme.Name,
+				Hashtag:       theme.Hashtag,
+				BackgroundURL: theme.BackgroundURL,
+			})
+		}
+		res.Periods = append(res.Periods, catalog.Periods...)
+		c.JSON(http.StatusOK, res)
+	}
+}
@@ -0,0 +1,292 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/application"
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/service"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// RoomInviteController handles /v1/rooms/:room_id/invites and /v1/rooms/join-by-invite api
+type RoomInviteController interface {
+	GetAll() gin.HandlerFunc
+	Post() gin.HandlerFunc
+	Delete() gin.HandlerFunc
+	Join() gin.HandlerFunc
+}
+
+type roomInviteController struct {
+	roomInviteService service.RoomInviteService
+}
+
+// NewRoomInviteController is a roomInviteController's constructor
+func NewRoomInviteController(ris service.RoomInviteService) RoomInviteController {
+	return &roomInviteController{
+		roomInviteService: ris,
+	}
+}
+
+// inviteDeepLinkPayload is passed to mobile app by deep link
+type inviteDeepLinkPayload struct {
+	RoomID   uint   `json:"roomId"`
+	InviteID uint   `json:"inviteId"`
+	Token    string `json:"token"`
+}
+
+type responseRoomInvite struct {
+	ID        uint                  `json:"id"`
+	Status    string                `json:"status" example:"ACTIVE"` // ACTIVE, EXPIRED, REVOKED, USED_UP
+	MaxUses   uint                  `json:"maxUses"`
+	UseCount  uint                  `json:"useCount"`
+	ExpiresAt time.Time             `json:"expiresAt"`
+	RevokedAt *time.Time            `json:"revokedAt,omitempty"`
+	DeepLink  string                `json:"deepLink" example:"exchangediary://rooms/join?token=..."`
+	Payload   inviteDeepLinkPayload `json:"payload"`
+	CreatedAt *time.Time            `json:"createdAt"`
+}
+
+func (ric *roomInviteController) toResponseRoomInvite(invite *entity.RoomInvite) (responseRoomInvite, error) {
+	token, err := ric.roomInviteService.Token(invite)
+	if err != nil {
+		return responseRoomInvite{}, err
+	}
+	status := "ACTIVE"
+	switch invite.Validate(time.Now()) {
+	case entity.ErrRoomInviteRevoked:
+		status = "REVOKED"
+	case entity.ErrRoomInviteExpired:
+		status = "EXPIRED"
+	case entity.ErrRoomInviteExhausted:
+		status = "USED_UP"
+	}
+	return responseRoomInvite{
+		ID:        invite.ID,
+		Status:    status,
+		MaxUses:   invite.MaxUses,
+		UseCount:  invite.UseCount,
+		ExpiresAt: invite.ExpiresAt,
+		RevokedAt: invite.RevokedAt,
+		DeepLink:  ric.roomInviteService.DeepLink(token),
+		Payload: inviteDeepLinkPayload{
+			RoomID:   invite.RoomID,
+			InviteID: invite.ID,
+			Token:    token,
+		},
+		CreatedAt: invite.CreatedAt,
+	}, nil
+}
+
+type listResponseRoomInvite struct {
+	Invites []responseRoomInvite `json:"invites"`
+}
+
+// @Summary      List invite links
+// @Description  교환일기방 초대 링크 리스트 (master only, 최신순)
+// @Description  * 만료, 취소, 사용 완료된 링크도 status와 함께 노출된다.
+// @Tags         invites
+// @Accept       json
+// @Produce      json
+// @Param        room_id  path  int  true  "교환일기방 ID"  Format(uint)
+// @Success      200  {object}   listResponseRoomInvite
+// @Failure      400
+// @Failure      401
+// @Failure      403
+// @Router       /rooms/{room_id}/invites [get]
+// @Security ApiKeyAuth
+func (ric *roomInviteController) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		roomID, err := application.ParseUint(c.Param("room_id"))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		invites, err := ric.roomInviteService.GetAll(roomID, currentMember.ID)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(roomInviteErrorStatus(err), err.Error())
+			return
+		}
+		res := listResponseRoomInvite{Invites: []responseRoomInvite{}}
+		for i := range invites {
+			invite, err := ric.toResponseRoomInvite(&invites[i])
+			if err != nil {
+				logger.Error(err.Error())
+				c.JSON(http.StatusInternalServerError, err.Error())
+				return
+			}
+			res.Invites = append(res.Invites, invite)
+		}
+		c.JSON(http.StatusOK, res)
+	}
+}
+
+type postRequestRoomInvite struct {
+	TTLHours uint `json:"ttlHours" example:"168"` // 링크 유효 시간 (default 168, 최대 720)
+	MaxUses  uint `json:"maxUses" example:"3"`    // 링크로 참여 가능한 인원 (default 1, 최대 9)
+}
+
+// @Summary      issue an invite link
+// @Description  교환일기방 초대 링크 발급 (master only)
+// @Description  * 링크의 token으로 방 ID, 참여코드 없이 교환일기방에 참여할 수 있다.
+// @Description  * deepLink는 앱의 참여 화면을 열며, payload는 앱에서 참여 요청에 사용한다.
+// @Tags         invites
+// @Accept       json
+// @Produce      json
+// @Param        room_id  path  int                    true  "교환일기방 ID"  Format(uint)
+// @Param        invite   body  postRequestRoomInvite  true  "초대 링크 발급 요청 body"
+// @Success      201  {object}   responseRoomInvite
+// @Failure      400
+// @Failure      401
+// @Failure      403
+// @Router       /rooms/{room_id}/invites [post]
+// @Security ApiKeyAuth
+func (ric *roomInviteController) Post() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		roomID, err := application.ParseUint(c.Param("room_id"))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		var req postRequestRoomInvite
+		if err := c.BindJSON(&req); err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ttl := entity.DefaultRoomInviteTTL
+		if req.TTLHours > 0 {
+			ttl = time.Duration(req.TTLHours) * time.Hour
+		}
+		maxUses := req.MaxUses
+		if maxUses == 0 {
+			maxUses = 1
+		}
+
+		invite, err := ric.roomInviteService.Issue(roomID, currentMember.ID, ttl, maxUses)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(roomInviteErrorStatus(err), err.Error())
+			return
+		}
+		res, err := ric.toResponseRoomInvite(invite)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusInternalServerError, err.Error())
+			return
+		}
+		c.JSON(http.StatusCreated, res)
+	}
+}
+
+// @Summary      revoke an invite link
+// @Description  교환일기방 초대 링크 취소 (master only). 이미 참여한 멤버에게는 영향이 없다.
+// @Tags         invites
+// @Accept       json
+// @Produce      json
+// @Param        room_id    path  int  true  "교환일기방 ID"  Format(uint)
+// @Param        invite_id  path  int  true  "초대 링크 ID"  Format(uint)
+// @Success      204
+// @Failure      400
+// @Failure      401
+// @Failure      403
+// @Failure      404
+// @Router       /rooms/{room_id}/invites/{invite_id} [delete]
+// @Security ApiKeyAuth
+func (ric *roomInviteController) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		roomID, err := application.ParseUint(c.Param("room_id"))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		inviteID, err := application.ParseUint(c.Param("invite_id"))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := ric.roomInviteService.Revoke(inviteID, roomID, currentMember.ID); err != nil {
+			logger.Error(err.Error())
+			c.JSON(roomInviteErrorStatus(err), err.Error())
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+type joinRequestRoomInvite struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type joinResponseRoomInvite struct {
+	RoomID   uint   `json:"roomId"`
+	RoomName string `json:"roomName"`
+}
+
+// @Summary      join a room by invite link
+// @Description  초대 링크의 token을 검증한 후, 교환일기방 멤버로 추가
+// @Description  * 만료, 취소, 사용 완료된 링크는 410, 인원이 가득 찬 방이나 보관된 방은 409
+// @Tags         invites
+// @Accept       json
+// @Produce      json
+// @Param        invite  body  joinRequestRoomInvite  true  "초대 링크 참여 요청 body"
+// @Success      201  {object}   joinResponseRoomInvite
+// @Failure      400
+// @Failure      401
+// @Failure      404
+// @Failure      409
+// @Failure      410
+// @Router       /rooms/join-by-invite [post]
+// @Security ApiKeyAuth
+func (ric *roomInviteController) Join() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		var req joinRequestRoomInvite
+		if err := c.BindJSON(&req); err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		room, err := ric.roomInviteService.Join(req.Token, currentMember.ID)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(roomInviteErrorStatus(err), err.Error())
+			return
+		}
+		c.JSON(http.StatusCreated, joinResponseRoomInvite{RoomID: room.ID, RoomName: room.Name})
+	}
+}
+
+// roomInviteErrorStatus maps room invite domain error to http status code
+func roomInviteErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrRoomInviteNotFound), errors.Is(err, entity.ErrRoomNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entity.ErrNotRoomMaster):
+		return http.StatusForbidden
+	case errors.Is(err, entity.ErrInvalidRoomInvite):
+		return http.StatusUnauthorized
+	case errors.Is(err, entity.ErrRoomInviteExpired),
+		errors.Is(err, entity.ErrRoomInviteRevoked),
+		errors.Is(err, entity.ErrRoomInviteExhausted):
+		return http.StatusGone
+	case errors.Is(err, entity.ErrAlreadyJoinedRoom), errors.Is(err, entity.ErrRoomMemberFull), errors.Is(err, entity.ErrRoomArchived):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
@@ -0,0 +1,221 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/application"
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/service"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// SwapRequestController handles /v1/rooms/:room_id/swaps api
+type SwapRequestController interface {
+	GetAll() gin.HandlerFunc
+	Post() gin.HandlerFunc
+	Accept() gin.HandlerFunc
+	Decline() gin.HandlerFunc
+}
+
+type swapRequestController struct {
+	swapRequestService service.SwapRequestService
+}
+
+// NewSwapRequestController is a swapRequestController's constructor
+func NewSwapRequestController(srs service.SwapRequestService) SwapRequestController {
+	return &swapRequestController{
+		swapRequestService: srs,
+	}
+}
+
+type responseSwapRequest struct {
+	ID          uint       `json:"id"`
+	RoomID      uint       `json:"roomId"`
+	RequesterID uint       `json:"requesterId"`
+	ResponderID uint       `json:"responderId"`
+	Status      string     `json:"status" example:"PENDING"` // PENDING, ACCEPTED, DECLINED, EXPIRED
+	ExpiresAt   time.Time  `json:"expiresAt"`
+	RespondedAt *time.Time `json:"respondedAt,omitempty"`
+}
+
+func toResponseSwapRequest(request *entity.SwapRequest) responseSwapRequest {
+	return responseSwapRequest{
+		ID:          request.ID,
+		RoomID:      request.RoomID,
+		RequesterID: request.RequesterID,
+		ResponderID: request.ResponderID,
+		Status:      string(request.Status),
+		ExpiresAt:   request.ExpiresAt,
+		RespondedAt: request.RespondedAt,
+	}
+}
+
+type listResponseSwapRequest struct {
+	SwapRequests []responseSwapRequest `json:"swapRequests"`
+}
+
+// @Summary      List pending swap requests
+// @Description  내가 요청했거나 요청받은, 응답 대기중인 작성 순서 바꾸기 요청 리스트
+// @Tags         swaps
+// @Accept       json
+// @Produce      json
+// @Param        room_id  path  int  true  "교환일기방 ID"  Format(uint)
+// @Success      200  {object}   listResponseSwapRequest
+// @Failure      400
+// @Failure      401
+// @Router       /rooms/{room_id}/swaps [get]
+// @Security ApiKeyAuth
+func (sc *swapRequestController) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		roomID, err := application.ParseUint(c.Param("room_id"))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		requests, err := sc.swapRequestService.GetAllPending(roomID, currentMember.ID)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(swapRequestErrorStatus(err), err.Error())
+			return
+		}
+		res := listResponseSwapRequest{SwapRequests: []responseSwapRequest{}}
+		for i := range requests {
+			res.SwapRequests = append(res.SwapRequests, toResponseSwapRequest(&requests[i]))
+		}
+		c.JSON(http.StatusOK, res)
+	}
+}
+
+type postRequestSwapRequest struct {
+	ResponderID uint `json:"responderId" binding:"required" example:"2"`
+}
+
+// @Summary      request a turn swap
+// @Description  다른 멤버에게 작성 순서 바꾸기 요청
+// @Description  * 상대방이 수락하면 다음 턴부터 이번 작성 주기(한 바퀴)가 끝날 때까지만 두 멤버의 순서가 바뀐다. 다음 주기부터는 원래 순서를 따른다.
+// @Description  * 현재 작성 차례인 멤버와 이번 주기에 이미 작성한 멤버는 순서를 바꿀 수 없다. (409)
+// @Description  * 24시간 동안 응답이 없으면 요청은 만료된다.
+// @Description  * 요청, 수락, 거절, 만료시 알림이 전송된다.
+// @Tags         swaps
+// @Accept       json
+// @Produce      json
+// @Param        room_id  path  int                     true  "교환일기방 ID"  Format(uint)
+// @Param        swap     body  postRequestSwapRequest  true  "순서 바꾸기 요청 body"
+// @Success      201  {object}   responseSwapRequest
+// @Failure      400
+// @Failure      401
+// @Failure      403
+// @Failure      409
+// @Router       /rooms/{room_id}/swaps [post]
+// @Security ApiKeyAuth
+func (sc *swapRequestController) Post() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		roomID, err := application.ParseUint(c.Param("room_id"))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		var req postRequestSwapRequest
+		if err := c.BindJSON(&req); err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		request, err := sc.swapRequestService.Request(roomID, currentMember.ID, req.ResponderID)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(swapRequestErrorStatus(err), err.Error())
+			return
+		}
+		c.JSON(http.StatusCreated, toResponseSwapRequest(request))
+	}
+}
+
+// @Summary      accept a turn swap
+// @Description  요청받은 작성 순서 바꾸기 수락 (다음 턴부터 이번 작성 주기가 끝날 때까지만 적용)
+// @Description  * 그 사이 둘 중 한 명의 작성 차례가 되었거나 이미 작성했다면 수락할 수 없다. (409)
+// @Tags         swaps
+// @Accept       json
+// @Produce      json
+// @Param        room_id  path  int  true  "교환일기방 ID"  Format(uint)
+// @Param        swap_id  path  int  true  "순서 바꾸기 요청 ID"  Format(uint)
+// @Success      200  {object}   responseSwapRequest
+// @Failure      400
+// @Failure      401
+// @Failure      403
+// @Failure      404
+// @Failure      409
+// @Router       /rooms/{room_id}/swaps/{swap_id}/accept [post]
+// @Security ApiKeyAuth
+func (sc *swapRequestController) Accept() gin.HandlerFunc {
+	return sc.respond(true)
+}
+
+// @Summary      decline a turn swap
+// @Description  요청받은 작성 순서 바꾸기 거절
+// @Tags         swaps
+// @Accept       json
+// @Produce      json
+// @Param        room_id  path  int  true  "교환일기방 ID"  Format(uint)
+// @Param        swap_id  path  int  true  "순서 바꾸기 요청 ID"  Format(uint)
+// @Success      200  {object}   responseSwapRequest
+// @Failure      400
+// @Failure      401
+// @Failure      403
+// @Failure      404
+// @Failure      409
+// @Router       /rooms/{room_id}/swaps/{swap_id}/decline [post]
+// @Security ApiKeyAuth
+func (sc *swapRequestController) Decline() gin.HandlerFunc {
+	return sc.respond(false)
+}
+
+func (sc *swapRequestController) respond(accept bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		roomID, err := application.ParseUint(c.Param("room_id"))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		swapID, err := application.ParseUint(c.Param("swap_id"))
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		request, err := sc.swapRequestService.Respond(swapID, roomID, currentMember.ID, accept)
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(swapRequestErrorStatus(err), err.Error())
+			return
+		}
+		c.JSON(http.StatusOK, toResponseSwapRequest(request))
+	}
+}
+
+// swapRequestErrorStatus maps swap request domain error to http status code
+func swapRequestErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrSwapRequestNotFound), errors.Is(err, entity.ErrRoomNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entity.ErrNotJoinedRoom), errors.Is(err, entity.ErrInvalidSwapRequest):
+		return http.StatusForbidden
+	case errors.Is(err, entity.ErrSwapRequestNotPending), errors.Is(err, entity.ErrSwapRequestAlreadyExists),
+		errors.Is(err, entity.ErrRoomArchived), errors.Is(err, entity.ErrSwapOnDuty), errors.Is(err, entity.ErrSwapPassed):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
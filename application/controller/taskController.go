@@ -1,16 +1,20 @@
 package controller
 
 import (
-	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/ExchangeDiary/exchange-diary/application"
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
 	"github.com/ExchangeDiary/exchange-diary/domain/service"
 	"github.com/ExchangeDiary/exchange-diary/domain/vo"
 	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
 	"github.com/gin-gonic/gin"
 )
 
+// cloudTasksTaskNameHeader is a header which has short name (task id + instance) of the task
+const cloudTasksTaskNameHeader = "X-CloudTasks-TaskName"
+
 // TaskController ...
 type TaskController interface {
 	HandleEvent() gin.HandlerFunc
@@ -33,17 +37,52 @@ func NewTaskController(ts service.TaskService, ms service.MemberService) TaskCon
 type taskRequest struct {
 	RoomID uint        `json:"room_id"`
 	Email  string      `json:"email"`
-	Code   vo.TaskCode `json:"code" enums:"ROOM_PERIOD_FIN,MEMBER_ON_DUTY,MEMBER_BEFORE_1HR,MEMBER_BEFORE_4HR,MEMBER_POSTED_DIARY"`
+	Code   vo.TaskCode `json:"code" example:"MEMBER_BEFORE_4HR"` // ROOM_PERIOD_FIN, MEMBER_ON_DUTY, MEMBER_POSTED_DIARY, MEMBER_BEFORE_{n}HR, MEMBER_BEFORE_{n}MIN, DEFERRED_PUSH, SWAP_REQUEST_EXPIRE
+	Turn   uint        `json:"turn,omitempty"`
+	DueAt  *time.Time  `json:"due_at,omitempty"`
+	// only DEFERRED_PUSH task
+	AlarmID uint `json:"alarm_id,omitempty"`
+	// only SWAP_REQUEST_EXPIRE task
+	SwapRequestID uint `json:"swap_request_id,omitempty"`
+}
+
+func (t *taskRequest) ToEntity(taskID, baseURL string) *entity.ScheduledTask {
+	task := vo.NewTaskVO(t.RoomID, t.Email, t.Code).OnTurn(t.Turn, t.DueAt)
+	task.AlarmID = t.AlarmID
+	task.SwapRequestID = t.SwapRequestID
+	return entity.NewScheduledTask(taskID, task, baseURL, time.Time{})
+}
+
+// taskErrorStatus chooses callback response status.
+// cloud tasks retries a task only if response status is not 2xx.
+func taskErrorStatus(err error) int {
+	switch {
+	case err == entity.ErrTaskAlreadyExecuted:
+		return http.StatusNoContent
+	case entity.IsPermanent(err):
+		return http.StatusAccepted
+	case err == entity.ErrTaskInProgress:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
 }
 
 // @Summary      Handle Event Task
 // @Description	 google cloud task에 예약 해두었던, task들을 스케쥴된 일정시간이 지난뒤, 처리해주는 callback handler api endpoint.
+// @Description  * cloud tasks가 첨부한 OIDC 토큰(Authorization 헤더) 또는 X-Voda-Task-Signature HMAC 서명 헤더로 인증한다.
 // @Tags         tasks
 // @Accept       json
 // @Produce      json
-// @Param        task  body     taskRequest  true  "Task Http Body"
-// @Success      202  "Accepted. It ignore request, because error occured. 정상 처리(204)와 차이점을 두기 위해서 202로 처리함"
-// @Success      204 "successfully finished callback task."
+// @Description  * task는 (task 이름, turn/dueAt) 단위로 한번만 실행되며, 중복 전달된 task는 실행하지 않고 204로 응답한다.
+// @Description  * 재시도해도 성공할 수 없는 에러(방/멤버 삭제, 지난 턴의 task 등)는 202, 일시적인 에러는 재시도되도록 409/500으로 응답한다.
+// @Param        X-CloudTasks-TaskName  header  string       true  "cloud tasks task 이름"
+// @Param        task                   body    taskRequest  true  "Task Http Body"
+// @Success      202  "Accepted. It ignore request, because permanent error occured. 정상 처리(204)와 차이점을 두기 위해서 202로 처리함"
+// @Success      204 "successfully finished (or already finished) callback task."
+// @Failure      401
+// @Failure      409 "the same task is being executed. cloud tasks retries it."
+// @Failure      500 "transient error. cloud tasks retries it."
 // @Router       /tasks/callback [post]
 func (tc *taskController) HandleEvent() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -54,17 +93,22 @@ func (tc *taskController) HandleEvent() gin.HandlerFunc {
 			c.JSON(http.StatusAccepted, err.Error())
 			return
 		}
-		_, err := tc.memberService.GetByEmail(req.Email)
-		if err != nil {
-			logger.Error("Callback task errors. " + req.Email + " not found. (maybe user is already unsigned)")
-			c.JSON(http.StatusAccepted, err.Error())
+		// task name is task id + instance (see entity.ScheduledTask.Name)
+		taskID, _ := entity.ParseTaskName(c.GetHeader(cloudTasksTaskNameHeader))
+		if taskID == "" {
+			logger.Error("POST /task/callback endpoint gets a request without task name header.")
+			c.JSON(http.StatusAccepted, "no "+cloudTasksTaskNameHeader+" header")
 			return
 		}
 
-		err = tc.doTask(req, application.GetCurrentURL(c))
-		if err != nil {
+		if err := tc.taskService.Handle(req.ToEntity(taskID, application.GetCurrentURL(c))); err != nil {
 			logger.Error(err.Error())
-			c.JSON(http.StatusAccepted, err.Error())
+			status := taskErrorStatus(err)
+			if status == http.StatusNoContent {
+				c.Status(status)
+				return
+			}
+			c.JSON(status, err.Error())
 			return
 		}
 		c.Status(http.StatusNoContent)
@@ -72,12 +116,14 @@ func (tc *taskController) HandleEvent() gin.HandlerFunc {
 }
 
 // @Summary      Mock Handle Event Task
+// @Description  prod phase에서는 등록되지 않는다. callback api와 동일하게 인증이 필요하다.
 // @Tags         tasks
 // @Accept       json
 // @Produce      json
 // @Param        task  body     taskRequest  true  "Task Http Body"
 // @Success      202  "Accepted. It ignore request, because error occured. 정상 처리(204)와 차이점을 두기 위해서 202로 처리함"
 // @Success      204 "successfully finished callback task."
+// @Failure      401
 // @Router       /tasks/mock [post]
 func (tc *taskController) MockEvent() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -120,21 +166,3 @@ func (tc *taskController) doMockTask(dto taskRequest, baseURL string) (err error
 	}
 	return
 }
-
-func (tc *taskController) doTask(dto taskRequest, baseURL string) (err error) {
-	switch dto.Code {
-	case vo.RoomPeriodFinCode:
-		err = tc.taskService.DoRoomPeriodFINTask(dto.RoomID, baseURL)
-	case vo.MemberOnDutyCode:
-		err = tc.taskService.DoMemberOnDutyTask(dto.RoomID, dto.Email)
-	case vo.MemberBefore1HRCode:
-		err = tc.taskService.DoMemberBeforeTask(dto.RoomID, dto.Email, vo.MemberBefore1HRCode)
-	case vo.MemberBefore4HRCode:
-		err = tc.taskService.DoMemberBeforeTask(dto.RoomID, dto.Email, vo.MemberBefore4HRCode)
-	case vo.MemberPostedDiaryCode:
-		err = tc.taskService.DoMemberPostedDiaryTask(dto.RoomID, baseURL)
-	default:
-		err = fmt.Errorf("Not registered task code. [ " + string(dto.Code) + " ]")
-	}
-	return
-}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ExchangeDiary/exchange-diary/application"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/configs"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AdminAuthorizationFilter allows only admin members. It must be used after AuthenticationFilter.
+type AdminAuthorizationFilter struct {
+	emails map[string]bool
+}
+
+// NewAdminAuthorizationFilter ...
+func NewAdminAuthorizationFilter(config configs.Admin) *AdminAuthorizationFilter {
+	emails := map[string]bool{}
+	for _, email := range config.Emails {
+		emails[strings.ToLower(email)] = true
+	}
+	return &AdminAuthorizationFilter{emails: emails}
+}
+
+// Authorize ...
+func (f *AdminAuthorizationFilter) Authorize() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		currentMember := c.MustGet(application.CurrentMemberKey).(application.CurrentMemberDTO)
+		if !f.emails[strings.ToLower(currentMember.Email)] {
+			logger.Error("not admin member", zap.Uint("ID", currentMember.ID), zap.String("Email", currentMember.Email))
+			c.JSON(http.StatusForbidden, gin.H{"error": "admin only"})
+			c.Abort()
+			return
+		}
+	}
+}
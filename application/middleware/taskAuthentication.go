@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/application"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/clients/google/tasks"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/configs"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// TaskAuthenticationFilter authenticates task callback requests.
+// A request must have a google OIDC token of task service account, or a valid HMAC signature.
+type TaskAuthenticationFilter struct {
+	config configs.TaskAuth
+}
+
+// NewTaskAuthenticationFilter ...
+func NewTaskAuthenticationFilter(config configs.TaskAuth) *TaskAuthenticationFilter {
+	return &TaskAuthenticationFilter{config: config}
+}
+
+// Authenticate ...
+func (f *TaskAuthenticationFilter) Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var err error
+		bearerToken := c.Request.Header.Get(authorizationHeader)
+		signature := c.Request.Header.Get(tasks.SignatureHeader)
+		switch {
+		case bearerToken != "" && f.config.ServiceAccountEmail != "":
+			err = f.verifyOIDC(c, strings.Replace(bearerToken, tokenBearer, "", 1))
+		case signature != "" && f.config.HMACSecret != "":
+			err = f.verifySignature(c, signature)
+		default:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "No task authorization"})
+			c.Abort()
+			return
+		}
+		if err != nil {
+			logger.Error(err.Error())
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+	}
+}
+
+func (f *TaskAuthenticationFilter) verifyOIDC(c *gin.Context, token string) error {
+	audience := f.config.Audience
+	if audience == "" {
+		audience = application.GetCurrentURL(c)
+	}
+	return tasks.VerifyOIDC(c.Request.Context(), token, audience, f.config.ServiceAccountEmail)
+}
+
+func (f *TaskAuthenticationFilter) verifySignature(c *gin.Context, signature string) error {
+	body, err := c.GetRawData()
+	if err != nil {
+		return err
+	}
+	// restore body for the handler
+	c.Request.Body = ioutil.NopCloser(bytes.NewBuffer(body))
+	return tasks.VerifySignature(f.config.HMACSecret, body, signature, time.Now())
+}
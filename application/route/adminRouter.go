@@ -0,0 +1,18 @@
+package route
+
+import (
+	"github.com/ExchangeDiary/exchange-diary/application/controller"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminRoutes is admin api handler. authorize must allow only admin members.
+func AdminRoutes(router *gin.RouterGroup, controller controller.AdminController, authorize gin.HandlerFunc) {
+	admin := router.Group("/admin/rooms/:room_id", authorize)
+	{
+		admin.GET("/tasks", controller.GetTasks())
+		admin.POST("/tasks/:account_id/:code/run", controller.RunTask())
+		admin.PATCH("/tasks/:account_id/:code", controller.RescheduleTask())
+		admin.DELETE("/tasks/:account_id/:code", controller.CancelTask())
+		admin.GET("/audits", controller.GetAudits())
+	}
+}
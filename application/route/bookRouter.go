@@ -0,0 +1,11 @@
+package route
+
+import (
+	"github.com/ExchangeDiary/exchange-diary/application/controller"
+	"github.com/gin-gonic/gin"
+)
+
+// BookRoutes is book api handler
+func BookRoutes(router *gin.RouterGroup, controller controller.BookController) {
+	router.GET("/rooms/:room_id/book", controller.Get())
+}
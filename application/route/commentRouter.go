@@ -0,0 +1,16 @@
+package route
+
+import (
+	"github.com/ExchangeDiary/exchange-diary/application/controller"
+	"github.com/gin-gonic/gin"
+)
+
+// CommentRoutes is comment api handler
+func CommentRoutes(router *gin.RouterGroup, controller controller.CommentController) {
+	comments := router.Group("/rooms/:room_id/diaries/:diary_id/comments")
+	{
+		comments.GET("", controller.GetAll())
+		comments.POST("", controller.Post())
+		comments.DELETE("/:comment_id", controller.Delete())
+	}
+}
@@ -1,8 +1,32 @@
 package route
 
 import (
+	"github.com/ExchangeDiary/exchange-diary/application/controller"
 	"github.com/gin-gonic/gin"
 )
 
-// DiaryRoutes ...
-func DiaryRoutes(incomingRoutes *gin.Engine) {}
+// DiaryRoutes is diary api handler
+func DiaryRoutes(router *gin.RouterGroup, controller controller.DiaryController) {
+	diaries := router.Group("/rooms/:room_id/diaries")
+	{
+		diaries.GET("/", controller.GetAll())
+		diaries.GET("/:diary_id", controller.Get())
+		diaries.POST("/", controller.Post())
+		diaries.PATCH("/:diary_id", controller.Patch())
+		diaries.DELETE("/:diary_id", controller.Delete())
+		diaries.POST("/:diary_id/read", controller.Read())
+		diaries.PUT("/:diary_id/reactions", controller.React())
+	}
+	router.GET("/diaries/search", controller.Search())
+}
+
+// DiaryDraftRoutes is diary draft api handler
+func DiaryDraftRoutes(router *gin.RouterGroup, controller controller.DiaryDraftController) {
+	draft := router.Group("/rooms/:room_id/diaries/draft")
+	{
+		draft.GET("", controller.Get())
+		draft.PUT("", controller.Put())
+		draft.DELETE("", controller.Delete())
+		draft.POST("/publish", controller.Publish())
+	}
+}
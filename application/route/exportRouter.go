@@ -0,0 +1,15 @@
+package route
+
+import (
+	"github.com/ExchangeDiary/exchange-diary/application/controller"
+	"github.com/gin-gonic/gin"
+)
+
+// ExportRoutes is export api handler
+func ExportRoutes(router *gin.RouterGroup, controller controller.ExportController) {
+	exports := router.Group("/rooms/:room_id/exports")
+	{
+		exports.POST("", controller.Post())
+		exports.GET("/:export_id", controller.Get())
+	}
+}
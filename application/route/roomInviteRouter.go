@@ -0,0 +1,17 @@
+package route
+
+import (
+	"github.com/ExchangeDiary/exchange-diary/application/controller"
+	"github.com/gin-gonic/gin"
+)
+
+// RoomInviteRoutes is room invite link api handler
+func RoomInviteRoutes(router *gin.RouterGroup, controller controller.RoomInviteController) {
+	router.POST("/rooms/join-by-invite", controller.Join())
+	invites := router.Group("/rooms/:room_id/invites")
+	{
+		invites.GET("", controller.GetAll())
+		invites.POST("", controller.Post())
+		invites.DELETE("/:invite_id", controller.Delete())
+	}
+}
@@ -10,6 +10,7 @@ func RoomRoutes(router *gin.RouterGroup, controller controller.RoomController) {
 	rooms := router.Group("/rooms")
 	{
 		rooms.GET("/", controller.GetAll())
+		rooms.GET("/form", controller.GetForm())
 		rooms.GET("/:room_id", controller.Get())
 		rooms.GET("/:room_id/orders", controller.GetOrders())
 		rooms.POST("/", controller.Post())
@@ -17,5 +18,9 @@ func RoomRoutes(router *gin.RouterGroup, controller controller.RoomController) {
 		rooms.DELETE("/:room_id", controller.Delete())
 		rooms.POST("/:room_id/join", controller.Join())
 		rooms.DELETE("/:room_id/leave", controller.Leave())
+		rooms.POST("/:room_id/turn/skip", controller.SkipTurn())
+		rooms.PUT("/:room_id/away", controller.SetAway())
+		rooms.POST("/:room_id/archive", controller.Archive())
+		rooms.POST("/:room_id/unarchive", controller.Unarchive())
 	}
 }
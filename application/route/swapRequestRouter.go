@@ -0,0 +1,17 @@
+package route
+
+import (
+	"github.com/ExchangeDiary/exchange-diary/application/controller"
+	"github.com/gin-gonic/gin"
+)
+
+// SwapRequestRoutes is turn swap request api handler
+func SwapRequestRoutes(router *gin.RouterGroup, controller controller.SwapRequestController) {
+	swaps := router.Group("/rooms/:room_id/swaps")
+	{
+		swaps.GET("", controller.GetAll())
+		swaps.POST("", controller.Post())
+		swaps.POST("/:swap_id/accept", controller.Accept())
+		swaps.POST("/:swap_id/decline", controller.Decline())
+	}
+}
@@ -6,11 +6,17 @@ import (
 )
 
 // TaskRoutes to handle google cloud tasks callback endpoints
-func TaskRoutes(router *gin.RouterGroup, controller controller.TaskController) {
-	tasks := router.Group("/tasks")
+func TaskRoutes(router *gin.RouterGroup, controller controller.TaskController, authenticate gin.HandlerFunc) {
+	tasks := router.Group("/tasks", authenticate)
 	{
 		tasks.POST("/callback", controller.HandleEvent())
-		tasks.POST("/mock", controller.MockEvent())
+	}
+}
 
+// TaskMockRoutes registers mock callback endpoint. It must not be registered in prod phase.
+func TaskMockRoutes(router *gin.RouterGroup, controller controller.TaskController, authenticate gin.HandlerFunc) {
+	tasks := router.Group("/tasks", authenticate)
+	{
+		tasks.POST("/mock", controller.MockEvent())
 	}
 }
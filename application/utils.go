@@ -1,6 +1,7 @@
 package application
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -9,8 +10,12 @@ import (
 const (
 	defaultPageLimit  = 10
 	defaultPageOffset = 0
+	maxPageLimit      = 100
 )
 
+// ErrInvalidPageLimit is returned when limit query is not in 1..maxPageLimit
+var ErrInvalidPageLimit = fmt.Errorf("limit must be between 1 and %d", maxPageLimit)
+
 // CurrentMemberDTO is a current member dto which parsed from jwtAuthentication middleware.
 type CurrentMemberDTO struct {
 	ID    uint
@@ -31,6 +36,16 @@ func GetLimitAndOffset(c *gin.Context) (uint, uint) {
 	return limit, offset
 }
 
+// GetValidLimitAndOffset is GetLimitAndOffset which rejects an invalid limit.
+// (limit 0 means no limit to gorm, so it is never passed to repositories)
+func GetValidLimitAndOffset(c *gin.Context) (uint, uint, error) {
+	limit, offset := GetLimitAndOffset(c)
+	if limit == 0 || limit > maxPageLimit {
+		return 0, 0, ErrInvalidPageLimit
+	}
+	return limit, offset, nil
+}
+
 // ParseUint parse string to uint
 func ParseUint(str string) (uint, error) {
 	val, err := strconv.ParseUint(str, 10, 64) // uint64
@@ -39,9 +54,18 @@ func ParseUint(str string) (uint, error) {
 
 // GetCurrentURL returns current request full url
 func GetCurrentURL(c *gin.Context) string {
+	return getBaseURL(c) + c.Request.URL.String()
+}
+
+// GetTaskCallbackURL returns task callback url of current host
+func GetTaskCallbackURL(c *gin.Context) string {
+	return getBaseURL(c) + TaskCallbackPath
+}
+
+func getBaseURL(c *gin.Context) string {
 	scheme := "http://"
 	if c.Request.TLS != nil {
 		scheme = "https://"
 	}
-	return scheme + c.Request.Host + c.Request.URL.String()
+	return scheme + c.Request.Host
 }
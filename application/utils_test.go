@@ -0,0 +1,35 @@
+package application
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetValidLimitAndOffset(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantLimit  uint
+		wantOffset uint
+		wantErr    error
+	}{
+		{"default", "", defaultPageLimit, defaultPageOffset, nil},
+		{"given", "?limit=20&offset=40", 20, 40, nil},
+		{"max", "?limit=100", maxPageLimit, defaultPageOffset, nil},
+		{"zero limit", "?limit=0", 0, 0, ErrInvalidPageLimit},
+		{"too large limit", "?limit=101", 0, 0, ErrInvalidPageLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/v1/rooms/1/diaries"+tt.query, nil)
+
+			limit, offset, err := GetValidLimitAndOffset(c)
+			if err != tt.wantErr || limit != tt.wantLimit || offset != tt.wantOffset {
+				t.Fatalf("GetValidLimitAndOffset() = (%d, %d, %v), want (%d, %d, %v)", limit, offset, err, tt.wantLimit, tt.wantOffset, tt.wantErr)
+			}
+		})
+	}
+}
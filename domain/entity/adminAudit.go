@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"time"
+)
+
+// AdminAction is an action which admin takes
+type AdminAction string
+
+const (
+	// AdminRunTask runs a scheduled task right away
+	AdminRunTask AdminAction = "RUN_TASK"
+	// AdminRescheduleTask changes schedule time of a task
+	AdminRescheduleTask AdminAction = "RESCHEDULE_TASK"
+	// AdminCancelTask deletes a scheduled task
+	AdminCancelTask AdminAction = "CANCEL_TASK"
+)
+
+// AdminAudit is a record of an admin action
+type AdminAudit struct {
+	ID        uint
+	AdminID   uint
+	Action    AdminAction
+	RoomID    uint
+	TaskID    string
+	Detail    string
+	Error     string
+	CreatedAt *time.Time
+}
+
+// NewAdminAudit ...
+func NewAdminAudit(adminID uint, action AdminAction, roomID uint, taskID, detail string) *AdminAudit {
+	return &AdminAudit{
+		AdminID: adminID,
+		Action:  action,
+		RoomID:  roomID,
+		TaskID:  taskID,
+		Detail:  detail,
+	}
+}
+
+// Fail records the error of the action
+func (a *AdminAudit) Fail(err error) {
+	if err != nil {
+		a.Error = err.Error()
+	}
+}
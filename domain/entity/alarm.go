@@ -2,6 +2,7 @@ package entity
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,8 +10,12 @@ import (
 	"github.com/ExchangeDiary/exchange-diary/domain/vo"
 )
 
+// ErrAlarmNotFound ...
+var ErrAlarmNotFound = errors.New("alarm does not exist")
+
 // Alarm represents alarm body
 type Alarm struct {
+	ID       uint `json:"-"`
 	MemberID uint
 	RoomID   uint
 	Code     string
@@ -36,40 +41,130 @@ func NewAlarm(memberID, roomID uint, code vo.TaskCode, roomName, diaryTitle, aut
 			AlarmAt:  &now,
 			Title:    "내가 일기 쓸 차례에요!",
 		}
-	case vo.MemberBefore1HRCode:
+	case vo.MemberPostedDiaryCode:
 		return &Alarm{
 			MemberID: memberID,
 			RoomID:   roomID,
 			Code:     string(code),
 			RoomName: roomName,
 			AlarmAt:  &now,
-			Title:    "일기 등록까지 1시간 남았어요!",
+			Title:    fmt.Sprintf("'%s' 새글 등록", diaryTitle),
+			Author:   authorNickname,
 		}
-	case vo.MemberBefore4HRCode:
+	case vo.MemberReactedDiaryCode:
 		return &Alarm{
 			MemberID: memberID,
 			RoomID:   roomID,
 			Code:     string(code),
 			RoomName: roomName,
 			AlarmAt:  &now,
-			Title:    "일기 등록까지 4시간 남았어요!",
+			Title:    fmt.Sprintf("'%s'에 새로운 반응이 있어요!", diaryTitle),
+			Author:   authorNickname,
 		}
-	case vo.MemberPostedDiaryCode:
+	case vo.MemberCommentedDiaryCode:
 		return &Alarm{
 			MemberID: memberID,
 			RoomID:   roomID,
 			Code:     string(code),
 			RoomName: roomName,
 			AlarmAt:  &now,
-			Title:    fmt.Sprintf("'%s' 새글 등록", diaryTitle),
+			Title:    fmt.Sprintf("'%s'에 새로운 댓글이 달렸어요!", diaryTitle),
+			Author:   authorNickname,
+		}
+	case vo.SwapRequestedCode:
+		return &Alarm{
+			MemberID: memberID,
+			RoomID:   roomID,
+			Code:     string(code),
+			RoomName: roomName,
+			AlarmAt:  &now,
+			Title:    fmt.Sprintf("'%s'님이 작성 순서를 바꾸고 싶어해요!", authorNickname),
+			Author:   authorNickname,
+		}
+	case vo.SwapAcceptedCode:
+		return &Alarm{
+			MemberID: memberID,
+			RoomID:   roomID,
+			Code:     string(code),
+			RoomName: roomName,
+			AlarmAt:  &now,
+			Title:    fmt.Sprintf("'%s'님이 작성 순서 바꾸기를 수락했어요!", authorNickname),
+			Author:   authorNickname,
+		}
+	case vo.SwapDeclinedCode:
+		return &Alarm{
+			MemberID: memberID,
+			RoomID:   roomID,
+			Code:     string(code),
+			RoomName: roomName,
+			AlarmAt:  &now,
+			Title:    fmt.Sprintf("'%s'님이 작성 순서 바꾸기를 거절했어요.", authorNickname),
 			Author:   authorNickname,
 		}
+	case vo.SwapExpiredCode:
+		return &Alarm{
+			MemberID: memberID,
+			RoomID:   roomID,
+			Code:     string(code),
+			RoomName: roomName,
+			AlarmAt:  &now,
+			Title:    fmt.Sprintf("'%s'님이 작성 순서 바꾸기 요청에 응답하지 않았어요.", authorNickname),
+			Author:   authorNickname,
+		}
+	case vo.RoomJoinLockedCode:
+		return &Alarm{
+			MemberID: memberID,
+			RoomID:   roomID,
+			Code:     string(code),
+			RoomName: roomName,
+			AlarmAt:  &now,
+			Title:    fmt.Sprintf("'%s'님이 참여 코드를 여러 번 틀려서 참여가 잠시 제한됐어요.", authorNickname),
+			Author:   authorNickname,
+		}
+	case vo.ExportReadyCode:
+		return &Alarm{
+			MemberID: memberID,
+			RoomID:   roomID,
+			Code:     string(code),
+			RoomName: roomName,
+			AlarmAt:  &now,
+			Title:    "교환일기 내보내기가 완료되었어요!",
+		}
 	default:
+		// reminder titles are generated from the offset. (ex. MEMBER_BEFORE_30MIN)
+		if offset, ok := code.ReminderOffset(); ok {
+			return &Alarm{
+				MemberID: memberID,
+				RoomID:   roomID,
+				Code:     string(code),
+				RoomName: roomName,
+				AlarmAt:  &now,
+				Title:    fmt.Sprintf("일기 등록까지 %s 남았어요!", remainingTimeText(offset)),
+			}
+		}
 		fmt.Printf("'%s' is invalid code type", code)
 		return nil
 	}
 }
 
+// remainingTimeText formats reminder offset. ex) 4h -> "4시간", 90m -> "1시간 30분", 30m -> "30분"
+func remainingTimeText(offset time.Duration) string {
+	hours, minutes := offset/time.Hour, (offset%time.Hour)/time.Minute
+	switch {
+	case minutes == 0:
+		return fmt.Sprintf("%d시간", hours)
+	case hours == 0:
+		return fmt.Sprintf("%d분", minutes)
+	default:
+		return fmt.Sprintf("%d시간 %d분", hours, minutes)
+	}
+}
+
+// IsUrgent returns whether alarm must be pushed even in quiet hours
+func (a *Alarm) IsUrgent() bool {
+	return vo.TaskCode(a.Code).IsUrgent()
+}
+
 // UnqFields returns alarm's unique field component.
 func (a *Alarm) UnqFields() (roomID, memberID uint, code vo.TaskCode) {
 	return a.RoomID, a.MemberID, vo.TaskCode(a.Code)
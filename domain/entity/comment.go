@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+const maxCommentLength = 1000
+
+var (
+	// ErrCommentNotFound is returned when comment does not exist or is already deleted.
+	ErrCommentNotFound = errors.New("comment does not exist")
+	// ErrCommentDepthExceeded is returned when member replies to a reply.
+	ErrCommentDepthExceeded = errors.New("cannot reply to a reply")
+	// ErrNotCommentAuthor is returned when member deletes other's comment.
+	ErrNotCommentAuthor = errors.New("only author can delete the comment")
+)
+
+// Comment is a member's comment on a diary.
+// A comment can have replies, but a reply cannot have replies. (one-level depth)
+type Comment struct {
+	ID       uint
+	DiaryID  uint
+	AuthorID uint
+	Author   *Member
+	ParentID *uint
+	Body     string
+	Replies  *Comments
+
+	CreatedAt *time.Time
+	DeletedAt *time.Time
+}
+
+// Comments ...
+type Comments []Comment
+
+// NewComment ...
+func NewComment(diaryID, authorID uint, body string) (*Comment, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, errors.New("comment body is required")
+	}
+	if len([]rune(body)) > maxCommentLength {
+		return nil, errors.New("comment body is too long")
+	}
+	return &Comment{
+		DiaryID:  diaryID,
+		AuthorID: authorID,
+		Body:     body,
+	}, nil
+}
+
+// IsEqual guarantees Entity's identity
+func (c *Comment) IsEqual(other *Comment) bool {
+	return other.ID == c.ID
+}
+
+// IsReply returns whether comment is a reply of another comment
+func (c *Comment) IsReply() bool {
+	return c.ParentID != nil
+}
+
+// IsDeleted ...
+func (c *Comment) IsDeleted() bool {
+	return c.DeletedAt != nil
+}
+
+// IsAuthor ...
+func (c *Comment) IsAuthor(memberID uint) bool {
+	return c.AuthorID == memberID
+}
+
+// ReplyTo makes comment as a reply of parent.
+func (c *Comment) ReplyTo(parent *Comment) error {
+	if parent.DiaryID != c.DiaryID {
+		return ErrCommentNotFound
+	}
+	if parent.IsReply() {
+		return ErrCommentDepthExceeded
+	}
+	c.ParentID = &parent.ID
+	return nil
+}
@@ -0,0 +1,185 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	summaryLength = 50
+	snippetRadius = 40
+
+	highlightOpenTag  = "<em>"
+	highlightCloseTag = "</em>"
+)
+
+var (
+	// ErrNotDiaryTurn is returned when a member who is not on duty tries to post a diary.
+	ErrNotDiaryTurn = errors.New("only current turn member can post a diary")
+	// ErrImmutableDiary is returned when a posted diary is about to be updated or deleted.
+	ErrImmutableDiary = errors.New("diary cannot be updated or deleted once it is posted")
+	// ErrLockedAttachment is returned when a photo/audio of posted diary is about to be overwritten or deleted.
+	ErrLockedAttachment = errors.New("attachment of posted diary cannot be overwritten or deleted")
+)
+
+// Diary is an entry posted into a room by the member on duty.
+type Diary struct {
+	ID       uint
+	RoomID   uint
+	AuthorID uint
+	Author   *Member
+	Turn     uint
+
+	Title string
+	Body  string
+
+	PhotoUUID  string
+	PhotoURL   string
+	PhotoHash  string // md5 of the photo object, which proves the object is unchanged
+	AudioUUID  string
+	AudioURL   string
+	AudioHash  string // md5 of the audio object, which proves the object is unchanged
+	AudioTitle string
+	AudioPitch string
+
+	Reactions ReactionCounts // aggregated reactions (not persisted)
+
+	CreatedAt *time.Time
+}
+
+// Diaries ...
+type Diaries []Diary
+
+// NewDiary ...
+func NewDiary(roomID, authorID uint, title, body string) (*Diary, error) {
+	if title == "" {
+		return nil, fmt.Errorf("diary title is required")
+	}
+	return &Diary{
+		RoomID:   roomID,
+		AuthorID: authorID,
+		Title:    title,
+		Body:     body,
+	}, nil
+}
+
+// IsEqual guarantees Entity's identity
+func (d *Diary) IsEqual(other *Diary) bool {
+	return other.ID == d.ID
+}
+
+// AttachPhoto sets photo which was already uploaded through files api.
+func (d *Diary) AttachPhoto(uuid, url string) {
+	d.PhotoUUID = uuid
+	d.PhotoURL = url
+}
+
+// AttachAudio sets audio which was already uploaded through files api.
+func (d *Diary) AttachAudio(uuid, url, title, pitch string) {
+	d.AudioUUID = uuid
+	d.AudioURL = url
+	d.AudioTitle = title
+	d.AudioPitch = pitch
+}
+
+// HasPhoto ...
+func (d *Diary) HasPhoto() bool {
+	return d.PhotoUUID != ""
+}
+
+// HasAudio ...
+func (d *Diary) HasAudio() bool {
+	return d.AudioUUID != ""
+}
+
+// Summary returns first few characters of body, which is shown on room's diary list.
+func (d *Diary) Summary() string {
+	summary := strings.Join(strings.Fields(d.Body), " ")
+	if utf8.RuneCountInString(summary) <= summaryLength {
+		return summary
+	}
+	return string([]rune(summary)[:summaryLength]) + "..."
+}
+
+// Matches returns whether title or body contains every term. (case insensitive)
+func (d *Diary) Matches(terms []string) bool {
+	text := strings.ToLower(d.Title + " " + d.Body)
+	for _, term := range terms {
+		if !strings.Contains(text, strings.ToLower(term)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Highlight returns title and a snippet of body whose matched terms are wrapped with <em> tag.
+// The rest of text is html escaped.
+func (d *Diary) Highlight(terms []string) (title, snippet string) {
+	title = highlight([]rune(d.Title), terms)
+
+	body := []rune(strings.Join(strings.Fields(d.Body), " "))
+	ranges := matchRanges(body, terms)
+	if len(ranges) == 0 {
+		return title, html.EscapeString(d.Summary())
+	}
+	start, end := ranges[0][0]-snippetRadius, ranges[0][0]+snippetRadius*2
+	if start < 0 {
+		start = 0
+	}
+	if end > len(body) {
+		end = len(body)
+	}
+	snippet = highlight(body[start:end], terms)
+	if start > 0 {
+		snippet = "..." + snippet
+	}
+	if end < len(body) {
+		snippet += "..."
+	}
+	return title, snippet
+}
+
+func highlight(text []rune, terms []string) string {
+	var b strings.Builder
+	last := 0
+	for _, r := range matchRanges(text, terms) {
+		b.WriteString(html.EscapeString(string(text[last:r[0]])))
+		b.WriteString(highlightOpenTag)
+		b.WriteString(html.EscapeString(string(text[r[0]:r[1]])))
+		b.WriteString(highlightCloseTag)
+		last = r[1]
+	}
+	b.WriteString(html.EscapeString(string(text[last:])))
+	return b.String()
+}
+
+// matchRanges returns sorted, non-overlapping [start, end) rune ranges of terms in text.
+func matchRanges(text []rune, terms []string) [][2]int {
+	lower := make([]rune, len(text))
+	for i, r := range text {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	ranges := [][2]int{}
+	for i := 0; i < len(lower); {
+		matched := 0
+		for _, term := range terms {
+			t := []rune(strings.ToLower(term))
+			if len(t) > matched && i+len(t) <= len(lower) && string(lower[i:i+len(t)]) == string(t) {
+				matched = len(t)
+			}
+		}
+		if matched == 0 {
+			i++
+			continue
+		}
+		ranges = append(ranges, [2]int{i, i + matched})
+		i += matched
+	}
+	return ranges
+}
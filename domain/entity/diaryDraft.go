@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrDraftVersionConflict is returned when a draft is saved with stale version.
+	ErrDraftVersionConflict = errors.New("draft is already changed by another device")
+	// ErrDraftNotFound is returned when there is no draft on current turn.
+	ErrDraftNotFound = errors.New("there is no draft on current turn")
+)
+
+// DiaryDraft is a diary which is being written by the member on duty.
+// It is stored per (room, member, turn) and promoted into a Diary when it is published.
+type DiaryDraft struct {
+	ID       uint
+	RoomID   uint
+	MemberID uint
+	Turn     uint
+	Version  uint // increased whenever draft is saved, used for optimistic concurrency
+
+	Title string
+	Body  string
+
+	PhotoUUID  string
+	PhotoURL   string
+	AudioUUID  string
+	AudioURL   string
+	AudioTitle string
+	AudioPitch string
+
+	CreatedAt *time.Time
+	UpdatedAt *time.Time
+}
+
+// NewDiaryDraft ...
+func NewDiaryDraft(roomID, memberID, turn uint) *DiaryDraft {
+	return &DiaryDraft{
+		RoomID:   roomID,
+		MemberID: memberID,
+		Turn:     turn,
+	}
+}
+
+// IsEqual guarantees Entity's identity
+func (d *DiaryDraft) IsEqual(other *DiaryDraft) bool {
+	return other.ID == d.ID
+}
+
+// ETag returns draft's version as a http entity tag
+func (d *DiaryDraft) ETag() string {
+	return fmt.Sprintf(`"%d"`, d.Version)
+}
+
+// ParseETag parses http If-Match header value to draft version.
+// Empty value means there is no draft yet (version 0).
+func ParseETag(etag string) (uint, error) {
+	etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+	etag = strings.Trim(etag, `"`)
+	if etag == "" {
+		return 0, nil
+	}
+	version, err := strconv.ParseUint(etag, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid etag '%s'", etag)
+	}
+	return uint(version), nil
+}
+
+// ToDiary converts draft to a diary which is going to be posted.
+func (d *DiaryDraft) ToDiary() (*Diary, error) {
+	diary, err := NewDiary(d.RoomID, d.MemberID, d.Title, d.Body)
+	if err != nil {
+		return nil, err
+	}
+	if d.PhotoUUID != "" {
+		diary.AttachPhoto(d.PhotoUUID, d.PhotoURL)
+	}
+	if d.AudioUUID != "" {
+		diary.AttachAudio(d.AudioUUID, d.AudioURL, d.AudioTitle, d.AudioPitch)
+	}
+	diary.Turn = d.Turn
+	return diary, nil
+}
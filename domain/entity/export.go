@@ -0,0 +1,101 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain"
+)
+
+var (
+	// ErrExportNotFound is returned when export does not exist or belongs to another member.
+	ErrExportNotFound = errors.New("export does not exist")
+	// ErrExportExpired is a failure reason of export which is not finished within its lease. (ex. server restart)
+	ErrExportExpired = errors.New("export is not finished within its lease")
+)
+
+// ExportStatus is a progress of an export job
+type ExportStatus string
+
+const (
+	// ExportPending is a status of export which is requested but not started
+	ExportPending ExportStatus = "PENDING"
+	// ExportRunning is a status of export which is being archived
+	ExportRunning ExportStatus = "RUNNING"
+	// ExportDone is a status of export whose archive is uploaded
+	ExportDone ExportStatus = "DONE"
+	// ExportFailed is a status of export which is failed
+	ExportFailed ExportStatus = "FAILED"
+)
+
+// Export is a ZIP archive job of room's every diary, which is requested by a member.
+type Export struct {
+	ID          uint
+	RoomID      uint
+	MemberID    uint
+	Status      ExportStatus
+	URL         string
+	DownloadURL string
+	Error       string
+
+	CreatedAt  *time.Time
+	UpdatedAt  *time.Time
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+}
+
+// NewExport ...
+func NewExport(roomID, memberID uint) *Export {
+	return &Export{
+		RoomID:   roomID,
+		MemberID: memberID,
+		Status:   ExportPending,
+	}
+}
+
+// IsEqual guarantees Entity's identity
+func (e *Export) IsEqual(other *Export) bool {
+	return other.ID == e.ID
+}
+
+// IsFinished returns whether export is done or failed
+func (e *Export) IsFinished() bool {
+	return e.Status == ExportDone || e.Status == ExportFailed
+}
+
+// IsStale returns whether unfinished export is not finished within lease since it is started. (or requested)
+// A stale export is abandoned by its worker, so it never finishes by itself.
+func (e *Export) IsStale(now time.Time, lease time.Duration) bool {
+	if e.IsFinished() {
+		return false
+	}
+	since := e.StartedAt
+	if since == nil {
+		since = e.CreatedAt
+	}
+	return since != nil && !since.Add(lease).After(now)
+}
+
+// Start ...
+func (e *Export) Start() {
+	now := domain.CurrentDateTime()
+	e.Status = ExportRunning
+	e.StartedAt = &now
+}
+
+// Finish marks export as done with the uploaded archive urls
+func (e *Export) Finish(url, downloadURL string) {
+	now := domain.CurrentDateTime()
+	e.Status = ExportDone
+	e.URL = url
+	e.DownloadURL = downloadURL
+	e.FinishedAt = &now
+}
+
+// Fail marks export as failed with the reason
+func (e *Export) Fail(err error) {
+	now := domain.CurrentDateTime()
+	e.Status = ExportFailed
+	e.Error = err.Error()
+	e.FinishedAt = &now
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExportIsStale(t *testing.T) {
+	now := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	lease := time.Minute * 30
+	before := func(d time.Duration) *time.Time {
+		t := now.Add(-d)
+		return &t
+	}
+
+	tests := []struct {
+		name   string
+		export Export
+		want   bool
+	}{
+		{"fresh pending", Export{Status: ExportPending, CreatedAt: before(time.Minute)}, false},
+		{"stale pending", Export{Status: ExportPending, CreatedAt: before(lease)}, true},
+		{"fresh running", Export{Status: ExportRunning, CreatedAt: before(lease * 2), StartedAt: before(time.Minute)}, false},
+		{"stale running", Export{Status: ExportRunning, CreatedAt: before(lease * 2), StartedAt: before(lease + time.Second)}, true},
+		{"done is never stale", Export{Status: ExportDone, CreatedAt: before(lease * 2)}, false},
+		{"failed is never stale", Export{Status: ExportFailed, CreatedAt: before(lease * 2)}, false},
+		{"unknown request time", Export{Status: ExportPending}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.export.IsStale(now, lease); got != tt.want {
+				t.Errorf("IsStale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// MemberJoinAttemptThreshold is the number of failures of a member on a room before lockout
+	MemberJoinAttemptThreshold = 5
+	// IPJoinAttemptThreshold is the number of failures from an ip (on every room) before lockout
+	IPJoinAttemptThreshold = 20
+
+	joinLockoutBase   = time.Minute
+	joinLockoutMax    = time.Hour * 24
+	joinAttemptWindow = time.Hour * 24 // failures are forgotten after the window without a failure
+)
+
+// ErrRoomJoinLocked is returned when join attempts are locked out by repeated failures.
+var ErrRoomJoinLocked = errors.New("too many failed join attempts, try again later")
+
+// JoinAttempt counts failed room code attempts of a key. (member on a room, or ip)
+// Once failures reach the threshold, every further failure locks the key out exponentially longer.
+type JoinAttempt struct {
+	ID           uint
+	Key          string
+	Failures     uint
+	LockedUntil  *time.Time
+	LastFailedAt *time.Time
+}
+
+// MemberJoinAttemptKey ...
+func MemberJoinAttemptKey(roomID, accountID uint) string {
+	return fmt.Sprintf("room:%d:member:%d", roomID, accountID)
+}
+
+// IPJoinAttemptKey ...
+func IPJoinAttemptKey(ip string) string {
+	return "ip:" + ip
+}
+
+// IsLocked ...
+func (a *JoinAttempt) IsLocked(at time.Time) bool {
+	return a.LockedUntil != nil && at.Before(*a.LockedUntil)
+}
+
+// Fail counts a failure at the time, and returns whether the key is locked out by it.
+// Lockout starts from joinLockoutBase at the threshold and doubles on each failure. (max joinLockoutMax)
+func (a *JoinAttempt) Fail(at time.Time, threshold uint) (locked bool) {
+	if a.LastFailedAt != nil && at.Sub(*a.LastFailedAt) > joinAttemptWindow {
+		a.Failures = 0
+	}
+	a.Failures++
+	a.LastFailedAt = &at
+	if a.Failures < threshold {
+		return false
+	}
+
+	lockout := joinLockoutMax
+	if exp := a.Failures - threshold; exp < 11 {
+		if d := joinLockoutBase << exp; d < joinLockoutMax {
+			lockout = d
+		}
+	}
+	lockedUntil := at.Add(lockout)
+	a.LockedUntil = &lockedUntil
+	return true
+}
+
+// Refund takes back a failure which was counted ahead of a successful attempt.
+// The lockout set by that failure (lockedUntil) is lifted unless a later failure extended it.
+func (a *JoinAttempt) Refund(lockedUntil *time.Time) {
+	if a.Failures > 0 {
+		a.Failures--
+	}
+	if lockedUntil != nil && a.LockedUntil != nil && !a.LockedUntil.After(*lockedUntil) {
+		a.LockedUntil = nil
+	}
+}
@@ -0,0 +1,86 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJoinAttemptFail(t *testing.T) {
+	now := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name            string
+		failures        uint
+		lastFailedAt    time.Time
+		wantLocked      bool
+		wantFailures    uint
+		wantLockoutFrom time.Duration // lockout from now, 0 if not locked
+	}{
+		{"first failure", 0, time.Time{}, false, 1, 0},
+		{"below threshold", 3, now.Add(-time.Minute), false, 4, 0},
+		{"at threshold", 4, now.Add(-time.Minute), true, 5, time.Minute},
+		{"doubles", 5, now.Add(-time.Minute), true, 6, time.Minute * 2},
+		{"doubles again", 7, now.Add(-time.Minute), true, 8, time.Minute * 8},
+		{"capped", 15, now.Add(-time.Minute), true, 16, joinLockoutMax},
+		{"long after threshold does not overflow", 100, now.Add(-time.Minute), true, 101, joinLockoutMax},
+		{"window passed", 10, now.Add(-joinAttemptWindow - time.Second), false, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempt := &JoinAttempt{Failures: tt.failures}
+			if !tt.lastFailedAt.IsZero() {
+				attempt.LastFailedAt = &tt.lastFailedAt
+			}
+			if locked := attempt.Fail(now, MemberJoinAttemptThreshold); locked != tt.wantLocked {
+				t.Errorf("Fail() = %t, want %t", locked, tt.wantLocked)
+			}
+			if attempt.Failures != tt.wantFailures {
+				t.Errorf("Failures = %d, want %d", attempt.Failures, tt.wantFailures)
+			}
+			if tt.wantLockoutFrom == 0 {
+				if attempt.IsLocked(now) {
+					t.Errorf("locked until %s, want not locked", attempt.LockedUntil)
+				}
+				return
+			}
+			if want := now.Add(tt.wantLockoutFrom); attempt.LockedUntil == nil || !attempt.LockedUntil.Equal(want) {
+				t.Errorf("LockedUntil = %v, want %s", attempt.LockedUntil, want)
+			}
+			if !attempt.IsLocked(now) || attempt.IsLocked(now.Add(tt.wantLockoutFrom)) {
+				t.Errorf("IsLocked() is wrong around %s", attempt.LockedUntil)
+			}
+		})
+	}
+}
+
+func TestJoinAttemptRefund(t *testing.T) {
+	now := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	reserved := now.Add(time.Minute)
+	extended := now.Add(time.Minute * 2)
+	tests := []struct {
+		name            string
+		failures        uint
+		lockedUntil     *time.Time
+		refunded        *time.Time // lockout set by the refunded failure
+		wantFailures    uint
+		wantLockedUntil *time.Time
+	}{
+		{"not locked", 3, nil, nil, 2, nil},
+		{"lockout of the refunded failure is lifted", 5, &reserved, &reserved, 4, nil},
+		{"lockout extended by a later failure is kept", 6, &extended, &reserved, 5, &extended},
+		{"lockout of another failure is kept", 6, &extended, nil, 5, &extended},
+		{"no failures", 0, nil, nil, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempt := &JoinAttempt{Failures: tt.failures, LockedUntil: tt.lockedUntil}
+			attempt.Refund(tt.refunded)
+			if attempt.Failures != tt.wantFailures {
+				t.Errorf("Failures = %d, want %d", attempt.Failures, tt.wantFailures)
+			}
+			if (attempt.LockedUntil == nil) != (tt.wantLockedUntil == nil) ||
+				(attempt.LockedUntil != nil && !attempt.LockedUntil.Equal(*tt.wantLockedUntil)) {
+				t.Errorf("LockedUntil = %v, want %v", attempt.LockedUntil, tt.wantLockedUntil)
+			}
+		})
+	}
+}
@@ -1,7 +1,20 @@
 package entity
 
 import (
+	"errors"
 	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/vo"
+)
+
+// DefaultTimeZone is a time zone of member who has not set it
+const DefaultTimeZone = "Asia/Seoul"
+
+var (
+	// ErrMemberNotFound ...
+	ErrMemberNotFound = errors.New("member does not exist")
+	// ErrInvalidTimeZone is returned when time zone is not an IANA time zone name. (ex. Asia/Seoul)
+	ErrInvalidTimeZone = errors.New("invalid IANA time zone")
 )
 
 // Member ...
@@ -12,6 +25,11 @@ type Member struct {
 	ProfileURL string
 	AuthType   string
 	AlarmFlag  bool
+	// ReminderOptOuts are reminder offsets which member does not want to be reminded at (every room)
+	ReminderOptOuts []time.Duration
+	// TimeZone is an IANA time zone name which QuietHours is based on
+	TimeZone   string
+	QuietHours vo.QuietHours
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
 }
@@ -27,6 +45,7 @@ func NewMember(email, name, profileURL string, authType string) (*Member, error)
 		ProfileURL: profileURL,
 		AuthType:   authType,
 		AlarmFlag:  true,
+		TimeZone:   DefaultTimeZone,
 	}, nil
 }
 
@@ -35,6 +54,46 @@ func (a *Member) IsEqual(other *Member) bool {
 	return (other.ID == a.ID) && (other.Email == a.Email)
 }
 
+// IsReminderOptedOut returns whether member opted out of reminders at the offset
+func (a *Member) IsReminderOptedOut(offset time.Duration) bool {
+	for _, optOut := range a.ReminderOptOuts {
+		if optOut == offset {
+			return true
+		}
+	}
+	return false
+}
+
+// SetTimeZone ...
+func (a *Member) SetTimeZone(name string) error {
+	if name == "" {
+		return ErrInvalidTimeZone
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return ErrInvalidTimeZone
+	}
+	a.TimeZone = name
+	return nil
+}
+
+// Location returns member's time zone location
+func (a *Member) Location() *time.Location {
+	name := a.TimeZone
+	if name == "" {
+		name = DefaultTimeZone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// QuietUntil returns the end of member's quiet hours, if t is in quiet hours.
+func (a *Member) QuietUntil(t time.Time) (time.Time, bool) {
+	return a.QuietHours.Until(t.In(a.Location()))
+}
+
 // IsNil check member is nil
 func (a *Member) IsNil() bool {
 	isNull := 0
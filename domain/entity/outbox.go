@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"time"
+)
+
+const (
+	outboxMaxAttempts = 10
+	outboxBaseBackoff = time.Second
+	outboxMaxBackoff  = time.Minute * 10
+)
+
+// OutboxAction is an intent of scheduled task which is delivered to scheduler
+type OutboxAction string
+
+const (
+	// OutboxSchedule registers a task
+	OutboxSchedule OutboxAction = "SCHEDULE"
+	// OutboxCancel removes a task
+	OutboxCancel OutboxAction = "CANCEL"
+)
+
+// OutboxMessage is a scheduled task intent, which is written in the same transaction with the state change.
+// Relay delivers it to scheduler later, so a failure of scheduler never leaves the state half-changed.
+type OutboxMessage struct {
+	ID            uint
+	Action        OutboxAction
+	Task          ScheduledTask
+	Attempts      uint
+	NextAttemptAt time.Time
+	LockedUntil   *time.Time
+	LastError     string
+
+	CreatedAt   *time.Time
+	DeliveredAt *time.Time
+	FailedAt    *time.Time
+}
+
+// NewScheduleMessage ...
+// Every schedule intent is a new instance of the task, so a task id can be cancelled and registered again right away.
+// Redelivery of the message registers the same instance.
+func NewScheduleMessage(task *ScheduledTask) *OutboxMessage {
+	message := &OutboxMessage{
+		Action:        OutboxSchedule,
+		Task:          *task,
+		NextAttemptAt: time.Now(),
+	}
+	message.Task.Instance = NewTaskInstance()
+	return message
+}
+
+// NewCancelMessage ...
+func NewCancelMessage(taskID string) *OutboxMessage {
+	return &OutboxMessage{
+		Action:        OutboxCancel,
+		Task:          ScheduledTask{ID: taskID},
+		NextAttemptAt: time.Now(),
+	}
+}
+
+// Deliver marks message as delivered.
+func (m *OutboxMessage) Deliver(now time.Time) {
+	m.Attempts++
+	m.LastError = ""
+	m.DeliveredAt = &now
+}
+
+// Retry postpones message with exponential backoff.
+// After outboxMaxAttempts, message is marked as failed and never retried.
+func (m *OutboxMessage) Retry(err error, now time.Time) {
+	m.Attempts++
+	m.LastError = err.Error()
+	if m.Attempts >= outboxMaxAttempts {
+		m.FailedAt = &now
+		return
+	}
+	backoff := outboxBaseBackoff << (m.Attempts - 1)
+	if backoff > outboxMaxBackoff {
+		backoff = outboxMaxBackoff
+	}
+	m.NextAttemptAt = now.Add(backoff)
+}
@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOutboxMessageRetry(t *testing.T) {
+	now := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		attempts    uint
+		wantBackoff time.Duration
+		wantFailed  bool
+	}{
+		{"first failure", 0, time.Second, false},
+		{"third failure", 2, time.Second * 4, false},
+		{"ninth failure", 8, time.Second * 256, false},
+		{"last attempt", outboxMaxAttempts - 1, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := &OutboxMessage{Attempts: tt.attempts}
+			message.Retry(errors.New("boom"), now)
+
+			if message.Attempts != tt.attempts+1 || message.LastError != "boom" {
+				t.Fatalf("attempt is not recorded: %+v", message)
+			}
+			if failed := message.FailedAt != nil; failed != tt.wantFailed {
+				t.Fatalf("failed = %v, want %v", failed, tt.wantFailed)
+			}
+			if !tt.wantFailed && !message.NextAttemptAt.Equal(now.Add(tt.wantBackoff)) {
+				t.Fatalf("next attempt = %v, want %v", message.NextAttemptAt, now.Add(tt.wantBackoff))
+			}
+		})
+	}
+}
+
+func TestOutboxMessageDeliver(t *testing.T) {
+	now := time.Now()
+	message := &OutboxMessage{Attempts: 1, LastError: "boom"}
+	message.Deliver(now)
+	if message.DeliveredAt == nil || !message.DeliveredAt.Equal(now) || message.LastError != "" || message.Attempts != 2 {
+		t.Fatalf("message is not delivered: %+v", message)
+	}
+}
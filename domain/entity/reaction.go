@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrInvalidEmoji is returned when emoji is not one of the reaction emoji set.
+	ErrInvalidEmoji = errors.New("invalid reaction emoji")
+	// ErrReactionNotFound is returned when member has not reacted on the diary.
+	ErrReactionNotFound = errors.New("there is no reaction of the member")
+)
+
+// Emoji is a code of reaction emoji
+type Emoji string
+
+const (
+	// HeartEmoji ❤️
+	HeartEmoji Emoji = "HEART"
+	// LaughEmoji 😂
+	LaughEmoji Emoji = "LAUGH"
+	// SadEmoji 😢
+	SadEmoji Emoji = "SAD"
+	// SurpriseEmoji 😮
+	SurpriseEmoji Emoji = "SURPRISE"
+	// ThumbsUpEmoji 👍
+	ThumbsUpEmoji Emoji = "THUMBS_UP"
+)
+
+// Emojis is a fixed emoji set which member can react with
+var Emojis = []Emoji{HeartEmoji, LaughEmoji, SadEmoji, SurpriseEmoji, ThumbsUpEmoji}
+
+// ParseEmoji validates emoji code
+func ParseEmoji(code string) (Emoji, error) {
+	for _, emoji := range Emojis {
+		if string(emoji) == code {
+			return emoji, nil
+		}
+	}
+	return "", ErrInvalidEmoji
+}
+
+// Reaction is a member's emoji reaction on a diary.
+// A member can react only once per diary.
+type Reaction struct {
+	ID        uint
+	DiaryID   uint
+	MemberID  uint
+	Emoji     Emoji
+	CreatedAt *time.Time
+}
+
+// ReactionCounts is a number of reactions per emoji
+type ReactionCounts map[Emoji]uint
+
+// NewReaction ...
+func NewReaction(diaryID, memberID uint, emoji Emoji) *Reaction {
+	return &Reaction{
+		DiaryID:  diaryID,
+		MemberID: memberID,
+		Emoji:    emoji,
+	}
+}
@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"time"
+)
+
+// ReadMarker tracks the last diary which member has read in a room.
+type ReadMarker struct {
+	ID              uint
+	MemberID        uint
+	RoomID          uint
+	LastReadDiaryID uint
+	UpdatedAt       *time.Time
+}
+
+// NewReadMarker ...
+func NewReadMarker(memberID, roomID, lastReadDiaryID uint) *ReadMarker {
+	return &ReadMarker{
+		MemberID:        memberID,
+		RoomID:          roomID,
+		LastReadDiaryID: lastReadDiaryID,
+	}
+}
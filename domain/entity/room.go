@@ -2,32 +2,79 @@ package entity
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/ExchangeDiary/exchange-diary/domain"
 	"github.com/jinzhu/copier"
+	"golang.org/x/crypto/bcrypt"
 )
 
-const maxRoomMemberCount = 10
+const (
+	maxRoomMemberCount     = 10
+	maxReminderOffsetCount = 5
+)
+
+// DefaultReminderOffsets are reminder offsets of a new room (and a room which has never set them)
+var DefaultReminderOffsets = []time.Duration{time.Hour * 4, time.Hour * 1}
+
+var (
+	// ErrNotJoinedRoom is returned when account is neither master nor member of the room.
+	ErrNotJoinedRoom = errors.New("Only member or master can access")
+	// ErrNotRoomMaster is returned when account is not master of the room.
+	ErrNotRoomMaster = errors.New("Only master can access")
+	// ErrRoomNotFound ...
+	ErrRoomNotFound = errors.New("room does not exist")
+	// ErrAlreadyJoinedRoom ...
+	ErrAlreadyJoinedRoom = errors.New("Already joined room")
+	// ErrInvalidRoomCode ...
+	ErrInvalidRoomCode = errors.New("Invalid code is given")
+	// ErrRoomMemberFull is returned when a room has no more seat for a new member.
+	ErrRoomMemberFull = fmt.Errorf("The total number of member in a room cannot exceed %d, this count includes master(1)", maxRoomMemberCount)
+	// ErrInvalidAwayUntil is returned when away until is not in the future.
+	ErrInvalidAwayUntil = errors.New("away until must be in the future")
+	// ErrInvalidReminderOffsets is returned when reminder offsets are not unique minutes within the period.
+	ErrInvalidReminderOffsets = fmt.Errorf("reminder offsets must be unique minutes shorter than the period (max %d)", maxReminderOffsetCount)
+	// ErrRoomArchived is returned when a write is requested to an archived (read-only) room.
+	ErrRoomArchived = errors.New("room is archived")
+	// ErrRoomNotArchived ...
+	ErrRoomNotArchived = errors.New("room is not archived")
+	// ErrInvalidRoomEndAt is returned when end date is not after the first due date.
+	ErrInvalidRoomEndAt = errors.New("end date must be after the first due date")
+)
 
 // Room ...
 type Room struct {
 	ID     uint
 	Name   string
-	Code   string
+	Code   string // bcrypt hash of join code. (never plaintext)
 	Hint   string
 	Theme  string
 	Period uint8
 
 	MasterID      uint
 	TurnAccountID uint
+	Turn          uint   // sequence number of current turn, starts from 1
 	Orders        []uint // master + roomMembers
+	// SwappedOrders are Orders of current cycle, in which accepted swaps are applied. (nil if there is no swap)
+	// NextTurn follows them until the cycle ends, then they are dropped. So a swap never changes Orders.
+	SwappedOrders []uint
 	Members       *Members
 
-	DueAt     *time.Time
-	CreatedAt *time.Time
-	UpdatedAt *time.Time
+	// ReminderOffsets are durations before DueAt when turn member is reminded. (longest first)
+	ReminderOffsets []time.Duration
+	// AwayUntil maps account id to the time until which the member is away. Away members are skipped on NextTurn.
+	AwayUntil   map[uint]time.Time
+	UnreadCount uint       // number of diaries which current member has not read yet (not persisted)
+	LastDiaryAt *time.Time // created time of the latest diary (not persisted)
+
+	DueAt      *time.Time
+	EndAt      *time.Time // the room is archived at the first turn change after EndAt. (nil for no end)
+	ArchivedAt *time.Time // archived room stops turn rotation and becomes read-only
+	CreatedAt  *time.Time
+	UpdatedAt  *time.Time
 }
 
 // Rooms ...
@@ -38,19 +85,56 @@ func NewRoom(masterID uint, name, code, hint, theme string, period uint8) (*Room
 	orders := []uint{masterID}
 	// dueAt = now + period
 	dueAt := domain.CurrentDateTime().Add(PeriodToDuration(period))
+	hashedCode, err := HashRoomCode(code)
+	if err != nil {
+		return nil, err
+	}
 	return &Room{
-		Name:          name,
-		Code:          code,
-		Hint:          hint,
-		Theme:         theme,
-		Period:        period,
-		MasterID:      masterID,
-		TurnAccountID: masterID,
-		Orders:        orders,
-		DueAt:         &dueAt,
+		Name:            name,
+		Code:            hashedCode,
+		Hint:            hint,
+		Theme:           theme,
+		Period:          period,
+		MasterID:        masterID,
+		TurnAccountID:   masterID,
+		Turn:            1,
+		Orders:          orders,
+		ReminderOffsets: append([]time.Duration{}, DefaultReminderOffsets...),
+		AwayUntil:       map[uint]time.Time{},
+		DueAt:           &dueAt,
 	}, nil
 }
 
+// HashRoomCode hashes plaintext join code to be stored.
+func HashRoomCode(code string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// IsHashedRoomCode returns whether stored code is already hashed. (rooms before hashing have plaintext code)
+func IsHashedRoomCode(code string) bool {
+	_, err := bcrypt.Cost([]byte(code))
+	return err == nil
+}
+
+// SetCode changes join code of the room
+func (r *Room) SetCode(code string) error {
+	hashedCode, err := HashRoomCode(code)
+	if err != nil {
+		return err
+	}
+	r.Code = hashedCode
+	return nil
+}
+
+// VerifyCode compares plaintext code with the room's code in constant time
+func (r *Room) VerifyCode(code string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(r.Code), []byte(code)) == nil
+}
+
 // IsEqual guarantees Entity's identity
 func (r *Room) IsEqual(other *Room) bool {
 	return other.ID == r.ID
@@ -76,9 +160,61 @@ func (r *Room) IsMemberFull() bool {
 	return len(r.Orders) >= maxRoomMemberCount
 }
 
+// IsArchived returns whether the room is archived (read-only)
+func (r *Room) IsArchived() bool {
+	return r.ArchivedAt != nil
+}
+
+// IsEnded returns whether the room has reached its end date at the time
+func (r *Room) IsEnded(at time.Time) bool {
+	return r.EndAt != nil && !at.Before(*r.EndAt)
+}
+
+// SetEndAt sets end date of the room. nil end clears it.
+func (r *Room) SetEndAt(endAt *time.Time) error {
+	if endAt != nil && r.DueAt != nil && !endAt.After(*r.DueAt) {
+		return ErrInvalidRoomEndAt
+	}
+	r.EndAt = endAt
+	return nil
+}
+
+// Archive stops turn rotation of the room and makes it read-only.
+func (r *Room) Archive(at time.Time) error {
+	if r.IsArchived() {
+		return ErrRoomArchived
+	}
+	r.ArchivedAt = &at
+	return nil
+}
+
+// Unarchive reopens the room. Current turn member gets a whole period from now.
+// Passed end date is cleared, otherwise the room would be archived again right away.
+func (r *Room) Unarchive(now time.Time) error {
+	if !r.IsArchived() {
+		return ErrRoomNotArchived
+	}
+	r.ArchivedAt = nil
+	if r.IsEnded(now) {
+		r.EndAt = nil
+	}
+	dueAt := now.Add(PeriodToDuration(r.Period))
+	r.DueAt = &dueAt
+	return nil
+}
+
 // AppendMember ...
 func (r *Room) AppendMember(accountID uint) {
 	r.Orders = append(r.Orders, accountID)
+	if r.SwappedOrders != nil {
+		r.SwappedOrders = append(r.SwappedOrders, accountID)
+	}
+}
+
+// SetOrders replaces Orders. A swap of current cycle is dropped, since it is made on the previous orders.
+func (r *Room) SetOrders(orders []uint) {
+	r.Orders = orders
+	r.SwappedOrders = nil
 }
 
 // RemoveMember ...
@@ -90,9 +226,47 @@ func (r *Room) RemoveMember(accountID uint) (uint, error) {
 	if accountID == 0 {
 		return 0, fmt.Errorf("There is no matched accountID from room.Orders")
 	}
+	if r.SwappedOrders != nil {
+		r.SwappedOrders, _ = domain.Remove(r.SwappedOrders, accountID)
+	}
+	delete(r.AwayUntil, accountID)
 	return accountID, nil
 }
 
+// SwapOrders exchanges positions of two members for the rest of current cycle. It is applied from the next turn.
+// Orders are not changed, so the next cycle follows Orders again.
+// Only members who have not written in current cycle can be swapped, since the next turn is decided by the position of the member on duty.
+func (r *Room) SwapOrders(a, b uint) error {
+	if r.IsTurn(a) || r.IsTurn(b) {
+		return ErrSwapOnDuty
+	}
+	orders := append([]uint{}, r.CycleOrders()...)
+	ai, bi := domain.Index(orders, a), domain.Index(orders, b)
+	if ai < 0 || bi < 0 || a == b {
+		return ErrNotJoinedRoom
+	}
+	if !r.IsUpcoming(a) || !r.IsUpcoming(b) {
+		return ErrSwapPassed
+	}
+	orders[ai], orders[bi] = orders[bi], orders[ai]
+	r.SwappedOrders = orders
+	return nil
+}
+
+// CycleOrders returns orders of current cycle. (swapped orders if there is a swap)
+func (r *Room) CycleOrders() []uint {
+	if r.SwappedOrders != nil {
+		return r.SwappedOrders
+	}
+	return r.Orders
+}
+
+// IsUpcoming returns whether the member has not written yet in current cycle. The member on duty is not upcoming.
+func (r *Room) IsUpcoming(accountID uint) bool {
+	orders := r.CycleOrders()
+	return domain.Index(orders, accountID) > domain.Index(orders, r.TurnAccountID)
+}
+
 // ChangeMaster ...
 func (r *Room) ChangeMaster() error {
 	nextCandidateIdx := 1
@@ -113,6 +287,58 @@ func (r *Room) OrdersToJSON() ([]byte, error) {
 	return []byte(orderJSON), nil
 }
 
+// SetReminderOffsets validates and sets reminder offsets in longest first order.
+func (r *Room) SetReminderOffsets(offsets []time.Duration) error {
+	if len(offsets) > maxReminderOffsetCount {
+		return ErrInvalidReminderOffsets
+	}
+	sorted := append([]time.Duration{}, offsets...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] > sorted[j] })
+	for i, offset := range sorted {
+		if offset < time.Minute || offset%time.Minute != 0 || offset >= PeriodToDuration(r.Period) {
+			return ErrInvalidReminderOffsets
+		}
+		if i > 0 && sorted[i-1] == offset {
+			return ErrInvalidReminderOffsets
+		}
+	}
+	r.ReminderOffsets = sorted
+	return nil
+}
+
+// HasReminderOffset returns whether the room reminds turn member at the offset
+func (r *Room) HasReminderOffset(offset time.Duration) bool {
+	for _, o := range r.ReminderOffsets {
+		if o == offset {
+			return true
+		}
+	}
+	return false
+}
+
+// ReminderOffsetsToJSON marshals reminder offsets to []byte json of minutes.
+func (r *Room) ReminderOffsetsToJSON() ([]byte, error) {
+	return json.Marshal(DurationsToMinutes(r.ReminderOffsets))
+}
+
+// DurationsToMinutes converts durations to minutes
+func DurationsToMinutes(durations []time.Duration) []uint {
+	minutes := []uint{}
+	for _, d := range durations {
+		minutes = append(minutes, uint(d/time.Minute))
+	}
+	return minutes
+}
+
+// MinutesToDurations converts minutes to durations
+func MinutesToDurations(minutes []uint) []time.Duration {
+	durations := []time.Duration{}
+	for _, m := range minutes {
+		durations = append(durations, time.Duration(m)*time.Minute)
+	}
+	return durations
+}
+
 // MemberOnlyOrders returns master excluded memberIDs
 func (r *Room) MemberOnlyOrders() ([]uint, error) {
 	var orders []uint
@@ -140,31 +366,74 @@ func (r *Room) MemberAllExceptTurnAccount() []uint {
 }
 
 // NextTurn set room.TurnAccountID to next-turnAccountID and return it.
+// Away members are skipped. If every member is away, the turn follows the original order.
+// The turn follows CycleOrders until the cycle ends, and a swap is dropped when the next cycle starts.
 func (r *Room) NextTurn() (nextTurnAccountID uint) {
-	curTurnAccountID := r.TurnAccountID
-
+	r.Turn++
+	orders := r.CycleOrders()
+	curIdx := domain.Index(orders, r.TurnAccountID)
 	if len(r.Orders) == 1 {
-		nextTurnAccountID = curTurnAccountID
-		return
+		r.SwappedOrders = nil
+		return r.TurnAccountID
 	}
 
-	var orders []uint
-	copier.Copy(&orders, &r.Orders)
-	for i, accountID := range orders {
-		if accountID == curTurnAccountID {
-			if i == len(r.Orders)-1 {
-				nextTurnAccountID = orders[0]
-			} else {
-				nextTurnAccountID = orders[i+1]
-			}
+	// account id of the member after step, which is in Orders once the next cycle starts.
+	after := func(step int) uint {
+		if i := curIdx + step; i < len(orders) {
+			return orders[i]
+		}
+		return r.Orders[(curIdx+step)%len(r.Orders)]
+	}
+	now := domain.CurrentDateTime()
+	nextStep := 1
+	for step := 1; step <= len(orders); step++ {
+		if !r.IsAway(after(step), now) {
+			nextStep = step
 			break
 		}
 	}
+	nextTurnAccountID = after(nextStep)
+	if curIdx+nextStep >= len(orders) {
+		r.SwappedOrders = nil
+	}
 	// set entity new turnAccountID
 	r.TurnAccountID = nextTurnAccountID
 	return
 }
 
+// IsAway returns whether the member is away at the time
+func (r *Room) IsAway(accountID uint, at time.Time) bool {
+	until, ok := r.AwayUntil[accountID]
+	return ok && at.Before(until)
+}
+
+// SetAway sets the member away until the time. nil until comes back right away.
+// Expired away settings are removed.
+func (r *Room) SetAway(accountID uint, until *time.Time, now time.Time) error {
+	if until != nil && !until.After(now) {
+		return ErrInvalidAwayUntil
+	}
+	away := map[uint]time.Time{}
+	for id, u := range r.AwayUntil {
+		if id != accountID && now.Before(u) {
+			away[id] = u
+		}
+	}
+	if until != nil {
+		away[accountID] = *until
+	}
+	r.AwayUntil = away
+	return nil
+}
+
+// AwayUntilToJSON marshals away settings to []byte json. (key is account id)
+func (r *Room) AwayUntilToJSON() ([]byte, error) {
+	if r.AwayUntil == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(r.AwayUntil)
+}
+
 // BeforeDueAt returns (current_room_due_at - oldPeriod)
 // It is used for change period
 func (r *Room) BeforeDueAt() *time.Time {
@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"errors"
+)
+
+var (
+	// ErrInvalidRoomTheme is returned when theme is not in the room catalog.
+	ErrInvalidRoomTheme = errors.New("theme is not in the room catalog")
+	// ErrInvalidRoomPeriod is returned when period is not in the room catalog.
+	ErrInvalidRoomPeriod = errors.New("period is not in the room catalog")
+)
+
+// RoomTheme is a theme of room which the master can choose. Room.Theme is an ID of RoomTheme.
+type RoomTheme struct {
+	ID            string
+	Name          string
+	Hashtag       string // ex. #새벽감성
+	BackgroundURL string
+}
+
+// RoomCatalog is a server managed list of themes and periods (days) which rooms can have.
+type RoomCatalog struct {
+	Themes  []RoomTheme
+	Periods []uint8
+}
+
+// Theme finds a theme by id
+func (c *RoomCatalog) Theme(id string) (*RoomTheme, bool) {
+	for i := range c.Themes {
+		if c.Themes[i].ID == id {
+			return &c.Themes[i], true
+		}
+	}
+	return nil, false
+}
+
+// HasPeriod ...
+func (c *RoomCatalog) HasPeriod(period uint8) bool {
+	for _, p := range c.Periods {
+		if p == period {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks theme and period of a room. Empty theme or zero period is not checked. (ex. unchanged field on patch)
+func (c *RoomCatalog) Validate(theme string, period uint8) error {
+	if theme != "" {
+		if _, ok := c.Theme(theme); !ok {
+			return ErrInvalidRoomTheme
+		}
+	}
+	if period != 0 && !c.HasPeriod(period) {
+		return ErrInvalidRoomPeriod
+	}
+	return nil
+}
+
+// RoomThemeGroup is rooms of a theme
+type RoomThemeGroup struct {
+	Theme RoomTheme
+	Rooms Rooms
+}
+
+// GroupRooms buckets rooms by theme. Groups follow the catalog order, and themes which are not in the catalog come last.
+// Rooms keep their order in each group, and themes without room are omitted.
+func (c *RoomCatalog) GroupRooms(rooms Rooms) []RoomThemeGroup {
+	groups := []RoomThemeGroup{}
+	index := map[string]int{}
+	for _, theme := range c.Themes {
+		index[theme.ID] = len(groups)
+		groups = append(groups, RoomThemeGroup{Theme: theme, Rooms: Rooms{}})
+	}
+	for _, room := range rooms {
+		i, ok := index[room.Theme]
+		if !ok {
+			i = len(groups)
+			index[room.Theme] = i
+			groups = append(groups, RoomThemeGroup{Theme: RoomTheme{ID: room.Theme}, Rooms: Rooms{}})
+		}
+		groups[i].Rooms = append(groups[i].Rooms, room)
+	}
+
+	nonEmpty := []RoomThemeGroup{}
+	for _, group := range groups {
+		if len(group.Rooms) > 0 {
+			nonEmpty = append(nonEmpty, group)
+		}
+	}
+	return nonEmpty
+}
@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRoomCatalogGroupRooms(t *testing.T) {
+	catalog := &RoomCatalog{Themes: []RoomTheme{{ID: "1", Name: "daily"}, {ID: "2", Name: "travel"}, {ID: "3", Name: "love"}}}
+	tests := []struct {
+		name  string
+		rooms Rooms
+		want  map[string][]uint // theme id -> room ids, in group order
+		order []string
+	}{
+		{"no rooms", Rooms{}, map[string][]uint{}, []string{}},
+		{"catalog order, rooms keep their order",
+			Rooms{{ID: 1, Theme: "2"}, {ID: 2, Theme: "1"}, {ID: 3, Theme: "2"}},
+			map[string][]uint{"1": {2}, "2": {1, 3}}, []string{"1", "2"}},
+		{"unknown themes come last in order of appearance",
+			Rooms{{ID: 1, Theme: "old"}, {ID: 2, Theme: "3"}, {ID: 3, Theme: ""}, {ID: 4, Theme: "old"}},
+			map[string][]uint{"3": {2}, "old": {1, 4}, "": {3}}, []string{"3", "old", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups := catalog.GroupRooms(tt.rooms)
+			order := []string{}
+			for _, group := range groups {
+				order = append(order, group.Theme.ID)
+				ids := []uint{}
+				for _, room := range group.Rooms {
+					ids = append(ids, room.ID)
+				}
+				if !reflect.DeepEqual(ids, tt.want[group.Theme.ID]) {
+					t.Errorf("theme %q rooms = %v, want %v", group.Theme.ID, ids, tt.want[group.Theme.ID])
+				}
+				if theme, ok := catalog.Theme(group.Theme.ID); ok && group.Theme.Name != theme.Name {
+					t.Errorf("theme %q name = %q, want %q", group.Theme.ID, group.Theme.Name, theme.Name)
+				}
+			}
+			if !reflect.DeepEqual(order, tt.order) {
+				t.Errorf("groups = %v, want %v", order, tt.order)
+			}
+		})
+	}
+}
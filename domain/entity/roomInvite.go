@@ -0,0 +1,122 @@
+package entity
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	// MaxRoomInviteTTL is the longest lifetime of an invite link
+	MaxRoomInviteTTL = time.Hour * 24 * 30
+	// DefaultRoomInviteTTL ...
+	DefaultRoomInviteTTL = time.Hour * 24 * 7
+)
+
+var (
+	// ErrRoomInviteNotFound ...
+	ErrRoomInviteNotFound = errors.New("invite does not exist")
+	// ErrInvalidRoomInvite is returned when an invite token is malformed or not signed by us.
+	ErrInvalidRoomInvite = errors.New("invite token is invalid")
+	// ErrRoomInviteExpired ...
+	ErrRoomInviteExpired = errors.New("invite is expired")
+	// ErrRoomInviteRevoked ...
+	ErrRoomInviteRevoked = errors.New("invite is revoked")
+	// ErrRoomInviteExhausted is returned when an invite is used as many as its max uses.
+	ErrRoomInviteExhausted = errors.New("invite is used up")
+	// ErrInvalidRoomInviteOption is returned when ttl or max uses of a new invite is out of range.
+	ErrInvalidRoomInviteOption = fmt.Errorf("invite ttl must be within %s and max uses within %d", MaxRoomInviteTTL, maxRoomMemberCount-1)
+)
+
+// RoomInvite is an invite link of a room issued by the master.
+// The link carries a signed token of RoomInviteClaims, and the invite itself keeps its usage and revocation.
+// Nonce is a random value of the invite, so that a token is not guessable from sequential ids.
+type RoomInvite struct {
+	ID        uint
+	RoomID    uint
+	IssuerID  uint
+	Nonce     string
+	MaxUses   uint
+	UseCount  uint
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+	CreatedAt *time.Time
+}
+
+// RoomInviteClaims are claims of an invite token
+type RoomInviteClaims struct {
+	InviteID uint   `json:"invite_id"`
+	RoomID   uint   `json:"room_id"`
+	Nonce    string `json:"nonce"`
+	jwt.StandardClaims
+}
+
+// NewRoomInvite ...
+func NewRoomInvite(roomID, issuerID uint, ttl time.Duration, maxUses uint, now time.Time) (*RoomInvite, error) {
+	if ttl <= 0 || ttl > MaxRoomInviteTTL || maxUses == 0 || maxUses >= maxRoomMemberCount {
+		return nil, ErrInvalidRoomInviteOption
+	}
+	return &RoomInvite{
+		RoomID:    roomID,
+		IssuerID:  issuerID,
+		Nonce:     newRoomInviteNonce(),
+		MaxUses:   maxUses,
+		ExpiresAt: now.Add(ttl),
+	}, nil
+}
+
+func newRoomInviteNonce() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// IsIssuedBy returns whether claims are of this invite.
+// Invites without nonce (issued before nonce) match no claims.
+func (i *RoomInvite) IsIssuedBy(claims *RoomInviteClaims) bool {
+	return i.Nonce != "" && claims.InviteID == i.ID && claims.RoomID == i.RoomID && claims.Nonce == i.Nonce
+}
+
+// Validate returns why the invite cannot be used at the time, or nil if it can.
+func (i *RoomInvite) Validate(at time.Time) error {
+	switch {
+	case i.RevokedAt != nil:
+		return ErrRoomInviteRevoked
+	case !at.Before(i.ExpiresAt):
+		return ErrRoomInviteExpired
+	case i.UseCount >= i.MaxUses:
+		return ErrRoomInviteExhausted
+	}
+	return nil
+}
+
+// Revoke ...
+func (i *RoomInvite) Revoke(at time.Time) error {
+	if i.RevokedAt != nil {
+		return ErrRoomInviteRevoked
+	}
+	i.RevokedAt = &at
+	return nil
+}
+
+// Claims returns claims of the invite token. The same invite always gives the same claims.
+func (i *RoomInvite) Claims() RoomInviteClaims {
+	var issuedAt int64
+	if i.CreatedAt != nil {
+		issuedAt = i.CreatedAt.Unix()
+	}
+	return RoomInviteClaims{
+		InviteID: i.ID,
+		RoomID:   i.RoomID,
+		Nonce:    i.Nonce,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: i.ExpiresAt.Unix(),
+			IssuedAt:  issuedAt,
+			Issuer:    "exchange-diary",
+		},
+	}
+}
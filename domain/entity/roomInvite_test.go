@@ -0,0 +1,95 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewRoomInvite(t *testing.T) {
+	now := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		maxUses uint
+		wantErr error
+	}{
+		{"default", DefaultRoomInviteTTL, 1, nil},
+		{"max ttl", MaxRoomInviteTTL, maxRoomMemberCount - 1, nil},
+		{"zero ttl", 0, 1, ErrInvalidRoomInviteOption},
+		{"too long ttl", MaxRoomInviteTTL + time.Second, 1, ErrInvalidRoomInviteOption},
+		{"zero max uses", DefaultRoomInviteTTL, 0, ErrInvalidRoomInviteOption},
+		{"too many max uses", DefaultRoomInviteTTL, maxRoomMemberCount, ErrInvalidRoomInviteOption},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invite, err := NewRoomInvite(1, 2, tt.ttl, tt.maxUses, now)
+			if err != tt.wantErr {
+				t.Fatalf("NewRoomInvite() = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !invite.ExpiresAt.Equal(now.Add(tt.ttl)) {
+				t.Errorf("ExpiresAt = %s, want %s", invite.ExpiresAt, now.Add(tt.ttl))
+			}
+			if len(invite.Nonce) != 32 {
+				t.Errorf("Nonce = %q, want 32 hex characters", invite.Nonce)
+			}
+		})
+	}
+
+	a, _ := NewRoomInvite(1, 2, DefaultRoomInviteTTL, 1, now)
+	b, _ := NewRoomInvite(1, 2, DefaultRoomInviteTTL, 1, now)
+	if a.Nonce == b.Nonce {
+		t.Errorf("invites have the same nonce %q", a.Nonce)
+	}
+}
+
+func TestRoomInviteValidate(t *testing.T) {
+	now := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	revokedAt := now.Add(-time.Hour)
+	tests := []struct {
+		name   string
+		invite RoomInvite
+		want   error
+	}{
+		{"valid", RoomInvite{MaxUses: 2, UseCount: 1, ExpiresAt: now.Add(time.Second)}, nil},
+		{"revoked", RoomInvite{MaxUses: 2, ExpiresAt: now.Add(time.Hour), RevokedAt: &revokedAt}, ErrRoomInviteRevoked},
+		{"expired at the moment", RoomInvite{MaxUses: 2, ExpiresAt: now}, ErrRoomInviteExpired},
+		{"expired", RoomInvite{MaxUses: 2, ExpiresAt: now.Add(-time.Second)}, ErrRoomInviteExpired},
+		{"used up", RoomInvite{MaxUses: 2, UseCount: 2, ExpiresAt: now.Add(time.Hour)}, ErrRoomInviteExhausted},
+		{"revoked first", RoomInvite{MaxUses: 1, UseCount: 1, ExpiresAt: now, RevokedAt: &revokedAt}, ErrRoomInviteRevoked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.invite.Validate(now); got != tt.want {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoomInviteIsIssuedBy(t *testing.T) {
+	invite := RoomInvite{ID: 1, RoomID: 2, Nonce: "nonce"}
+	tests := []struct {
+		name   string
+		invite RoomInvite
+		claims RoomInviteClaims
+		want   bool
+	}{
+		{"own claims", invite, invite.Claims(), true},
+		{"other nonce", invite, RoomInviteClaims{InviteID: 1, RoomID: 2, Nonce: "guess"}, false},
+		{"no nonce", invite, RoomInviteClaims{InviteID: 1, RoomID: 2}, false},
+		{"other room", invite, RoomInviteClaims{InviteID: 1, RoomID: 3, Nonce: "nonce"}, false},
+		{"other invite", invite, RoomInviteClaims{InviteID: 4, RoomID: 2, Nonce: "nonce"}, false},
+		{"invite without nonce", RoomInvite{ID: 1, RoomID: 2}, RoomInviteClaims{InviteID: 1, RoomID: 2}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := tt.claims
+			if got := tt.invite.IsIssuedBy(&claims); got != tt.want {
+				t.Errorf("IsIssuedBy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// ErrInvalidRoomSort ...
+var ErrInvalidRoomSort = errors.New("sort must be one of updated, last_diary, due_at, created")
+
+// RoomSort is an ordering of joined room list
+type RoomSort string
+
+const (
+	// RoomSortUpdated orders rooms by recently updated first (default)
+	RoomSortUpdated RoomSort = "updated"
+	// RoomSortLastDiary orders rooms by recently posted diary first. Rooms without diary come last.
+	RoomSortLastDiary RoomSort = "last_diary"
+	// RoomSortDueAt orders rooms by closest due time first
+	RoomSortDueAt RoomSort = "due_at"
+	// RoomSortCreated orders rooms by recently created first
+	RoomSortCreated RoomSort = "created"
+)
+
+// RoomListOption filters and orders joined room list. Empty Theme means every theme.
+type RoomListOption struct {
+	Theme string
+	Sort  RoomSort
+}
+
+// ParseRoomSort returns RoomSortUpdated for empty string
+func ParseRoomSort(s string) (RoomSort, error) {
+	switch RoomSort(s) {
+	case "":
+		return RoomSortUpdated, nil
+	case RoomSortUpdated, RoomSortLastDiary, RoomSortDueAt, RoomSortCreated:
+		return RoomSort(s), nil
+	}
+	return "", ErrInvalidRoomSort
+}
+
+// Sort orders rooms in place. Ties keep the previous order.
+func (rs Rooms) Sort(by RoomSort) {
+	var key func(room *Room) *time.Time
+	asc := false
+	switch by {
+	case RoomSortLastDiary:
+		key = func(room *Room) *time.Time { return room.LastDiaryAt }
+	case RoomSortDueAt:
+		key = func(room *Room) *time.Time { return room.DueAt }
+		asc = true
+	case RoomSortCreated:
+		key = func(room *Room) *time.Time { return room.CreatedAt }
+	default:
+		key = func(room *Room) *time.Time { return room.UpdatedAt }
+	}
+	sort.SliceStable(rs, func(i, j int) bool {
+		a, b := key(&rs[i]), key(&rs[j])
+		switch {
+		case a == nil || b == nil:
+			// nil comes last
+			return a != nil
+		case asc:
+			return a.Before(*b)
+		default:
+			return a.After(*b)
+		}
+	})
+}
+
+// SplitArchived separates archived rooms from active ones. Both keep the order.
+func (rs Rooms) SplitArchived() (active, archived Rooms) {
+	active, archived = Rooms{}, Rooms{}
+	for _, room := range rs {
+		if room.IsArchived() {
+			archived = append(archived, room)
+			continue
+		}
+		active = append(active, room)
+	}
+	return active, archived
+}
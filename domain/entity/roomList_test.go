@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseRoomSort(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    RoomSort
+		wantErr error
+	}{
+		{"", RoomSortUpdated, nil},
+		{"updated", RoomSortUpdated, nil},
+		{"last_diary", RoomSortLastDiary, nil},
+		{"due_at", RoomSortDueAt, nil},
+		{"created", RoomSortCreated, nil},
+		{"name", "", ErrInvalidRoomSort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParseRoomSort(tt.s)
+			if got != tt.want || err != tt.wantErr {
+				t.Errorf("ParseRoomSort(%q) = %q, %v, want %q, %v", tt.s, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRoomsSort(t *testing.T) {
+	base := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) *time.Time {
+		t := base.Add(time.Hour * time.Duration(hours))
+		return &t
+	}
+	rooms := func() Rooms {
+		return Rooms{
+			{ID: 1, UpdatedAt: at(1), CreatedAt: at(3), DueAt: at(5), LastDiaryAt: nil},
+			{ID: 2, UpdatedAt: at(3), CreatedAt: at(1), DueAt: nil, LastDiaryAt: at(2)},
+			{ID: 3, UpdatedAt: at(2), CreatedAt: at(2), DueAt: at(4), LastDiaryAt: at(1)},
+			{ID: 4, UpdatedAt: at(2), CreatedAt: at(1), DueAt: at(4), LastDiaryAt: nil},
+		}
+	}
+
+	tests := []struct {
+		by   RoomSort
+		want []uint
+	}{
+		{RoomSortUpdated, []uint{2, 3, 4, 1}},
+		{RoomSortLastDiary, []uint{2, 3, 1, 4}}, // rooms without diary come last, in previous order
+		{RoomSortDueAt, []uint{3, 4, 1, 2}},     // closest first
+		{RoomSortCreated, []uint{1, 3, 2, 4}},
+		{"", []uint{2, 3, 4, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.by), func(t *testing.T) {
+			rs := rooms()
+			rs.Sort(tt.by)
+			got := []uint{}
+			for _, room := range rs {
+				got = append(got, room.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Sort(%q) = %v, want %v", tt.by, got, tt.want)
+			}
+		})
+	}
+}
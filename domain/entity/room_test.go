@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRoomSwapOrders(t *testing.T) {
+	tests := []struct {
+		name       string
+		turn       uint
+		a, b       uint
+		wantErr    error
+		wantOrders []uint // of current cycle
+	}{
+		{"swap", 1, 2, 4, nil, []uint{1, 4, 3, 2}},
+		{"swap in reverse", 1, 4, 2, nil, []uint{1, 4, 3, 2}},
+		{"member on duty", 1, 1, 3, ErrSwapOnDuty, []uint{1, 2, 3, 4}},
+		{"member on duty as responder", 1, 3, 1, ErrSwapOnDuty, []uint{1, 2, 3, 4}},
+		{"member who already wrote", 3, 2, 4, ErrSwapPassed, []uint{1, 2, 3, 4}},
+		{"not joined", 1, 2, 5, ErrNotJoinedRoom, []uint{1, 2, 3, 4}},
+		{"oneself", 1, 2, 2, ErrNotJoinedRoom, []uint{1, 2, 3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room := &Room{Orders: []uint{1, 2, 3, 4}, TurnAccountID: tt.turn}
+			if err := room.SwapOrders(tt.a, tt.b); err != tt.wantErr {
+				t.Fatalf("SwapOrders() = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(room.CycleOrders(), tt.wantOrders) {
+				t.Errorf("CycleOrders() = %v, want %v", room.CycleOrders(), tt.wantOrders)
+			}
+			// a swap never changes Orders
+			if !reflect.DeepEqual(room.Orders, []uint{1, 2, 3, 4}) {
+				t.Errorf("Orders = %v, want %v", room.Orders, []uint{1, 2, 3, 4})
+			}
+		})
+	}
+}
+
+func TestRoomNextTurn(t *testing.T) {
+	future := time.Now().Add(time.Hour * 24)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name   string
+		orders []uint
+		turn   uint
+		away   map[uint]time.Time
+		swap   []uint // swapped before rotation
+		want   []uint // turn account ids of following rotations
+	}{
+		{"in orders", []uint{1, 2, 3}, 1, nil, nil, []uint{2, 3, 1, 2}},
+		{"single member", []uint{1}, 1, nil, nil, []uint{1, 1}},
+		{"away member is skipped", []uint{1, 2, 3}, 1, map[uint]time.Time{2: future}, nil, []uint{3, 1, 3}},
+		{"away is over", []uint{1, 2, 3}, 1, map[uint]time.Time{2: past}, nil, []uint{2, 3, 1}},
+		{"every other member is away", []uint{1, 2, 3}, 1, map[uint]time.Time{2: future, 3: future}, nil, []uint{1, 1}},
+		{"everyone is away", []uint{1, 2}, 1, map[uint]time.Time{1: future, 2: future}, nil, []uint{2, 1}},
+		// the swap lasts only for the current cycle. the next cycle follows the orders again.
+		{"swapped members", []uint{1, 2, 3, 4}, 1, nil, []uint{2, 4}, []uint{4, 3, 2, 1, 2, 3, 4}},
+		{"swapped and away", []uint{1, 2, 3, 4}, 1, map[uint]time.Time{4: future}, []uint{2, 4}, []uint{3, 2, 1, 2, 3}},
+		{"swapped in the middle of a cycle", []uint{1, 2, 3, 4}, 2, nil, []uint{3, 4}, []uint{4, 3, 1, 2, 3, 4}},
+		{"swapped and the cycle ends by away", []uint{1, 2, 3, 4}, 1, map[uint]time.Time{2: future}, []uint{2, 4}, []uint{4, 3, 1, 3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room := &Room{Orders: tt.orders, TurnAccountID: tt.turn, Turn: 1, AwayUntil: tt.away}
+			if tt.swap != nil {
+				if err := room.SwapOrders(tt.swap[0], tt.swap[1]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got := []uint{}
+			for range tt.want {
+				got = append(got, room.NextTurn())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NextTurn() = %v, want %v", got, tt.want)
+			}
+			if room.Turn != uint(len(tt.want))+1 {
+				t.Errorf("Turn = %d, want %d", room.Turn, len(tt.want)+1)
+			}
+			if room.SwappedOrders != nil || !reflect.DeepEqual(room.Orders, tt.orders) {
+				t.Errorf("Orders = %v (swapped %v), want %v", room.Orders, room.SwappedOrders, tt.orders)
+			}
+		})
+	}
+}
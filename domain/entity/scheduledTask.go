@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/vo"
+)
+
+var (
+	// ErrScheduledTaskNotFound is returned when there is no scheduled task with given id.
+	ErrScheduledTaskNotFound = errors.New("scheduled task not found")
+	// ErrScheduledTaskAlreadyExists is returned when an other task is already scheduled with the same name.
+	ErrScheduledTaskAlreadyExists = errors.New("scheduled task already exists")
+	// ErrInvalidScheduledAt is returned when a task is rescheduled to the past.
+	ErrInvalidScheduledAt = errors.New("scheduled time must be in the future")
+)
+
+// taskInstanceSeparator joins task id and instance into a scheduler name. (task ids never contain it)
+const taskInstanceSeparator = "--"
+
+// ScheduledTask is a callback task which is going to run at ScheduledAt.
+// ID is unique per (room, account, code). (see service.genUniqueTaskID)
+// Instance distinguishes each registration of the same ID, because google cloud tasks
+// does not accept the name of a deleted or executed task again for a while. (see Name)
+type ScheduledTask struct {
+	ID          string
+	Instance    string
+	Task        vo.TaskVO
+	CallbackURL string
+	ScheduledAt time.Time // zero value means right now
+
+	// only used by local scheduler
+	Attempts    uint       // number of failed attempts
+	LockedUntil *time.Time // lock while the task is running
+}
+
+// NewScheduledTask ...
+func NewScheduledTask(id string, task vo.TaskVO, callbackURL string, scheduledAt time.Time) *ScheduledTask {
+	return &ScheduledTask{
+		ID:          id,
+		Task:        task,
+		CallbackURL: callbackURL,
+		ScheduledAt: scheduledAt,
+	}
+}
+
+// IsDue reports whether the task should run at now.
+func (st *ScheduledTask) IsDue(now time.Time) bool {
+	return !st.ScheduledAt.After(now)
+}
+
+// Name returns the scheduler name of the task instance. (ID if it has no instance)
+func (st *ScheduledTask) Name() string {
+	if st.Instance == "" {
+		return st.ID
+	}
+	return st.ID + taskInstanceSeparator + st.Instance
+}
+
+// NewTaskInstance returns a random instance of a task registration.
+func NewTaskInstance() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ParseTaskName is the inverse of ScheduledTask.Name
+func ParseTaskName(name string) (id, instance string) {
+	i := strings.LastIndex(name, taskInstanceSeparator)
+	if i < 0 {
+		return name, ""
+	}
+	return name[:i], name[i+len(taskInstanceSeparator):]
+}
@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/vo"
+)
+
+func TestScheduledTaskName(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		instance string
+		want     string
+	}{
+		{"turn task", "1-2-ROOM_PERIOD_FIN", "a1b2", "1-2-ROOM_PERIOD_FIN--a1b2"},
+		{"reminder", "1-2-MEMBER_BEFORE_30MIN", "a1b2", "1-2-MEMBER_BEFORE_30MIN--a1b2"},
+		{"room end", "room-end-5", "ff", "room-end-5--ff"},
+		{"without instance", "swap-3", "", "swap-3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &ScheduledTask{ID: tt.id, Instance: tt.instance}
+			if got := task.Name(); got != tt.want {
+				t.Fatalf("Name() = %q, want %q", got, tt.want)
+			}
+			id, instance := ParseTaskName(task.Name())
+			if id != tt.id || instance != tt.instance {
+				t.Fatalf("ParseTaskName(%q) = (%q, %q), want (%q, %q)", task.Name(), id, instance, tt.id, tt.instance)
+			}
+		})
+	}
+}
+
+func TestNewScheduleMessageRegistersNewInstance(t *testing.T) {
+	task := NewScheduledTask("1-2-ROOM_PERIOD_FIN", vo.NewTaskVO(1, "", vo.RoomPeriodFinCode), "", time.Time{})
+
+	first, second := NewScheduleMessage(task), NewScheduleMessage(task)
+	if first.Task.Instance == "" || first.Task.Instance == second.Task.Instance {
+		t.Fatalf("instances must be unique per schedule intent: %q, %q", first.Task.Instance, second.Task.Instance)
+	}
+	if first.Task.ID != task.ID || task.Instance != "" {
+		t.Fatalf("task id must be kept and given task must not be changed: %+v", task)
+	}
+	if cancel := NewCancelMessage(task.ID); cancel.Task.Name() != task.ID {
+		t.Fatalf("cancel message is found by task id, got %q", cancel.Task.Name())
+	}
+}
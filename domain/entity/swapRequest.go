@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrSwapRequestNotFound ...
+	ErrSwapRequestNotFound = errors.New("swap request does not exist")
+	// ErrSwapRequestNotPending is returned when a swap request is already answered or expired.
+	ErrSwapRequestNotPending = errors.New("swap request is not pending")
+	// ErrSwapRequestAlreadyExists is returned when the members have a pending swap request in the room.
+	ErrSwapRequestAlreadyExists = errors.New("pending swap request already exists")
+	// ErrInvalidSwapRequest is returned when a member requests a swap with oneself or a member of another room.
+	ErrInvalidSwapRequest = errors.New("swap request must be made with another member of the room")
+	// ErrSwapOnDuty is returned when a swap involves the member on duty.
+	// Moving the current writer in orders makes the next rotation skip a member.
+	ErrSwapOnDuty = errors.New("member on duty can not be swapped")
+	// ErrSwapPassed is returned when a swap involves a member who already wrote in current cycle.
+	// A swap lasts only until the cycle ends, so the member would write twice in the cycle.
+	ErrSwapPassed = errors.New("member who already wrote in this cycle can not be swapped")
+)
+
+// SwapRequestStatus ...
+type SwapRequestStatus string
+
+const (
+	// SwapPending is a status of swap request which waits for the responder's answer
+	SwapPending SwapRequestStatus = "PENDING"
+	// SwapAccepted is a status of swap request whose members are swapped in orders of current cycle
+	SwapAccepted SwapRequestStatus = "ACCEPTED"
+	// SwapDeclined ...
+	SwapDeclined SwapRequestStatus = "DECLINED"
+	// SwapExpired is a status of swap request which is not answered until ExpiresAt
+	SwapExpired SwapRequestStatus = "EXPIRED"
+)
+
+// SwapRequest is a request of requester to trade places in orders of current cycle with responder.
+type SwapRequest struct {
+	ID          uint
+	RoomID      uint
+	RequesterID uint
+	ResponderID uint
+	Status      SwapRequestStatus
+	ExpiresAt   time.Time
+	RespondedAt *time.Time
+	CreatedAt   *time.Time
+}
+
+// NewSwapRequest ...
+func NewSwapRequest(roomID, requesterID, responderID uint, expiresAt time.Time) *SwapRequest {
+	return &SwapRequest{
+		RoomID:      roomID,
+		RequesterID: requesterID,
+		ResponderID: responderID,
+		Status:      SwapPending,
+		ExpiresAt:   expiresAt,
+	}
+}
+
+// IsPending returns whether the request can be answered at the time
+func (s *SwapRequest) IsPending(at time.Time) bool {
+	return s.Status == SwapPending && at.Before(s.ExpiresAt)
+}
+
+// Respond accepts or declines the request
+func (s *SwapRequest) Respond(accept bool, at time.Time) error {
+	if !s.IsPending(at) {
+		return ErrSwapRequestNotPending
+	}
+	s.Status = SwapDeclined
+	if accept {
+		s.Status = SwapAccepted
+	}
+	s.RespondedAt = &at
+	return nil
+}
+
+// Expire marks unanswered request as expired
+func (s *SwapRequest) Expire() error {
+	if s.Status != SwapPending {
+		return ErrSwapRequestNotPending
+	}
+	s.Status = SwapExpired
+	return nil
+}
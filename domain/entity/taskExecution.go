@@ -0,0 +1,137 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrTaskAlreadyExecuted is returned when the same task (task id + fingerprint) is delivered again after it is finished.
+	ErrTaskAlreadyExecuted = errors.New("task is already executed")
+	// ErrTaskInProgress is returned when the same task is being executed by another delivery.
+	ErrTaskInProgress = errors.New("task is being executed")
+	// ErrStaleTask is returned when the task is scheduled on a turn which is already passed.
+	ErrStaleTask = errors.New("task is scheduled on a passed turn")
+)
+
+// PermanentTaskError is a task error which never succeeds on retry. (ex. room is deleted)
+type PermanentTaskError struct {
+	Err error
+}
+
+func (e *PermanentTaskError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentTaskError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent marks err as a permanent task error.
+func Permanent(err error) error {
+	if err == nil || IsPermanent(err) {
+		return err
+	}
+	return &PermanentTaskError{Err: err}
+}
+
+// IsPermanent reports whether err should not be retried.
+func IsPermanent(err error) bool {
+	var permanent *PermanentTaskError
+	return errors.As(err, &permanent)
+}
+
+// TaskExecutionStatus ...
+type TaskExecutionStatus string
+
+const (
+	// TaskPending is a status of execution which is never run
+	TaskPending TaskExecutionStatus = "PENDING"
+	// TaskRunning is a status of execution which is being run
+	TaskRunning TaskExecutionStatus = "RUNNING"
+	// TaskSucceeded is a status of execution which is finished successfully
+	TaskSucceeded TaskExecutionStatus = "SUCCEEDED"
+	// TaskRetrying is a status of execution which is failed with transient error
+	TaskRetrying TaskExecutionStatus = "RETRYING"
+	// TaskFailed is a status of execution which is failed with permanent error
+	TaskFailed TaskExecutionStatus = "FAILED"
+)
+
+// TaskAttemptOutcome is a result of a single delivery
+type TaskAttemptOutcome string
+
+const (
+	// AttemptSucceeded ...
+	AttemptSucceeded TaskAttemptOutcome = "SUCCEEDED"
+	// AttemptTransientError ...
+	AttemptTransientError TaskAttemptOutcome = "TRANSIENT_ERROR"
+	// AttemptPermanentError ...
+	AttemptPermanentError TaskAttemptOutcome = "PERMANENT_ERROR"
+	// AttemptDuplicate is an attempt which is short-circuited because the task is already executed or in progress
+	AttemptDuplicate TaskAttemptOutcome = "DUPLICATE"
+)
+
+// TaskExecution is a ledger row of a task, which is unique by (TaskID, Fingerprint).
+// Fingerprint identifies the turn which the task is scheduled on, so the same task id can run again on a next turn.
+type TaskExecution struct {
+	ID          uint
+	TaskID      string
+	Fingerprint string
+	RoomID      uint
+	Code        string
+	Status      TaskExecutionStatus
+	Attempts    uint
+	LastError   string
+	LockedUntil *time.Time
+
+	CreatedAt  *time.Time
+	FinishedAt *time.Time
+}
+
+// NewTaskExecution ...
+func NewTaskExecution(taskID, fingerprint string, roomID uint, code string) *TaskExecution {
+	return &TaskExecution{
+		TaskID:      taskID,
+		Fingerprint: fingerprint,
+		RoomID:      roomID,
+		Code:        code,
+		Status:      TaskPending,
+	}
+}
+
+// IsFinished reports whether the task should not be run anymore.
+func (te *TaskExecution) IsFinished() bool {
+	return te.Status == TaskSucceeded || te.Status == TaskFailed
+}
+
+// Finish records the result of current attempt and returns its outcome.
+func (te *TaskExecution) Finish(err error, now time.Time) TaskAttemptOutcome {
+	te.LockedUntil = nil
+	switch {
+	case err == nil:
+		te.Status = TaskSucceeded
+		te.LastError = ""
+		te.FinishedAt = &now
+		return AttemptSucceeded
+	case IsPermanent(err):
+		te.Status = TaskFailed
+		te.LastError = err.Error()
+		te.FinishedAt = &now
+		return AttemptPermanentError
+	default:
+		te.Status = TaskRetrying
+		te.LastError = err.Error()
+		return AttemptTransientError
+	}
+}
+
+// TaskAttempt is a record of every delivery of a task.
+type TaskAttempt struct {
+	ID          uint
+	ExecutionID uint
+	Number      uint
+	Outcome     TaskAttemptOutcome
+	Error       string
+	StartedAt   time.Time
+	FinishedAt  time.Time
+}
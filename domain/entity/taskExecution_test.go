@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTaskExecutionFinish(t *testing.T) {
+	now := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	transient := errors.New("unavailable")
+	tests := []struct {
+		name         string
+		err          error
+		wantOutcome  TaskAttemptOutcome
+		wantStatus   TaskExecutionStatus
+		wantFinished bool
+	}{
+		{"success", nil, AttemptSucceeded, TaskSucceeded, true},
+		{"transient error", transient, AttemptTransientError, TaskRetrying, false},
+		{"permanent error", Permanent(ErrRoomNotFound), AttemptPermanentError, TaskFailed, true},
+		{"wrapped permanent error", fmt.Errorf("rotate: %w", Permanent(ErrStaleTask)), AttemptPermanentError, TaskFailed, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lockedUntil := now.Add(time.Minute)
+			execution := NewTaskExecution("1-2-ROOM_PERIOD_FIN", "1", 1, "ROOM_PERIOD_FIN")
+			execution.Status, execution.Attempts, execution.LockedUntil = TaskRunning, 1, &lockedUntil
+
+			if got := execution.Finish(tt.err, now); got != tt.wantOutcome {
+				t.Errorf("Finish() = %s, want %s", got, tt.wantOutcome)
+			}
+			if execution.Status != tt.wantStatus || execution.IsFinished() != tt.wantFinished {
+				t.Errorf("status = %s (finished %t), want %s (finished %t)",
+					execution.Status, execution.IsFinished(), tt.wantStatus, tt.wantFinished)
+			}
+			if execution.LockedUntil != nil {
+				t.Errorf("lock is not released: %s", execution.LockedUntil)
+			}
+			if (execution.FinishedAt != nil) != tt.wantFinished {
+				t.Errorf("FinishedAt = %v, want finished %t", execution.FinishedAt, tt.wantFinished)
+			}
+			if tt.err != nil && execution.LastError != tt.err.Error() {
+				t.Errorf("LastError = %q, want %q", execution.LastError, tt.err.Error())
+			}
+		})
+	}
+}
+
+func TestPermanent(t *testing.T) {
+	permanent := Permanent(ErrRoomNotFound)
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", Permanent(nil), false},
+		{"transient", errors.New("unavailable"), false},
+		{"permanent", permanent, true},
+		{"permanent twice", Permanent(permanent), true},
+		{"wrapped", fmt.Errorf("wrap: %w", permanent), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPermanent(tt.err); got != tt.want {
+				t.Errorf("IsPermanent() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+	if Permanent(permanent) != permanent {
+		t.Errorf("Permanent() wraps a permanent error again")
+	}
+	if !errors.Is(permanent, ErrRoomNotFound) {
+		t.Errorf("permanent error does not unwrap to its cause")
+	}
+}
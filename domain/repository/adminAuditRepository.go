@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+)
+
+// AdminAuditRepository ...
+type AdminAuditRepository interface {
+	Create(audit *entity.AdminAudit) (*entity.AdminAudit, error)
+	GetAllByRoomID(roomID, limit, offset uint) ([]entity.AdminAudit, error)
+}
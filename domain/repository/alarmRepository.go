@@ -7,5 +7,6 @@ import (
 // AlarmRepository ...
 type AlarmRepository interface {
 	Create(alarm *entity.Alarm) (*entity.Alarm, error)
+	GetByID(id uint) (*entity.Alarm, error)
 	GetAll(accountID uint) (*entity.Alarms, error)
 }
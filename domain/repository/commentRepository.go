@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+)
+
+// CommentRepository ...
+type CommentRepository interface {
+	Create(comment *entity.Comment) (*entity.Comment, error)
+	GetByID(id uint) (*entity.Comment, error)
+	GetAll(diaryID, limit, offset uint) (*entity.Comments, error)
+	GetAllReplies(parentIDs []uint) (*entity.Comments, error)
+	Delete(comment *entity.Comment) error
+}
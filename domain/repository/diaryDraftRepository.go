@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+)
+
+// DiaryDraftRepository ...
+type DiaryDraftRepository interface {
+	GetByUnq(roomID, memberID, turn uint) (*entity.DiaryDraft, error)
+	Save(draft *entity.DiaryDraft) (*entity.DiaryDraft, error)
+	Promote(draft *entity.DiaryDraft, diary *entity.Diary) (*entity.Diary, error)
+	Delete(draft *entity.DiaryDraft) error
+	DeleteAllBefore(roomID, turn uint) error
+}
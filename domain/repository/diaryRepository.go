@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/vo"
+)
+
+// DiaryRepository ...
+type DiaryRepository interface {
+	Create(diary *entity.Diary) (*entity.Diary, error)
+	GetByID(id uint) (*entity.Diary, error)
+	GetLatest(roomID uint) (*entity.Diary, error)
+	// LastCreatedAts returns map of (roomID -> created time of the latest diary). Rooms without diary are omitted.
+	LastCreatedAts(roomIDs []uint) (map[uint]time.Time, error)
+	GetAll(roomID, limit, offset, cursor uint) (*entity.Diaries, error)
+	Search(cond vo.DiarySearchVO) (*entity.Diaries, error)
+	Update(diary *entity.Diary) (*entity.Diary, error)
+	Delete(diary *entity.Diary) error
+}
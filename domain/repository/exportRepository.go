@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+)
+
+// ExportRepository ...
+type ExportRepository interface {
+	Create(export *entity.Export) (*entity.Export, error)
+	GetByID(id uint) (*entity.Export, error)
+	GetUnfinished(roomID, memberID uint) (*entity.Export, error)
+	Update(export *entity.Export) (*entity.Export, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+)
+
+// JoinAttemptRepository ...
+type JoinAttemptRepository interface {
+	// Update applies fn to the attempt of key (a new attempt if not exists) exclusively, and saves it.
+	// Nothing is saved if fn returns an error.
+	Update(key string, fn func(attempt *entity.JoinAttempt) error) (*entity.JoinAttempt, error)
+	Delete(key string) error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+)
+
+// OutboxRepository ...
+type OutboxRepository interface {
+	Create(messages ...*entity.OutboxMessage) error
+	Claim(now time.Time, lease time.Duration, limit int) ([]entity.OutboxMessage, error)
+	Update(message *entity.OutboxMessage) error
+}
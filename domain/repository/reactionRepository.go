@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+)
+
+// ReactionRepository ...
+type ReactionRepository interface {
+	GetByUnq(diaryID, memberID uint) (*entity.Reaction, error)
+	Create(reaction *entity.Reaction) (*entity.Reaction, error)
+	Update(reaction *entity.Reaction) (*entity.Reaction, error)
+	Delete(reaction *entity.Reaction) error
+	CountAll(diaryIDs []uint) (map[uint]entity.ReactionCounts, error)
+}
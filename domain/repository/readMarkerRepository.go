@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+)
+
+// ReadMarkerRepository ...
+type ReadMarkerRepository interface {
+	Save(marker *entity.ReadMarker) error
+	UnreadCounts(memberID uint, roomIDs []uint) (map[uint]uint, error)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+)
+
+// RoomInviteRepository ...
+type RoomInviteRepository interface {
+	Create(invite *entity.RoomInvite) (*entity.RoomInvite, error)
+	GetByID(id uint) (*entity.RoomInvite, error)
+	GetAllByRoomID(roomID uint) ([]entity.RoomInvite, error)
+	Revoke(invite *entity.RoomInvite) error
+	// Use increases use count of a valid invite. entity.ErrRoomInviteExhausted if it cannot be used anymore.
+	Use(id uint) error
+}
@@ -8,7 +8,11 @@ import (
 type RoomRepository interface {
 	Create(room *entity.Room) (*entity.Room, error)
 	GetByID(id uint) (*entity.Room, error)
+	// GetByIDForUpdate locks the room row until the transaction ends.
+	GetByIDForUpdate(id uint) (*entity.Room, error)
 	GetAll(accountID uint, roomIDs []uint) (*entity.Rooms, error)
+	GetPage(afterID uint, limit int) (*entity.Rooms, error)
 	Update(room *entity.Room) (*entity.Room, error)
+	UpdateAwayUntil(room *entity.Room) error
 	Delete(room *entity.Room) error
 }
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+)
+
+// ScheduledTaskRepository ...
+type ScheduledTaskRepository interface {
+	Save(task *entity.ScheduledTask) (*entity.ScheduledTask, error)
+	GetByID(id string) (*entity.ScheduledTask, error)
+	GetAll() ([]entity.ScheduledTask, error)
+	Delete(id string) error
+	Claim(now time.Time, lease time.Duration, limit int) ([]entity.ScheduledTask, error)
+	Release(task *entity.ScheduledTask) error
+	Retry(task *entity.ScheduledTask, at time.Time) error
+}
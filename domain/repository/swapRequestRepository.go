@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+)
+
+// SwapRequestRepository ...
+type SwapRequestRepository interface {
+	Create(request *entity.SwapRequest) (*entity.SwapRequest, error)
+	GetByID(id uint) (*entity.SwapRequest, error)
+	// GetPending returns a pending request between two members in either direction
+	GetPending(roomID, a, b uint) (*entity.SwapRequest, error)
+	// GetAllPending returns pending requests of the room which the member requested or is asked
+	GetAllPending(roomID, memberID uint) ([]entity.SwapRequest, error)
+	// Update saves the answer of a pending request. entity.ErrSwapRequestNotPending if it is already answered.
+	Update(request *entity.SwapRequest) (*entity.SwapRequest, error)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+)
+
+// TaskExecutionRepository ...
+type TaskExecutionRepository interface {
+	Acquire(execution *entity.TaskExecution, now time.Time, lease time.Duration) (*entity.TaskExecution, error)
+	Finish(execution *entity.TaskExecution, attempt *entity.TaskAttempt) error
+	CreateAttempt(attempt *entity.TaskAttempt) error
+	Exists(taskID, fingerprint string) (bool, error)
+}
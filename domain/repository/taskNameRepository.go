@@ -0,0 +1,10 @@
+package repository
+
+// TaskNameRepository keeps the scheduler name of the latest instance of each task id. (used by google cloud tasks scheduler)
+type TaskNameRepository interface {
+	Save(taskID, name string) error
+	// Get returns entity.ErrScheduledTaskNotFound if the task id has never been registered with an instance.
+	Get(taskID string) (string, error)
+	// Delete removes the task id only if its latest name is still the given name.
+	Delete(taskID, name string) error
+}
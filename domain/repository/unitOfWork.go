@@ -0,0 +1,18 @@
+package repository
+
+// Transaction gives repositories which share a single db transaction.
+type Transaction interface {
+	Rooms() RoomRepository
+	RoomMembers() RoomMemberRepository
+	DiaryDrafts() DiaryDraftRepository
+	Outbox() OutboxRepository
+	AdminAudits() AdminAuditRepository
+	SwapRequests() SwapRequestRepository
+	RoomInvites() RoomInviteRepository
+}
+
+// UnitOfWork runs fn in a transaction.
+// If fn returns an error (or panics), every change made through tx is rolled back.
+type UnitOfWork interface {
+	Do(fn func(tx Transaction) error) error
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"github.com/ExchangeDiary/exchange-diary/domain/vo"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
+)
+
+// AdminTaskService inspects and manages scheduled tasks of a room. Every action is audited.
+type AdminTaskService interface {
+	ListTasks(roomID uint) ([]vo.PendingTask, error)
+	RunTask(adminID, roomID, accountID uint, code vo.TaskCode) error
+	RescheduleTask(adminID, roomID, accountID uint, code vo.TaskCode, scheduledAt time.Time) error
+	CancelTask(adminID, roomID, accountID uint, code vo.TaskCode) error
+	ListAudits(roomID, limit, offset uint) ([]entity.AdminAudit, error)
+}
+
+type adminTaskService struct {
+	unitOfWork           repository.UnitOfWork
+	adminAuditRepository repository.AdminAuditRepository
+	taskService          TaskService
+	roomService          RoomService
+	memberService        MemberService
+}
+
+// NewAdminTaskService ...
+func NewAdminTaskService(uow repository.UnitOfWork, aar repository.AdminAuditRepository, ts TaskService, rs RoomService, ms MemberService) AdminTaskService {
+	return &adminTaskService{
+		unitOfWork:           uow,
+		adminAuditRepository: aar,
+		taskService:          ts,
+		roomService:          rs,
+		memberService:        ms,
+	}
+}
+
+// ListTasks returns pending tasks of every room member in schedule time order.
+func (ats *adminTaskService) ListTasks(roomID uint) ([]vo.PendingTask, error) {
+	room, err := ats.roomService.Get(roomID, entity.Ignore)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := append([]vo.TaskCode{}, vo.TaskCodes...)
+	for _, offset := range room.ReminderOffsets {
+		codes = append(codes, vo.MemberBeforeCode(offset))
+	}
+
+	tasks := []vo.PendingTask{}
+	for _, accountID := range room.Orders {
+		for _, code := range codes {
+			task, err := ats.taskService.GetTask(code, roomID, accountID)
+			if err == entity.ErrScheduledTaskNotFound {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			tasks = append(tasks, vo.PendingTask{
+				TaskID:      task.ID,
+				AccountID:   accountID,
+				Email:       task.Task.Email,
+				Code:        code,
+				Turn:        task.Task.Turn,
+				DueAt:       task.Task.DueAt,
+				ScheduledAt: task.ScheduledAt,
+			})
+		}
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].ScheduledAt.Before(tasks[j].ScheduledAt)
+	})
+	return tasks, nil
+}
+
+// RunTask runs the task right away. The scheduled one is ignored as a duplicate when it is delivered.
+func (ats *adminTaskService) RunTask(adminID, roomID, accountID uint, code vo.TaskCode) error {
+	task, err := ats.taskService.GetTask(code, roomID, accountID)
+	if err != nil {
+		return err
+	}
+
+	audit := entity.NewAdminAudit(adminID, entity.AdminRunTask, roomID, task.ID,
+		fmt.Sprintf("turn: %d, scheduledAt: %s", task.Task.Turn, formatScheduledAt(task.ScheduledAt)))
+	err = ats.taskService.Handle(task)
+	audit.Fail(err)
+	if _, aerr := ats.adminAuditRepository.Create(audit); aerr != nil {
+		logger.Error(aerr.Error())
+	}
+	return err
+}
+
+// RescheduleTask moves the task to scheduledAt.
+// ROOM_PERIOD_FIN task of current turn decides room's due time, so the room and reminders of the turn follow it.
+func (ats *adminTaskService) RescheduleTask(adminID, roomID, accountID uint, code vo.TaskCode, scheduledAt time.Time) error {
+	if !scheduledAt.After(time.Now()) {
+		return entity.ErrInvalidScheduledAt
+	}
+	task, err := ats.taskService.GetTask(code, roomID, accountID)
+	if err != nil {
+		return err
+	}
+	room, messages, err := ats.rescheduleMessages(task, accountID, scheduledAt)
+	if err != nil {
+		return err
+	}
+
+	audit := entity.NewAdminAudit(adminID, entity.AdminRescheduleTask, roomID, task.ID,
+		fmt.Sprintf("scheduledAt: %s -> %s", formatScheduledAt(task.ScheduledAt), formatScheduledAt(scheduledAt)))
+	return ats.unitOfWork.Do(func(tx repository.Transaction) error {
+		if room != nil {
+			if _, err := tx.Rooms().Update(room); err != nil {
+				return err
+			}
+		}
+		if err := tx.Outbox().Create(messages...); err != nil {
+			return err
+		}
+		_, err := tx.AdminAudits().Create(audit)
+		return err
+	})
+}
+
+// rescheduleMessages returns the room to be updated (nil if not) and outbox messages of rescheduling.
+// A new instance replaces the scheduled one of the same task id, so tasks are not cancelled first.
+func (ats *adminTaskService) rescheduleMessages(task *entity.ScheduledTask, accountID uint, scheduledAt time.Time) (*entity.Room, []*entity.OutboxMessage, error) {
+	if task.Task.Code == vo.RoomPeriodFinCode {
+		room, err := ats.roomService.Get(task.Task.RoomID, entity.Ignore)
+		if err != nil && err != entity.ErrRoomNotFound {
+			return nil, nil, err
+		}
+		if err == nil && room.IsTurn(accountID) && (task.Task.Turn == 0 || task.Task.Turn == room.Turn) {
+			turnMember, err := ats.memberService.Get(room.TurnAccountID)
+			if err == entity.ErrMemberNotFound {
+				turnMember = nil
+			} else if err != nil {
+				return nil, nil, err
+			}
+
+			room.DueAt = &scheduledAt
+			now := time.Now()
+			messages := []*entity.OutboxMessage{}
+			for _, turnTask := range turnTasks(task.CallbackURL, room, turnMember) {
+				if turnTask.Task.Code == vo.MemberOnDutyCode {
+					continue
+				}
+				if isPassed(turnTask, now) {
+					// a reminder before the new due time is already passed, so the old one should not be sent either.
+					messages = append(messages, entity.NewCancelMessage(turnTask.ID))
+					continue
+				}
+				messages = append(messages, entity.NewScheduleMessage(turnTask))
+			}
+			return room, messages, nil
+		}
+	}
+
+	rescheduled := *task
+	rescheduled.ScheduledAt = scheduledAt
+	return nil, []*entity.OutboxMessage{entity.NewScheduleMessage(&rescheduled)}, nil
+}
+
+// CancelTask deletes the task. It can be used for tasks of a deleted room.
+func (ats *adminTaskService) CancelTask(adminID, roomID, accountID uint, code vo.TaskCode) error {
+	task, err := ats.taskService.GetTask(code, roomID, accountID)
+	if err != nil {
+		return err
+	}
+
+	audit := entity.NewAdminAudit(adminID, entity.AdminCancelTask, roomID, task.ID,
+		fmt.Sprintf("turn: %d, scheduledAt: %s", task.Task.Turn, formatScheduledAt(task.ScheduledAt)))
+	return ats.unitOfWork.Do(func(tx repository.Transaction) error {
+		if err := tx.Outbox().Create(entity.NewCancelMessage(task.ID)); err != nil {
+			return err
+		}
+		_, err := tx.AdminAudits().Create(audit)
+		return err
+	})
+}
+
+func (ats *adminTaskService) ListAudits(roomID, limit, offset uint) ([]entity.AdminAudit, error) {
+	return ats.adminAuditRepository.GetAllByRoomID(roomID, limit, offset)
+}
+
+func formatScheduledAt(scheduledAt time.Time) string {
+	if scheduledAt.IsZero() {
+		return "now"
+	}
+	return scheduledAt.Format(time.RFC3339)
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/vo"
+)
+
+func TestAdminTaskServiceRescheduleTask(t *testing.T) {
+	const roomID, accountID, adminID = 1, 2, 3
+	reminder := vo.MemberBeforeCode(time.Hour)
+	dueAt := time.Now().Add(time.Hour * 24).Truncate(time.Second)
+	later := dueAt.Add(time.Hour * 6)
+	sooner := time.Now().Add(time.Minute * 30).Truncate(time.Second)
+
+	tests := []struct {
+		name         string
+		code         vo.TaskCode
+		scheduledAt  []time.Time // rescheduled in order
+		wantErr      error
+		wantTasks    map[vo.TaskCode]time.Time
+		wantCanceled []vo.TaskCode
+		wantDueAt    time.Time
+	}{
+		{"reminder", reminder, []time.Time{later}, nil,
+			map[vo.TaskCode]time.Time{reminder: later, vo.RoomPeriodFinCode: dueAt}, nil, dueAt},
+		{"room period fin moves due time and reminders", vo.RoomPeriodFinCode, []time.Time{later}, nil,
+			map[vo.TaskCode]time.Time{reminder: later.Add(-time.Hour), vo.RoomPeriodFinCode: later}, nil, later},
+		{"room period fin cancels passed reminders", vo.RoomPeriodFinCode, []time.Time{sooner}, nil,
+			map[vo.TaskCode]time.Time{vo.RoomPeriodFinCode: sooner}, []vo.TaskCode{reminder}, sooner},
+		{"rescheduled twice", vo.RoomPeriodFinCode, []time.Time{later, later.Add(time.Hour)}, nil,
+			map[vo.TaskCode]time.Time{reminder: later, vo.RoomPeriodFinCode: later.Add(time.Hour)}, nil, later.Add(time.Hour)},
+		{"past", reminder, []time.Time{time.Now().Add(-time.Minute)}, entity.ErrInvalidScheduledAt,
+			map[vo.TaskCode]time.Time{reminder: dueAt.Add(-time.Hour), vo.RoomPeriodFinCode: dueAt}, nil, dueAt},
+		{"no task", vo.MemberBeforeCode(time.Hour * 3), []time.Time{later}, entity.ErrScheduledTaskNotFound,
+			map[vo.TaskCode]time.Time{reminder: dueAt.Add(-time.Hour), vo.RoomPeriodFinCode: dueAt}, nil, dueAt},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room := &entity.Room{
+				ID:              roomID,
+				Orders:          []uint{accountID},
+				TurnAccountID:   accountID,
+				Turn:            1,
+				DueAt:           &dueAt,
+				ReminderOffsets: []time.Duration{time.Hour},
+			}
+			member := entity.Member{ID: accountID, Email: "a@voda.com"}
+			uow := newFakeUnitOfWork()
+			uow.tx.rooms.rooms[roomID] = *room
+			scheduler := newFakeScheduler()
+			for _, task := range turnTasks("https://voda.com", room, &member) {
+				if _, err := scheduler.Schedule(task); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ats := NewAdminTaskService(
+				uow,
+				uow.tx.audits,
+				NewTaskService(uow, nil, scheduler, nil, nil, nil, nil, nil),
+				&fakeRoomService{rooms: uow.tx.rooms},
+				&fakeMemberService{members: map[uint]entity.Member{accountID: member}},
+			)
+			for _, scheduledAt := range tt.scheduledAt {
+				if err := ats.RescheduleTask(adminID, roomID, accountID, tt.code, scheduledAt); err != tt.wantErr {
+					t.Fatalf("RescheduleTask() = %v, want %v", err, tt.wantErr)
+				}
+				relayOutbox(t, uow, scheduler)
+			}
+
+			for code, want := range tt.wantTasks {
+				task, err := scheduler.Get(genUniqueTaskID(roomID, accountID, code))
+				if err != nil {
+					t.Fatalf("%s: %v", code, err)
+				}
+				if !task.ScheduledAt.Equal(want) {
+					t.Errorf("%s scheduled at %s, want %s", code, task.ScheduledAt, want)
+				}
+			}
+			for _, code := range tt.wantCanceled {
+				if _, err := scheduler.Get(genUniqueTaskID(roomID, accountID, code)); err != entity.ErrScheduledTaskNotFound {
+					t.Errorf("%s: %v, want %v", code, err, entity.ErrScheduledTaskNotFound)
+				}
+			}
+			if got := uow.tx.rooms.rooms[roomID].DueAt; !got.Equal(tt.wantDueAt) {
+				t.Errorf("room due at %s, want %s", got, tt.wantDueAt)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package service
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/ExchangeDiary/exchange-diary/domain/entity"
 	"github.com/ExchangeDiary/exchange-diary/domain/repository"
 	"github.com/ExchangeDiary/exchange-diary/domain/vo"
@@ -8,26 +11,34 @@ import (
 )
 
 // AlarmService ...
+// Alarms are always stored right away, but pushes to members in quiet hours are deferred
+// to the end of quiet hours by scheduler, unless the alarm is urgent.
 type AlarmService interface {
 	Create(memberID, roomID uint, code vo.TaskCode, roomName, diaryTitle, authorNickname string) (*entity.Alarm, error)
 	GetAll(memberID uint) (*entity.Alarms, error)
 	PushByID(memberID uint, al *entity.Alarm) error
 	PushByEmail(email string, al *entity.Alarm) error
 	BroadCast(memberIDs []uint, al *entity.Alarm) error
+	PushDeferred(alarmID uint, email string) error
 }
 
 type alarmService struct {
+	unitOfWork             repository.UnitOfWork
 	memberService          MemberService
 	memberDeviceRepository repository.MemberDeviceRepository
 	alarmRepository        repository.AlarmRepository
+	callbackURL            string
 }
 
 // NewAlarmService ...
-func NewAlarmService(ms MemberService, mdr repository.MemberDeviceRepository, ar repository.AlarmRepository) AlarmService {
+// callbackURL is a task callback url of deferred pushes.
+func NewAlarmService(uow repository.UnitOfWork, ms MemberService, mdr repository.MemberDeviceRepository, ar repository.AlarmRepository, callbackURL string) AlarmService {
 	return &alarmService{
+		unitOfWork:             uow,
 		memberService:          ms,
 		memberDeviceRepository: mdr,
 		alarmRepository:        ar,
+		callbackURL:            callbackURL,
 	}
 }
 
@@ -44,33 +55,70 @@ func (as *alarmService) GetAll(memberID uint) (*entity.Alarms, error) {
 }
 
 func (as *alarmService) PushByID(memberID uint, al *entity.Alarm) (err error) {
-	var deviceTokens []string
-	if deviceTokens, err = as.memberDeviceRepository.GetAllTokens(memberID); err != nil {
+	var member *entity.Member
+	if member, err = as.memberService.Get(memberID); err != nil {
 		return
 	}
+	return as.pushOrDefer(entity.Members{*member}, al)
+}
 
-	var failedTokens []string
-	if failedTokens, err = firebase.GetClient().Push(deviceTokens, al); err != nil {
+func (as *alarmService) PushByEmail(email string, al *entity.Alarm) (err error) {
+	var member *entity.Member
+	if member, err = as.memberService.GetByEmail(email); err != nil {
 		return
 	}
-	if len(failedTokens) > 0 {
-		if err = as.memberDeviceRepository.DeleteBatch(failedTokens); err != nil {
-			return
-		}
-	}
+	return as.pushOrDefer(entity.Members{*member}, al)
+}
 
-	return
+func (as *alarmService) BroadCast(memberIDs []uint, al *entity.Alarm) (err error) {
+	var members *entity.Members
+	if members, err = as.memberService.GetAllByIDs(memberIDs); err != nil {
+		return
+	}
+	return as.pushOrDefer(*members, al)
 }
 
-func (as *alarmService) PushByEmail(email string, al *entity.Alarm) (err error) {
+// PushDeferred pushes the alarm which was deferred by quiet hours. It is called by DEFERRED_PUSH task.
+func (as *alarmService) PushDeferred(alarmID uint, email string) (err error) {
+	var alarm *entity.Alarm
+	if alarm, err = as.alarmRepository.GetByID(alarmID); err != nil {
+		return
+	}
 	var member *entity.Member
 	if member, err = as.memberService.GetByEmail(email); err != nil {
 		return
 	}
-	return as.PushByID(member.ID, al)
+	return as.push([]uint{member.ID}, alarm)
 }
 
-func (as *alarmService) BroadCast(memberIDs []uint, al *entity.Alarm) (err error) {
+// pushOrDefer pushes the alarm to members right away, except members in quiet hours.
+func (as *alarmService) pushOrDefer(members entity.Members, al *entity.Alarm) (err error) {
+	now := time.Now()
+	receiverIDs := []uint{}
+	deferred := []*entity.OutboxMessage{}
+	for i := range members {
+		member := &members[i]
+		if until, quiet := member.QuietUntil(now); quiet && !al.IsUrgent() {
+			deferred = append(deferred, entity.NewScheduleMessage(as.deferredPushTask(al, member, until)))
+			continue
+		}
+		receiverIDs = append(receiverIDs, member.ID)
+	}
+
+	if len(deferred) > 0 {
+		if err = as.unitOfWork.Do(func(tx repository.Transaction) error {
+			return tx.Outbox().Create(deferred...)
+		}); err != nil {
+			return
+		}
+	}
+	if len(receiverIDs) == 0 {
+		return
+	}
+	return as.push(receiverIDs, al)
+}
+
+func (as *alarmService) push(memberIDs []uint, al *entity.Alarm) (err error) {
 	var deviceTokens []string
 	if deviceTokens, err = as.memberDeviceRepository.GetAllMemberTokens(memberIDs); err != nil {
 		return
@@ -87,3 +135,11 @@ func (as *alarmService) BroadCast(memberIDs []uint, al *entity.Alarm) (err error
 	}
 	return
 }
+
+// deferredPushTask returns DEFERRED_PUSH task which pushes the alarm to member at the end of quiet hours.
+// Task id is unique per (alarm, member), so the same alarm is not deferred twice.
+func (as *alarmService) deferredPushTask(al *entity.Alarm, member *entity.Member, at time.Time) *entity.ScheduledTask {
+	task := vo.NewTaskVO(al.RoomID, member.Email, vo.DeferredPushCode)
+	task.AlarmID = al.ID
+	return entity.NewScheduledTask(fmt.Sprintf("push-%d-%d", al.ID, member.ID), task, as.callbackURL, at)
+}
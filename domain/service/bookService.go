@@ -0,0 +1,119 @@
+package service
+
+import (
+	"io/ioutil"
+
+	"github.com/ExchangeDiary/exchange-diary/domain"
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/book"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
+)
+
+// BookService ...
+type BookService interface {
+	Render(roomID, memberID uint, format book.Format) ([]byte, error)
+}
+
+type bookService struct {
+	roomService  RoomService
+	diaryService DiaryService
+	fileService  FileService
+}
+
+// NewBookService ...
+func NewBookService(rs RoomService, ds DiaryService, fs FileService) BookService {
+	return &bookService{
+		roomService:  rs,
+		diaryService: ds,
+		fileService:  fs,
+	}
+}
+
+// Render renders every diary of the room into a printable book.
+func (bs *bookService) Render(roomID, memberID uint, format book.Format) ([]byte, error) {
+	room, err := bs.roomService.Get(roomID, entity.DiaryOrder)
+	if err != nil {
+		return nil, err
+	}
+	if !room.IsAlreadyJoined(memberID) {
+		return nil, entity.ErrNotJoinedRoom
+	}
+	diaries, err := bs.diaryService.GetAllInTurnOrder(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &book.Book{
+		Title:    room.Name,
+		Theme:    room.Theme,
+		Contents: bs.contents(room, diaries),
+		Pages:    []book.Page{},
+	}
+	for i := range *diaries {
+		b.Pages = append(b.Pages, bs.page(&(*diaries)[i]))
+	}
+	return book.Render(b, format)
+}
+
+// contents groups diaries by author in room.Orders.
+// 방을 떠난 작성자의 일기는 마지막에 추가된다.
+func (bs *bookService) contents(room *entity.Room, diaries *entity.Diaries) []book.Chapter {
+	authorIDs := append([]uint{}, room.Orders...)
+	for _, diary := range *diaries {
+		if !domain.Contains(authorIDs, diary.AuthorID) {
+			authorIDs = append(authorIDs, diary.AuthorID)
+		}
+	}
+
+	chapters := []book.Chapter{}
+	for _, authorID := range authorIDs {
+		chapter := book.Chapter{Turns: []uint{}}
+		for _, diary := range *diaries {
+			if diary.AuthorID != authorID {
+				continue
+			}
+			if diary.Author != nil {
+				chapter.Author = diary.Author.Name
+			}
+			chapter.Turns = append(chapter.Turns, diary.Turn)
+		}
+		if len(chapter.Turns) > 0 {
+			chapters = append(chapters, chapter)
+		}
+	}
+	return chapters
+}
+
+func (bs *bookService) page(diary *entity.Diary) book.Page {
+	page := book.Page{
+		Turn:      diary.Turn,
+		Title:     diary.Title,
+		Body:      diary.Body,
+		CreatedAt: diary.CreatedAt,
+	}
+	if diary.Author != nil {
+		page.Author = diary.Author.Name
+	}
+	if diary.HasAudio() {
+		page.AudioTitle = diary.AudioTitle
+		page.AudioURL = diary.AudioURL
+	}
+	if diary.HasPhoto() {
+		// 사진을 불러오지 못하더라도 책은 만들어진다.
+		photo, err := bs.readFile(diary.RoomID, diary.PhotoUUID, PhotoType)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		page.Photo = photo
+	}
+	return page
+}
+
+func (bs *bookService) readFile(roomID uint, fileUUID string, ftype FileType) ([]byte, error) {
+	r, err := bs.fileService.OpenFile(roomID, fileUUID, ftype)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"github.com/ExchangeDiary/exchange-diary/domain"
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"github.com/ExchangeDiary/exchange-diary/domain/vo"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
+)
+
+// CommentService ...
+type CommentService interface {
+	Create(diary *entity.Diary, comment *entity.Comment) (*entity.Comment, error)
+	Get(id uint) (*entity.Comment, error)
+	GetAll(diaryID, limit, offset uint) (*entity.Comments, error)
+	Delete(comment *entity.Comment, memberID uint) error
+}
+
+type commentService struct {
+	roomService       RoomService
+	alarmService      AlarmService
+	commentRepository repository.CommentRepository
+	memberRepository  repository.MemberRepository
+}
+
+// NewCommentService ...
+func NewCommentService(cr repository.CommentRepository, mr repository.MemberRepository, rs RoomService, as AlarmService) CommentService {
+	return &commentService{
+		commentRepository: cr,
+		memberRepository:  mr,
+		roomService:       rs,
+		alarmService:      as,
+	}
+}
+
+// Create posts a comment on the diary. If comment.ParentID is given, it is posted as a reply.
+func (cs *commentService) Create(diary *entity.Diary, comment *entity.Comment) (*entity.Comment, error) {
+	var parent *entity.Comment
+	if comment.ParentID != nil {
+		var err error
+		if parent, err = cs.commentRepository.GetByID(*comment.ParentID); err != nil {
+			return nil, err
+		}
+		if err := comment.ReplyTo(parent); err != nil {
+			return nil, err
+		}
+	}
+
+	createdComment, err := cs.commentRepository.Create(comment)
+	if err != nil {
+		return nil, err
+	}
+	author, err := cs.memberRepository.Get(createdComment.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	createdComment.Author = author
+
+	// 알림 실패가 댓글 작성을 실패시키지는 않는다.
+	if err := cs.notify(diary, parent, createdComment); err != nil {
+		logger.Error(err.Error())
+	}
+	return createdComment, nil
+}
+
+// notify sends MEMBER_COMMENTED_DIARY alarm to the diary author and the parent comment author.
+// Each receiver gets an own alarm, and it is pushed only when the receiver turns on the alarm.
+// The alarm is created regardless of the flag, so it is listed in the alarm list.
+func (cs *commentService) notify(diary *entity.Diary, parent *entity.Comment, comment *entity.Comment) error {
+	receiverIDs := []uint{}
+	candidateIDs := []uint{diary.AuthorID}
+	if parent != nil {
+		candidateIDs = append(candidateIDs, parent.AuthorID)
+	}
+	for _, id := range candidateIDs {
+		if id != comment.AuthorID && !domain.Contains(receiverIDs, id) {
+			receiverIDs = append(receiverIDs, id)
+		}
+	}
+	if len(receiverIDs) == 0 {
+		return nil
+	}
+	receivers, err := cs.memberRepository.GetAllByIDs(receiverIDs)
+	if err != nil {
+		return err
+	}
+
+	room, err := cs.roomService.Get(diary.RoomID, entity.Ignore)
+	if err != nil {
+		return err
+	}
+	for _, receiver := range *receivers {
+		alarm, err := cs.alarmService.Create(receiver.ID, room.ID, vo.MemberCommentedDiaryCode, room.Name, diary.Title, comment.Author.Name)
+		if err != nil {
+			return err
+		}
+		if !receiver.AlarmFlag {
+			continue
+		}
+		if err := cs.alarmService.PushByID(receiver.ID, alarm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get returns a comment which is not deleted.
+func (cs *commentService) Get(id uint) (*entity.Comment, error) {
+	return cs.commentRepository.GetByID(id)
+}
+
+// GetAll returns top-level comments of the diary with their replies.
+func (cs *commentService) GetAll(diaryID, limit, offset uint) (*entity.Comments, error) {
+	comments, err := cs.commentRepository.GetAll(diaryID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	parentIDs := []uint{}
+	for _, comment := range *comments {
+		parentIDs = append(parentIDs, comment.ID)
+	}
+	replies, err := cs.commentRepository.GetAllReplies(parentIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := cs.populateAuthors(comments, replies); err != nil {
+		return nil, err
+	}
+
+	replyMap := map[uint]entity.Comments{}
+	for _, reply := range *replies {
+		replyMap[*reply.ParentID] = append(replyMap[*reply.ParentID], reply)
+	}
+	for i := range *comments {
+		commentReplies := replyMap[(*comments)[i].ID]
+		if commentReplies == nil {
+			commentReplies = entity.Comments{}
+		}
+		(*comments)[i].Replies = &commentReplies
+	}
+	return comments, nil
+}
+
+// Delete soft deletes a comment. Only author is allowed to delete.
+func (cs *commentService) Delete(comment *entity.Comment, memberID uint) error {
+	if !comment.IsAuthor(memberID) {
+		return entity.ErrNotCommentAuthor
+	}
+	return cs.commentRepository.Delete(comment)
+}
+
+// populateAuthors fetches every author of comments and replies at once.
+// 삭제된 댓글의 작성자는 노출하지 않는다.
+func (cs *commentService) populateAuthors(comments, replies *entity.Comments) error {
+	authorIDs := []uint{}
+	for _, list := range []*entity.Comments{comments, replies} {
+		for _, comment := range *list {
+			if !comment.IsDeleted() && !domain.Contains(authorIDs, comment.AuthorID) {
+				authorIDs = append(authorIDs, comment.AuthorID)
+			}
+		}
+	}
+	if len(authorIDs) == 0 {
+		return nil
+	}
+	authors, err := cs.memberRepository.GetAllByIDs(authorIDs)
+	if err != nil {
+		return err
+	}
+
+	authorMap := map[uint]entity.Member{}
+	for _, author := range *authors {
+		authorMap[author.ID] = author
+	}
+	for _, list := range []*entity.Comments{comments, replies} {
+		for i := range *list {
+			if (*list)[i].IsDeleted() {
+				continue
+			}
+			if author, ok := authorMap[(*list)[i].AuthorID]; ok {
+				(*list)[i].Author = &author
+			}
+		}
+	}
+	return nil
+}
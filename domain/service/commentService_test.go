@@ -0,0 +1,87 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+)
+
+type fakeMemberRepository struct {
+	repository.MemberRepository
+	members map[uint]entity.Member
+}
+
+func (f *fakeMemberRepository) GetAllByIDs(ids []uint) (*entity.Members, error) {
+	members := entity.Members{}
+	for _, id := range ids {
+		if member, ok := f.members[id]; ok {
+			members = append(members, member)
+		}
+	}
+	return &members, nil
+}
+
+func TestCommentServiceNotify(t *testing.T) {
+	const diaryAuthor, parentAuthor, commenter, mutedAuthor = 1, 2, 3, 4
+	members := map[uint]entity.Member{
+		diaryAuthor:  {ID: diaryAuthor, AlarmFlag: true},
+		parentAuthor: {ID: parentAuthor, AlarmFlag: true},
+		commenter:    {ID: commenter, AlarmFlag: true},
+		mutedAuthor:  {ID: mutedAuthor, AlarmFlag: false},
+	}
+	tests := []struct {
+		name         string
+		diaryAuthor  uint
+		parentAuthor uint // 0 for a top-level comment
+		wantCreated  []uint
+		wantPushed   []uint
+	}{
+		{"top-level comment", diaryAuthor, 0, []uint{diaryAuthor}, []uint{diaryAuthor}},
+		{"reply", diaryAuthor, parentAuthor, []uint{diaryAuthor, parentAuthor}, []uint{diaryAuthor, parentAuthor}},
+		{"reply to the diary author", diaryAuthor, diaryAuthor, []uint{diaryAuthor}, []uint{diaryAuthor}},
+		{"comment on own diary", commenter, 0, []uint{}, []uint{}},
+		{"muted author gets an alarm without push", mutedAuthor, parentAuthor, []uint{mutedAuthor, parentAuthor}, []uint{parentAuthor}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alarmService := &fakeAlarmService{}
+			cs := &commentService{
+				roomService:      &fakeRoomService{rooms: newFakeRoomRepository(entity.Room{ID: 10, Name: "room"})},
+				alarmService:     alarmService,
+				memberRepository: &fakeMemberRepository{members: members},
+			}
+			diary := &entity.Diary{RoomID: 10, AuthorID: tt.diaryAuthor}
+			var parent *entity.Comment
+			if tt.parentAuthor != 0 {
+				parent = &entity.Comment{AuthorID: tt.parentAuthor}
+			}
+			comment := &entity.Comment{AuthorID: commenter, Author: &entity.Member{ID: commenter, Name: "commenter"}}
+
+			if err := cs.notify(diary, parent, comment); err != nil {
+				t.Fatal(err)
+			}
+			created := []uint{}
+			for _, alarm := range alarmService.created {
+				created = append(created, alarm.MemberID)
+			}
+			if !reflect.DeepEqual(created, tt.wantCreated) {
+				t.Errorf("alarms created for %v, want %v", created, tt.wantCreated)
+			}
+			if len(alarmService.pushed) != len(tt.wantPushed) {
+				t.Fatalf("pushed to %v, want %v", alarmService.pushed, tt.wantPushed)
+			}
+			for _, memberID := range tt.wantPushed {
+				alarmID, ok := alarmService.pushed[memberID]
+				if !ok {
+					t.Fatalf("member %d is not pushed", memberID)
+				}
+				// each receiver gets the own alarm
+				if alarm := alarmService.created[alarmID-1]; alarm.MemberID != memberID {
+					t.Fatalf("member %d is pushed alarm of member %d", memberID, alarm.MemberID)
+				}
+			}
+		})
+	}
+}
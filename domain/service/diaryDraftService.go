@@ -0,0 +1,86 @@
+package service
+
+import (
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+)
+
+// DiaryDraftService ...
+type DiaryDraftService interface {
+	Get(roomID, memberID uint) (*entity.DiaryDraft, error)
+	Save(draft *entity.DiaryDraft) (*entity.DiaryDraft, error)
+	Discard(roomID, memberID uint) error
+}
+
+type diaryDraftService struct {
+	roomService          RoomService
+	diaryDraftRepository repository.DiaryDraftRepository
+}
+
+// NewDiaryDraftService ...
+func NewDiaryDraftService(ddr repository.DiaryDraftRepository, rs RoomService) DiaryDraftService {
+	return &diaryDraftService{
+		diaryDraftRepository: ddr,
+		roomService:          rs,
+	}
+}
+
+// Get returns member's draft of current turn.
+func (dds *diaryDraftService) Get(roomID, memberID uint) (*entity.DiaryDraft, error) {
+	room, err := dds.getTurnRoom(roomID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	return dds.diaryDraftRepository.GetByUnq(roomID, memberID, room.Turn)
+}
+
+// Save autosaves member's draft of current turn.
+// draft.Version must be the version which client has read. (0 for a new draft)
+func (dds *diaryDraftService) Save(draft *entity.DiaryDraft) (*entity.DiaryDraft, error) {
+	room, err := dds.getTurnRoom(draft.RoomID, draft.MemberID)
+	if err != nil {
+		return nil, err
+	}
+	draft.Turn = room.Turn
+
+	savedDraft, err := dds.diaryDraftRepository.GetByUnq(draft.RoomID, draft.MemberID, draft.Turn)
+	switch err {
+	case nil:
+		if savedDraft.Version != draft.Version {
+			return nil, entity.ErrDraftVersionConflict
+		}
+		draft.ID = savedDraft.ID
+	case entity.ErrDraftNotFound:
+		if draft.Version != 0 {
+			return nil, entity.ErrDraftVersionConflict
+		}
+		draft.ID = 0
+	default:
+		return nil, err
+	}
+	return dds.diaryDraftRepository.Save(draft)
+}
+
+// Discard removes member's draft of current turn.
+func (dds *diaryDraftService) Discard(roomID, memberID uint) error {
+	draft, err := dds.Get(roomID, memberID)
+	if err != nil {
+		return err
+	}
+	return dds.diaryDraftRepository.Delete(draft)
+}
+
+// getTurnRoom returns room only if the member is on duty. (archived room has no duty)
+func (dds *diaryDraftService) getTurnRoom(roomID, memberID uint) (*entity.Room, error) {
+	room, err := dds.roomService.Get(roomID, entity.Ignore)
+	if err != nil {
+		return nil, err
+	}
+	if room.IsArchived() {
+		return nil, entity.ErrRoomArchived
+	}
+	if !room.IsTurn(memberID) {
+		return nil, entity.ErrNotDiaryTurn
+	}
+	return room, nil
+}
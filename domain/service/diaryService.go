@@ -0,0 +1,237 @@
+package service
+
+import (
+	"github.com/ExchangeDiary/exchange-diary/domain"
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"github.com/ExchangeDiary/exchange-diary/domain/vo"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
+)
+
+// diaryPageSize is a page size which is used to fetch every diary of a room
+const diaryPageSize = 100
+
+// DiaryService ...
+type DiaryService interface {
+	Create(diary *entity.Diary) (*entity.Diary, error)
+	Publish(draft *entity.DiaryDraft) (*entity.Diary, error)
+	Get(id uint) (*entity.Diary, error)
+	GetLatest(roomID uint) (*entity.Diary, error)
+	GetAll(roomID, limit, offset, cursor uint) (*entity.Diaries, error)
+	GetAllInTurnOrder(roomID uint) (*entity.Diaries, error)
+	MarkAsRead(diary *entity.Diary, memberID uint) error
+	Search(memberID, roomID uint, cond vo.DiarySearchVO) (*entity.Diaries, error)
+	Update(diary *entity.Diary) (*entity.Diary, error)
+	Delete(diary *entity.Diary) error
+}
+
+type diaryService struct {
+	roomService          RoomService
+	fileService          FileService
+	diaryRepository      repository.DiaryRepository
+	diaryDraftRepository repository.DiaryDraftRepository
+	memberRepository     repository.MemberRepository
+	readMarkerRepository repository.ReadMarkerRepository
+}
+
+// NewDiaryService ...
+func NewDiaryService(dr repository.DiaryRepository, ddr repository.DiaryDraftRepository, mr repository.MemberRepository, rmr repository.ReadMarkerRepository, rs RoomService, fs FileService) DiaryService {
+	return &diaryService{
+		diaryRepository:      dr,
+		diaryDraftRepository: ddr,
+		memberRepository:     mr,
+		readMarkerRepository: rmr,
+		roomService:          rs,
+		fileService:          fs,
+	}
+}
+
+// Create posts a diary to the room. Only current turn member is allowed to post.
+func (ds *diaryService) Create(diary *entity.Diary) (*entity.Diary, error) {
+	return ds.post(diary, ds.diaryRepository.Create)
+}
+
+// Publish promotes a draft into a posted diary atomically.
+func (ds *diaryService) Publish(draft *entity.DiaryDraft) (*entity.Diary, error) {
+	diary, err := draft.ToDiary()
+	if err != nil {
+		return nil, err
+	}
+	return ds.post(diary, func(diary *entity.Diary) (*entity.Diary, error) {
+		return ds.diaryDraftRepository.Promote(draft, diary)
+	})
+}
+
+func (ds *diaryService) post(diary *entity.Diary, save func(diary *entity.Diary) (*entity.Diary, error)) (*entity.Diary, error) {
+	room, err := ds.roomService.Get(diary.RoomID, entity.Ignore)
+	if err != nil {
+		return nil, err
+	}
+	if room.IsArchived() {
+		return nil, entity.ErrRoomArchived
+	}
+	if !room.IsTurn(diary.AuthorID) {
+		return nil, entity.ErrNotDiaryTurn
+	}
+	diary.Turn = room.Turn
+
+	// 한번 작성된 교환일기의 사진/음성은 수정, 삭제할 수 없도록 잠근다.
+	if err := ds.lockAttachments(diary); err != nil {
+		return nil, err
+	}
+	createdDiary, err := save(diary)
+	if err != nil {
+		ds.unlockAttachments(diary)
+		return nil, err
+	}
+	return ds.populateAuthor(createdDiary)
+}
+
+// Update always fails, because posted diary is immutable.
+func (ds *diaryService) Update(diary *entity.Diary) (*entity.Diary, error) {
+	return nil, entity.ErrImmutableDiary
+}
+
+// Delete always fails, because posted diary is immutable.
+func (ds *diaryService) Delete(diary *entity.Diary) error {
+	return entity.ErrImmutableDiary
+}
+
+func (ds *diaryService) lockAttachments(diary *entity.Diary) (err error) {
+	if diary.HasPhoto() {
+		if diary.PhotoHash, err = ds.fileService.LockFile(diary.RoomID, diary.PhotoUUID, PhotoType); err != nil {
+			return err
+		}
+	}
+	if diary.HasAudio() {
+		if diary.AudioHash, err = ds.fileService.LockFile(diary.RoomID, diary.AudioUUID, AudioType); err != nil {
+			ds.unlockAttachments(diary)
+			return err
+		}
+	}
+	return nil
+}
+
+// unlockAttachments rollbacks lockAttachments
+func (ds *diaryService) unlockAttachments(diary *entity.Diary) {
+	if diary.PhotoHash != "" {
+		if err := ds.fileService.UnlockFile(diary.RoomID, diary.PhotoUUID, PhotoType); err != nil {
+			logger.Error(err.Error())
+		}
+	}
+	if diary.AudioHash != "" {
+		if err := ds.fileService.UnlockFile(diary.RoomID, diary.AudioUUID, AudioType); err != nil {
+			logger.Error(err.Error())
+		}
+	}
+}
+
+func (ds *diaryService) Get(id uint) (*entity.Diary, error) {
+	diary, err := ds.diaryRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return ds.populateAuthor(diary)
+}
+
+func (ds *diaryService) GetLatest(roomID uint) (*entity.Diary, error) {
+	diary, err := ds.diaryRepository.GetLatest(roomID)
+	if err != nil {
+		return nil, err
+	}
+	return ds.populateAuthor(diary)
+}
+
+func (ds *diaryService) GetAll(roomID, limit, offset, cursor uint) (*entity.Diaries, error) {
+	diaries, err := ds.diaryRepository.GetAll(roomID, limit, offset, cursor)
+	if err != nil {
+		return nil, err
+	}
+	return ds.populateAuthors(diaries)
+}
+
+// GetAllInTurnOrder returns every diary of the room in posted order.
+func (ds *diaryService) GetAllInTurnOrder(roomID uint) (*entity.Diaries, error) {
+	all := entity.Diaries{}
+	cursor := uint(0)
+	for {
+		diaries, err := ds.diaryRepository.GetAll(roomID, diaryPageSize, 0, cursor)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, *diaries...)
+		if len(*diaries) < diaryPageSize {
+			break
+		}
+		cursor = (*diaries)[len(*diaries)-1].ID
+	}
+	// GetAll returns latest first
+	for i, j := 0, len(all)-1; i < j; i, j = i+1, j-1 {
+		all[i], all[j] = all[j], all[i]
+	}
+	return ds.populateAuthors(&all)
+}
+
+// MarkAsRead moves member's read marker of the room forward to the diary.
+// Reading an older diary doesn't move the marker backward.
+func (ds *diaryService) MarkAsRead(diary *entity.Diary, memberID uint) error {
+	return ds.readMarkerRepository.Save(entity.NewReadMarker(memberID, diary.RoomID, diary.ID))
+}
+
+// Search finds diaries across every room which member has joined.
+// If roomID is given, only the room is searched.
+func (ds *diaryService) Search(memberID, roomID uint, cond vo.DiarySearchVO) (*entity.Diaries, error) {
+	roomIDs, err := ds.roomService.GetAllJoinedRoomIDs(memberID)
+	if err != nil {
+		return nil, err
+	}
+	if roomID != 0 {
+		if !domain.Contains(roomIDs, roomID) {
+			return nil, entity.ErrNotJoinedRoom
+		}
+		roomIDs = []uint{roomID}
+	}
+	cond.RoomIDs = roomIDs
+
+	diaries, err := ds.diaryRepository.Search(cond)
+	if err != nil {
+		return nil, err
+	}
+	return ds.populateAuthors(diaries)
+}
+
+func (ds *diaryService) populateAuthor(diary *entity.Diary) (*entity.Diary, error) {
+	author, err := ds.memberRepository.Get(diary.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	diary.Author = author
+	return diary, nil
+}
+
+// populateAuthors fetches every author of diaries at once.
+// 탈퇴한 작성자의 일기는 cascade로 제거되기 때문에 author는 항상 존재한다.
+func (ds *diaryService) populateAuthors(diaries *entity.Diaries) (*entity.Diaries, error) {
+	if len(*diaries) == 0 {
+		return diaries, nil
+	}
+	authorIDs := []uint{}
+	for _, diary := range *diaries {
+		authorIDs = append(authorIDs, diary.AuthorID)
+	}
+	authors, err := ds.memberRepository.GetAllByIDs(authorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	authorMap := map[uint]entity.Member{}
+	for _, author := range *authors {
+		authorMap[author.ID] = author
+	}
+	for i := range *diaries {
+		if author, ok := authorMap[(*diaries)[i].AuthorID]; ok {
+			(*diaries)[i].Author = &author
+		}
+	}
+	return diaries, nil
+}
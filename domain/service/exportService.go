@@ -0,0 +1,285 @@
+package service
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain"
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"github.com/ExchangeDiary/exchange-diary/domain/vo"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/clients/google/cloudstorage"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
+)
+
+// exportLease is the longest time which an export can take.
+// An unfinished export older than it is considered abandoned. (ex. server restart)
+const exportLease = time.Minute * 30
+
+// ExportService ...
+type ExportService interface {
+	Request(roomID, memberID uint) (*entity.Export, error)
+	Get(id, memberID uint) (*entity.Export, error)
+}
+
+type exportService struct {
+	roomService      RoomService
+	diaryService     DiaryService
+	fileService      FileService
+	alarmService     AlarmService
+	exportRepository repository.ExportRepository
+}
+
+// NewExportService ...
+func NewExportService(er repository.ExportRepository, rs RoomService, ds DiaryService, fs FileService, as AlarmService) ExportService {
+	return &exportService{
+		exportRepository: er,
+		roomService:      rs,
+		diaryService:     ds,
+		fileService:      fs,
+		alarmService:     as,
+	}
+}
+
+// Request starts an export job of the room in background.
+// If member already has an unfinished export of the room, it is returned instead.
+// Unless the unfinished export is stale, then it is marked as failed and a new export is started.
+func (es *exportService) Request(roomID, memberID uint) (*entity.Export, error) {
+	room, err := es.roomService.Get(roomID, entity.Ignore)
+	if err != nil {
+		return nil, err
+	}
+	if !room.IsAlreadyJoined(memberID) {
+		return nil, entity.ErrNotJoinedRoom
+	}
+
+	export, err := es.exportRepository.GetUnfinished(roomID, memberID)
+	if err == nil {
+		if !export.IsStale(domain.CurrentDateTime(), exportLease) {
+			return export, nil
+		}
+		export.Fail(entity.ErrExportExpired)
+		if _, err := es.exportRepository.Update(export); err != nil {
+			return nil, err
+		}
+	} else if err != entity.ErrExportNotFound {
+		return nil, err
+	}
+
+	if export, err = es.exportRepository.Create(entity.NewExport(roomID, memberID)); err != nil {
+		return nil, err
+	}
+	go es.run(*export, room)
+	return export, nil
+}
+
+// Get returns member's export.
+func (es *exportService) Get(id, memberID uint) (*entity.Export, error) {
+	export, err := es.exportRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if export.MemberID != memberID {
+		return nil, entity.ErrExportNotFound
+	}
+	return export, nil
+}
+
+// run archives the room, uploads it and notifies the member.
+// If server restarts while running, export remains unfinished until it becomes stale. (see exportLease)
+func (es *exportService) run(export entity.Export, room *entity.Room) {
+	defer func() {
+		if r := recover(); r != nil {
+			es.fail(&export, fmt.Errorf("export panic: %v", r))
+		}
+	}()
+
+	export.Start()
+	if _, err := es.exportRepository.Update(&export); err != nil {
+		logger.Error(err.Error())
+		return
+	}
+
+	vItem, err := es.archive(&export, room)
+	if err != nil {
+		es.fail(&export, err)
+		return
+	}
+	export.Finish(vItem.URL().String(), vItem.DownloadURL().String())
+	if _, err := es.exportRepository.Update(&export); err != nil {
+		logger.Error(err.Error())
+		return
+	}
+
+	alarm, err := es.alarmService.Create(export.MemberID, room.ID, vo.ExportReadyCode, room.Name, "", "")
+	if err != nil {
+		logger.Error(err.Error())
+		return
+	}
+	if err := es.alarmService.PushByID(export.MemberID, alarm); err != nil {
+		logger.Error(err.Error())
+	}
+}
+
+func (es *exportService) fail(export *entity.Export, err error) {
+	logger.Error(err.Error())
+	export.Fail(err)
+	if _, err := es.exportRepository.Update(export); err != nil {
+		logger.Error(err.Error())
+	}
+}
+
+// archive writes ZIP into a temp file and uploads it to the bucket.
+func (es *exportService) archive(export *entity.Export, room *entity.Room) (*cloudstorage.VItem, error) {
+	diaries, err := es.diaryService.GetAllInTurnOrder(room.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tmp, err := ioutil.TempFile("", fmt.Sprintf("export-%d-*.zip", export.ID))
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if err := es.writeArchive(tmp, room, *diaries); err != nil {
+		return nil, err
+	}
+	size, err := tmp.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return es.fileService.PutFile(room.ID, fmt.Sprintf("%d.zip", export.ID), tmp, size, ExportType)
+}
+
+type exportManifest struct {
+	Room       exportManifestRoom    `json:"room"`
+	ExportedAt time.Time             `json:"exportedAt"`
+	Diaries    []exportManifestDiary `json:"diaries"`
+}
+
+type exportManifestRoom struct {
+	ID     uint   `json:"id"`
+	Name   string `json:"name"`
+	Theme  string `json:"theme"`
+	Period uint8  `json:"period"`
+}
+
+type exportManifestDiary struct {
+	ID         uint       `json:"id"`
+	Turn       uint       `json:"turn"`
+	Title      string     `json:"title"`
+	Author     string     `json:"author"`
+	File       string     `json:"file"`
+	Photo      string     `json:"photo,omitempty"`
+	Audio      string     `json:"audio,omitempty"`
+	AudioTitle string     `json:"audioTitle,omitempty"`
+	AudioPitch string     `json:"audioPitch,omitempty"`
+	CreatedAt  *time.Time `json:"createdAt"`
+}
+
+// writeArchive writes ZIP which consists of
+// - manifest.json
+// - diaries/{turn}.md
+// - attachments/{turn}_{photo|audio}_{uuid}
+func (es *exportService) writeArchive(w io.Writer, room *entity.Room, diaries []entity.Diary) error {
+	zw := zip.NewWriter(w)
+	manifest := exportManifest{
+		Room: exportManifestRoom{
+			ID:     room.ID,
+			Name:   room.Name,
+			Theme:  room.Theme,
+			Period: room.Period,
+		},
+		ExportedAt: domain.CurrentDateTime(),
+		Diaries:    []exportManifestDiary{},
+	}
+
+	for i := range diaries {
+		diary := &diaries[i]
+		item := exportManifestDiary{
+			ID:        diary.ID,
+			Turn:      diary.Turn,
+			Title:     diary.Title,
+			File:      fmt.Sprintf("diaries/%03d.md", diary.Turn),
+			CreatedAt: diary.CreatedAt,
+		}
+		if diary.Author != nil {
+			item.Author = diary.Author.Name
+		}
+		if diary.HasPhoto() {
+			item.Photo = fmt.Sprintf("attachments/%03d_photo_%s", diary.Turn, diary.PhotoUUID)
+			if err := es.writeAttachment(zw, item.Photo, diary.RoomID, diary.PhotoUUID, PhotoType); err != nil {
+				return err
+			}
+		}
+		if diary.HasAudio() {
+			item.Audio = fmt.Sprintf("attachments/%03d_audio_%s", diary.Turn, diary.AudioUUID)
+			item.AudioTitle = diary.AudioTitle
+			item.AudioPitch = diary.AudioPitch
+			if err := es.writeAttachment(zw, item.Audio, diary.RoomID, diary.AudioUUID, AudioType); err != nil {
+				return err
+			}
+		}
+
+		f, err := zw.Create(item.File)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, toMarkdown(item, diary.Body)); err != nil {
+			return err
+		}
+		manifest.Diaries = append(manifest.Diaries, item)
+	}
+
+	f, err := zw.Create("manifest.json")
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// writeAttachment streams a photo/audio object from the bucket into the archive.
+func (es *exportService) writeAttachment(zw *zip.Writer, name string, roomID uint, fileUUID string, ftype FileType) error {
+	r, err := es.fileService.OpenFile(roomID, fileUUID, ftype)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	return err
+}
+
+func toMarkdown(item exportManifestDiary, body string) string {
+	md := fmt.Sprintf("# %s\n\n- 작성자: %s\n- 차례: %d\n", item.Title, item.Author, item.Turn)
+	if item.CreatedAt != nil {
+		md += fmt.Sprintf("- 작성일: %s\n", item.CreatedAt.Format(time.RFC3339))
+	}
+	md += "\n" + body + "\n"
+	if item.Photo != "" {
+		md += fmt.Sprintf("\n![사진](../%s)\n", item.Photo)
+	}
+	if item.Audio != "" {
+		md += fmt.Sprintf("\n[음성: %s](../%s)\n", item.AudioTitle, item.Audio)
+	}
+	return md
+}
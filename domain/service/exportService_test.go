@@ -0,0 +1,107 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain"
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+)
+
+// fakeExportRepository keeps exports in memory. (background run also updates it)
+type fakeExportRepository struct {
+	repository.ExportRepository
+	mu      sync.Mutex
+	exports map[uint]entity.Export
+}
+
+func (f *fakeExportRepository) Create(export *entity.Export) (*entity.Export, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	created := *export
+	created.ID = uint(len(f.exports) + 1)
+	f.exports[created.ID] = created
+	return &created, nil
+}
+
+func (f *fakeExportRepository) GetUnfinished(roomID, memberID uint) (*entity.Export, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, export := range f.exports {
+		if export.RoomID == roomID && export.MemberID == memberID && !export.IsFinished() {
+			return &export, nil
+		}
+	}
+	return nil, entity.ErrExportNotFound
+}
+
+func (f *fakeExportRepository) Update(export *entity.Export) (*entity.Export, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.exports[export.ID] = *export
+	return export, nil
+}
+
+func (f *fakeExportRepository) get(id uint) entity.Export {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.exports[id]
+}
+
+// fakeDiaryService fails every archive, so background run finishes right away.
+type fakeDiaryService struct {
+	DiaryService
+}
+
+func (fakeDiaryService) GetAllInTurnOrder(roomID uint) (*entity.Diaries, error) {
+	return nil, errors.New("diary service is not available")
+}
+
+func TestExportServiceRequest(t *testing.T) {
+	const roomID, memberID = 1, 2
+	now := domain.CurrentDateTime()
+	ago := func(d time.Duration) *time.Time {
+		t := now.Add(-d)
+		return &t
+	}
+
+	tests := []struct {
+		name       string
+		unfinished *entity.Export // nil means there is no unfinished export
+		wantReused bool
+	}{
+		{"no unfinished export", nil, false},
+		{"fresh pending export is reused", &entity.Export{Status: entity.ExportPending, CreatedAt: ago(time.Minute)}, true},
+		{"fresh running export is reused", &entity.Export{Status: entity.ExportRunning, CreatedAt: ago(time.Hour), StartedAt: ago(time.Minute)}, true},
+		{"stale pending export is failed", &entity.Export{Status: entity.ExportPending, CreatedAt: ago(exportLease + time.Minute)}, false},
+		{"stale running export is failed", &entity.Export{Status: entity.ExportRunning, CreatedAt: ago(time.Hour), StartedAt: ago(exportLease)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			er := &fakeExportRepository{exports: map[uint]entity.Export{}}
+			if tt.unfinished != nil {
+				tt.unfinished.RoomID, tt.unfinished.MemberID = roomID, memberID
+				er.Create(tt.unfinished)
+			}
+			rs := &fakeRoomService{rooms: newFakeRoomRepository(entity.Room{ID: roomID, MasterID: memberID, Orders: []uint{memberID}})}
+			es := NewExportService(er, rs, fakeDiaryService{}, nil, nil)
+
+			export, err := es.Request(roomID, memberID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if reused := tt.unfinished != nil && export.ID == 1; reused != tt.wantReused {
+				t.Fatalf("Request() reused = %v, want %v", reused, tt.wantReused)
+			}
+			if tt.unfinished == nil || tt.wantReused {
+				return
+			}
+			if old := er.get(1); old.Status != entity.ExportFailed || old.Error != entity.ErrExportExpired.Error() {
+				t.Errorf("stale export = %s (%s), want %s (%s)", old.Status, old.Error, entity.ExportFailed, entity.ErrExportExpired)
+			}
+		})
+	}
+}
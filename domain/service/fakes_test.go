@@ -0,0 +1,277 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"github.com/ExchangeDiary/exchange-diary/domain/vo"
+)
+
+// fakeUnitOfWork runs fn with in-memory repositories. Nothing is rolled back.
+type fakeUnitOfWork struct {
+	tx    *fakeTransaction
+	calls int
+}
+
+func newFakeUnitOfWork() *fakeUnitOfWork {
+	return &fakeUnitOfWork{tx: &fakeTransaction{
+		rooms:  newFakeRoomRepository(),
+		outbox: &fakeOutboxRepository{},
+		audits: &fakeAdminAuditRepository{},
+		drafts: &fakeDiaryDraftRepository{},
+		swaps:  &fakeSwapRequestRepository{requests: map[uint]entity.SwapRequest{}},
+	}}
+}
+
+func (f *fakeUnitOfWork) Do(fn func(tx repository.Transaction) error) error {
+	f.calls++
+	return fn(f.tx)
+}
+
+type fakeTransaction struct {
+	repository.Transaction
+	rooms  *fakeRoomRepository
+	outbox *fakeOutboxRepository
+	audits *fakeAdminAuditRepository
+	drafts *fakeDiaryDraftRepository
+	swaps  *fakeSwapRequestRepository
+}
+
+func (f *fakeTransaction) Rooms() repository.RoomRepository {
+	return f.rooms
+}
+
+func (f *fakeTransaction) Outbox() repository.OutboxRepository {
+	return f.outbox
+}
+
+func (f *fakeTransaction) AdminAudits() repository.AdminAuditRepository {
+	return f.audits
+}
+
+func (f *fakeTransaction) DiaryDrafts() repository.DiaryDraftRepository {
+	return f.drafts
+}
+
+func (f *fakeTransaction) SwapRequests() repository.SwapRequestRepository {
+	return f.swaps
+}
+
+type fakeRoomRepository struct {
+	repository.RoomRepository
+	rooms map[uint]entity.Room
+	locks int // calls of GetByIDForUpdate
+}
+
+func newFakeRoomRepository(rooms ...entity.Room) *fakeRoomRepository {
+	f := &fakeRoomRepository{rooms: map[uint]entity.Room{}}
+	for _, room := range rooms {
+		f.rooms[room.ID] = room
+	}
+	return f
+}
+
+func (f *fakeRoomRepository) GetByID(id uint) (*entity.Room, error) {
+	room, ok := f.rooms[id]
+	if !ok {
+		return nil, entity.ErrRoomNotFound
+	}
+	return &room, nil
+}
+
+func (f *fakeRoomRepository) GetByIDForUpdate(id uint) (*entity.Room, error) {
+	f.locks++
+	return f.GetByID(id)
+}
+
+func (f *fakeRoomRepository) Update(room *entity.Room) (*entity.Room, error) {
+	f.rooms[room.ID] = *room
+	return room, nil
+}
+
+func (f *fakeRoomRepository) UpdateAwayUntil(room *entity.Room) error {
+	saved, ok := f.rooms[room.ID]
+	if !ok {
+		return entity.ErrRoomNotFound
+	}
+	saved.AwayUntil = room.AwayUntil
+	f.rooms[room.ID] = saved
+	return nil
+}
+
+type fakeDiaryDraftRepository struct {
+	repository.DiaryDraftRepository
+}
+
+func (f *fakeDiaryDraftRepository) DeleteAllBefore(roomID, turn uint) error {
+	return nil
+}
+
+type fakeAdminAuditRepository struct {
+	repository.AdminAuditRepository
+	audits []entity.AdminAudit
+}
+
+func (f *fakeAdminAuditRepository) Create(audit *entity.AdminAudit) (*entity.AdminAudit, error) {
+	f.audits = append(f.audits, *audit)
+	return audit, nil
+}
+
+// fakeOutboxRepository records written and updated messages.
+type fakeOutboxRepository struct {
+	repository.OutboxRepository
+	messages []*entity.OutboxMessage
+	updated  []entity.OutboxMessage
+}
+
+func (f *fakeOutboxRepository) Create(messages ...*entity.OutboxMessage) error {
+	f.messages = append(f.messages, messages...)
+	return nil
+}
+
+func (f *fakeOutboxRepository) Update(message *entity.OutboxMessage) error {
+	f.updated = append(f.updated, *message)
+	return nil
+}
+
+type fakeSwapRequestRepository struct {
+	repository.SwapRequestRepository
+	requests map[uint]entity.SwapRequest
+}
+
+func (f *fakeSwapRequestRepository) GetByID(id uint) (*entity.SwapRequest, error) {
+	request, ok := f.requests[id]
+	if !ok {
+		return nil, entity.ErrSwapRequestNotFound
+	}
+	return &request, nil
+}
+
+func (f *fakeSwapRequestRepository) Update(request *entity.SwapRequest) (*entity.SwapRequest, error) {
+	if f.requests[request.ID].Status != entity.SwapPending {
+		return nil, entity.ErrSwapRequestNotPending
+	}
+	f.requests[request.ID] = *request
+	return request, nil
+}
+
+// fakeRoomService reads rooms from fakeRoomRepository, so room updates of a transaction are visible.
+type fakeRoomService struct {
+	RoomService
+	rooms *fakeRoomRepository
+}
+
+func (f *fakeRoomService) Get(id uint, orderBy entity.RoomMemberOrderBy) (*entity.Room, error) {
+	return f.rooms.GetByID(id)
+}
+
+// JoinRoomByInvite joins every valid invite.
+func (f *fakeRoomService) JoinRoomByInvite(invite *entity.RoomInvite, accountID uint) (*entity.Room, error) {
+	if err := invite.Validate(time.Now()); err != nil {
+		return nil, err
+	}
+	return &entity.Room{ID: invite.RoomID}, nil
+}
+
+type fakeMemberService struct {
+	MemberService
+	members map[uint]entity.Member
+}
+
+func (f *fakeMemberService) Get(id uint) (*entity.Member, error) {
+	member, ok := f.members[id]
+	if !ok {
+		return nil, entity.ErrMemberNotFound
+	}
+	return &member, nil
+}
+
+// fakeAlarmService records created alarms and pushes. (alarm id is a sequence)
+type fakeAlarmService struct {
+	AlarmService
+	created []entity.Alarm
+	pushed  map[uint]uint // member id -> alarm id
+}
+
+func (f *fakeAlarmService) Create(memberID, roomID uint, code vo.TaskCode, roomName, diaryTitle, authorNickname string) (*entity.Alarm, error) {
+	alarm := entity.Alarm{ID: uint(len(f.created) + 1), MemberID: memberID, RoomID: roomID, Code: string(code)}
+	f.created = append(f.created, alarm)
+	return &alarm, nil
+}
+
+func (f *fakeAlarmService) PushByID(memberID uint, al *entity.Alarm) error {
+	if f.pushed == nil {
+		f.pushed = map[uint]uint{}
+	}
+	f.pushed[memberID] = al.ID
+	return nil
+}
+
+// fakeScheduler behaves like google cloud tasks. A task name is never reusable, even after it is deleted.
+// scheduleErr and cancelErr fail every call, as an unavailable scheduler does.
+type fakeScheduler struct {
+	tasks       map[string]entity.ScheduledTask // task id -> latest instance
+	names       map[string]bool                 // every registered name
+	scheduleErr error
+	cancelErr   error
+}
+
+func newFakeScheduler() *fakeScheduler {
+	return &fakeScheduler{tasks: map[string]entity.ScheduledTask{}, names: map[string]bool{}}
+}
+
+func (s *fakeScheduler) Schedule(task *entity.ScheduledTask) (*entity.ScheduledTask, error) {
+	if s.scheduleErr != nil {
+		return nil, s.scheduleErr
+	}
+	if s.names[task.Name()] {
+		if current, ok := s.tasks[task.ID]; ok && current.Instance == task.Instance {
+			return &current, nil
+		}
+		return nil, entity.ErrScheduledTaskAlreadyExists
+	}
+	s.names[task.Name()] = true
+	s.tasks[task.ID] = *task
+	return task, nil
+}
+
+func (s *fakeScheduler) Cancel(id string) error {
+	if s.cancelErr != nil {
+		return s.cancelErr
+	}
+	if _, ok := s.tasks[id]; !ok {
+		return entity.ErrScheduledTaskNotFound
+	}
+	delete(s.tasks, id)
+	return nil
+}
+
+func (s *fakeScheduler) Get(id string) (*entity.ScheduledTask, error) {
+	task, ok := s.tasks[id]
+	if !ok {
+		return nil, entity.ErrScheduledTaskNotFound
+	}
+	return &task, nil
+}
+
+func (s *fakeScheduler) List() ([]entity.ScheduledTask, error) {
+	tasks := []entity.ScheduledTask{}
+	for _, task := range s.tasks {
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// relayOutbox delivers every written outbox message to the scheduler in order, and clears the outbox.
+func relayOutbox(t *testing.T, uow *fakeUnitOfWork, scheduler Scheduler) {
+	t.Helper()
+	relay := &outboxRelay{scheduler: scheduler}
+	for _, message := range uow.tx.outbox.messages {
+		if err := relay.deliver(message); err != nil {
+			t.Fatalf("deliver(%s %s) = %v", message.Action, message.Task.ID, err)
+		}
+	}
+	uow.tx.outbox.messages = nil
+}
@@ -2,15 +2,24 @@ package service
 
 import (
 	"fmt"
+	"io"
 	"mime/multipart"
 
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
 	"github.com/ExchangeDiary/exchange-diary/infrastructure/clients/google/cloudstorage"
 	"github.com/pkg/errors"
 )
 
+// BucketName is a google cloud storage bucket which stores voda static files
+const BucketName = "voda_bucket"
+
 // FileService ...
 type FileService interface {
 	UploadFile(bkt *cloudstorage.VBucket, roomID uint, fileUUID string, file *multipart.FileHeader, ftype FileType) (vItem *cloudstorage.VItem, err error)
+	LockFile(roomID uint, fileUUID string, ftype FileType) (hash string, err error)
+	UnlockFile(roomID uint, fileUUID string, ftype FileType) error
+	OpenFile(roomID uint, fileUUID string, ftype FileType) (io.ReadCloser, error)
+	PutFile(roomID uint, fileUUID string, r io.Reader, size int64, ftype FileType) (*cloudstorage.VItem, error)
 }
 
 type fileService struct{}
@@ -23,6 +32,8 @@ const (
 	AudioType FileType = iota
 	// PhotoType ...
 	PhotoType
+	// ExportType is a ZIP archive of room's diaries
+	ExportType
 )
 
 func (ft *FileType) toString() string {
@@ -32,6 +43,8 @@ func (ft *FileType) toString() string {
 		val = "audio"
 	case PhotoType:
 		val = "photo"
+	case ExportType:
+		val = "export"
 	}
 	return val
 }
@@ -42,6 +55,7 @@ func NewFileService() FileService {
 }
 
 // UploadFile uploads file to cloudstorage bucket
+// The file which is attached to posted diary cannot be overwritten.
 func (fs *fileService) UploadFile(bkt *cloudstorage.VBucket, roomID uint, fileUUID string, file *multipart.FileHeader, ftype FileType) (*cloudstorage.VItem, error) {
 	openedFile, err := file.Open()
 	if err != nil {
@@ -60,11 +74,62 @@ func (fs *fileService) UploadFile(bkt *cloudstorage.VBucket, roomID uint, fileUU
 	}
 
 	if err != nil {
+		if err == cloudstorage.ErrLocked {
+			return nil, entity.ErrLockedAttachment
+		}
 		return nil, err
 	}
 	return vItem, nil
 }
 
+// LockFile locks uploaded file when it is attached to a diary and returns content hash of it.
+func (fs *fileService) LockFile(roomID uint, fileUUID string, ftype FileType) (string, error) {
+	bkt, err := cloudstorage.GetClient().VBucket(BucketName)
+	if err != nil {
+		return "", err
+	}
+	vItem, err := bkt.Lock(fs.cloudStoragePath(roomID, fileUUID, ftype))
+	if err != nil {
+		if err == cloudstorage.ErrNotFound {
+			return "", errors.Errorf(`%s '%s' is not uploaded`, ftype.toString(), fileUUID)
+		}
+		return "", err
+	}
+	return vItem.Hash(), nil
+}
+
+// UnlockFile is used to rollback LockFile, when posting a diary is failed.
+func (fs *fileService) UnlockFile(roomID uint, fileUUID string, ftype FileType) error {
+	bkt, err := cloudstorage.GetClient().VBucket(BucketName)
+	if err != nil {
+		return err
+	}
+	_, err = bkt.Unlock(fs.cloudStoragePath(roomID, fileUUID, ftype))
+	return err
+}
+
+// OpenFile opens uploaded file to stream its content.
+func (fs *fileService) OpenFile(roomID uint, fileUUID string, ftype FileType) (io.ReadCloser, error) {
+	bkt, err := cloudstorage.GetClient().VBucket(BucketName)
+	if err != nil {
+		return nil, err
+	}
+	vItem, err := bkt.VItem(fs.cloudStoragePath(roomID, fileUUID, ftype))
+	if err != nil {
+		return nil, err
+	}
+	return vItem.Open()
+}
+
+// PutFile uploads content of reader which is generated by server. (e.g. export archive)
+func (fs *fileService) PutFile(roomID uint, fileUUID string, r io.Reader, size int64, ftype FileType) (*cloudstorage.VItem, error) {
+	bkt, err := cloudstorage.GetClient().VBucket(BucketName)
+	if err != nil {
+		return nil, err
+	}
+	return bkt.Put(fs.cloudStoragePath(roomID, fileUUID, ftype), r, size, make(map[string]interface{}))
+}
+
 func (fs *fileService) cloudStoragePath(roomID uint, fileUUID string, ftype FileType) string {
 	return fmt.Sprintf(
 		"%d/%s/%s",
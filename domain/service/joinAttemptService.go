@@ -0,0 +1,111 @@
+package service
+
+import (
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"github.com/ExchangeDiary/exchange-diary/domain/vo"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
+)
+
+// JoinAttemptService guards room code from brute-force.
+// Failures are counted per (room, member) and per ip, and each key is locked out exponentially longer on repeated failures.
+type JoinAttemptService interface {
+	Attempt(room *entity.Room, accountID uint, clientIP string, verify func() bool) (bool, error)
+}
+
+type joinAttemptService struct {
+	joinAttemptRepository repository.JoinAttemptRepository
+	memberService         MemberService
+	alarmService          AlarmService
+}
+
+// NewJoinAttemptService ...
+func NewJoinAttemptService(jar repository.JoinAttemptRepository, ms MemberService, as AlarmService) JoinAttemptService {
+	return &joinAttemptService{
+		joinAttemptRepository: jar,
+		memberService:         ms,
+		alarmService:          as,
+	}
+}
+
+// Attempt counts a failure of the member on the room and of the ip before verify runs,
+// so that concurrent attempts cannot all pass the lockout check and verify the code.
+// entity.ErrRoomJoinLocked is returned without running verify while any key is locked out.
+// If verify succeeds, failures of the member are forgotten and the failure of the ip is taken back.
+// The master is alarmed whenever the member is locked out by a wrong code.
+func (jas *joinAttemptService) Attempt(room *entity.Room, accountID uint, clientIP string, verify func() bool) (bool, error) {
+	now := time.Now()
+	memberKey := entity.MemberJoinAttemptKey(room.ID, accountID)
+	memberLocked, memberLockedUntil, err := jas.reserve(memberKey, entity.MemberJoinAttemptThreshold, now)
+	if err != nil {
+		return false, err
+	}
+	var ipLockedUntil *time.Time
+	if clientIP != "" {
+		if _, ipLockedUntil, err = jas.reserve(entity.IPJoinAttemptKey(clientIP), entity.IPJoinAttemptThreshold, now); err != nil {
+			jas.refund(memberKey, memberLockedUntil)
+			return false, err
+		}
+	}
+
+	if !verify() {
+		if memberLocked {
+			jas.notifyMaster(room, accountID)
+		}
+		return false, nil
+	}
+	if err := jas.joinAttemptRepository.Delete(memberKey); err != nil {
+		logger.Error(err.Error())
+	}
+	if clientIP != "" {
+		jas.refund(entity.IPJoinAttemptKey(clientIP), ipLockedUntil)
+	}
+	return true, nil
+}
+
+// reserve counts a failure of key unless it is locked out, and returns the lockout set by it.
+func (jas *joinAttemptService) reserve(key string, threshold uint, now time.Time) (bool, *time.Time, error) {
+	var locked bool
+	var lockedUntil *time.Time
+	if _, err := jas.joinAttemptRepository.Update(key, func(attempt *entity.JoinAttempt) error {
+		if attempt.IsLocked(now) {
+			return entity.ErrRoomJoinLocked
+		}
+		if locked = attempt.Fail(now, threshold); locked {
+			lockedUntil = attempt.LockedUntil
+		}
+		return nil
+	}); err != nil {
+		return false, nil, err
+	}
+	return locked, lockedUntil, nil
+}
+
+// refund takes back a reserved failure of key. Failure of refund does not fail the attempt.
+func (jas *joinAttemptService) refund(key string, lockedUntil *time.Time) {
+	if _, err := jas.joinAttemptRepository.Update(key, func(attempt *entity.JoinAttempt) error {
+		attempt.Refund(lockedUntil)
+		return nil
+	}); err != nil {
+		logger.Error(err.Error())
+	}
+}
+
+// notifyMaster sends ROOM_JOIN_LOCKED alarm to the master. Failure of alarm does not fail the attempt.
+func (jas *joinAttemptService) notifyMaster(room *entity.Room, accountID uint) {
+	member, err := jas.memberService.Get(accountID)
+	if err != nil {
+		logger.Error(err.Error())
+		return
+	}
+	alarm, err := jas.alarmService.Create(room.MasterID, room.ID, vo.RoomJoinLockedCode, room.Name, "", member.Name)
+	if err != nil {
+		logger.Error(err.Error())
+		return
+	}
+	if err := jas.alarmService.PushByID(room.MasterID, alarm); err != nil {
+		logger.Error(err.Error())
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+)
+
+type fakeJoinAttemptRepository struct {
+	repository.JoinAttemptRepository
+	attempts map[string]entity.JoinAttempt
+}
+
+func (f *fakeJoinAttemptRepository) Update(key string, fn func(attempt *entity.JoinAttempt) error) (*entity.JoinAttempt, error) {
+	attempt := f.attempts[key]
+	attempt.Key = key
+	if err := fn(&attempt); err != nil {
+		return nil, err
+	}
+	f.attempts[key] = attempt
+	return &attempt, nil
+}
+
+func (f *fakeJoinAttemptRepository) Delete(key string) error {
+	delete(f.attempts, key)
+	return nil
+}
+
+func TestJoinAttemptServiceAttempt(t *testing.T) {
+	const roomID, masterID, accountID, clientIP = 1, 2, 3, "1.2.3.4"
+	memberKey := entity.MemberJoinAttemptKey(roomID, accountID)
+	ipKey := entity.IPJoinAttemptKey(clientIP)
+	locked := time.Now().Add(time.Hour)
+	lastFailedAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name         string
+		attempts     map[string]entity.JoinAttempt
+		code         bool
+		wantVerified bool
+		wantErr      error
+		wantVerify   bool // whether the code is verified at all
+		wantFailures map[string]uint
+		wantAlarmed  bool
+	}{
+		{"wrong code", map[string]entity.JoinAttempt{}, false, false, nil, true,
+			map[string]uint{memberKey: 1, ipKey: 1}, false},
+		{"wrong code locks the member out", map[string]entity.JoinAttempt{
+			memberKey: {Failures: entity.MemberJoinAttemptThreshold - 1, LastFailedAt: &lastFailedAt},
+		}, false, false, nil, true,
+			map[string]uint{memberKey: entity.MemberJoinAttemptThreshold, ipKey: 1}, true},
+		{"right code forgets the member and takes back the ip failure", map[string]entity.JoinAttempt{
+			memberKey: {Failures: 2, LastFailedAt: &lastFailedAt},
+			ipKey:     {Failures: 3, LastFailedAt: &lastFailedAt},
+		}, true, true, nil, true,
+			map[string]uint{ipKey: 3}, false},
+		{"right code at the threshold is not locked out", map[string]entity.JoinAttempt{
+			memberKey: {Failures: entity.MemberJoinAttemptThreshold - 1, LastFailedAt: &lastFailedAt},
+		}, true, true, nil, true,
+			map[string]uint{ipKey: 0}, false},
+		{"locked member is not verified", map[string]entity.JoinAttempt{
+			memberKey: {Failures: entity.MemberJoinAttemptThreshold, LockedUntil: &locked, LastFailedAt: &lastFailedAt},
+		}, true, false, entity.ErrRoomJoinLocked, false,
+			map[string]uint{memberKey: entity.MemberJoinAttemptThreshold}, false},
+		{"locked ip is not verified, and the member failure is taken back", map[string]entity.JoinAttempt{
+			memberKey: {Failures: 1, LastFailedAt: &lastFailedAt},
+			ipKey:     {Failures: entity.IPJoinAttemptThreshold, LockedUntil: &locked, LastFailedAt: &lastFailedAt},
+		}, true, false, entity.ErrRoomJoinLocked, false,
+			map[string]uint{memberKey: 1, ipKey: entity.IPJoinAttemptThreshold}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeJoinAttemptRepository{attempts: tt.attempts}
+			alarmService := &fakeAlarmService{}
+			jas := NewJoinAttemptService(repo, &fakeMemberService{members: map[uint]entity.Member{accountID: {ID: accountID}}}, alarmService)
+			room := &entity.Room{ID: roomID, MasterID: masterID}
+
+			verified := false
+			got, err := jas.Attempt(room, accountID, clientIP, func() bool {
+				verified = true
+				// every attempt is counted while the code is verified
+				if repo.attempts[memberKey].Failures == 0 || repo.attempts[ipKey].Failures == 0 {
+					t.Error("code is verified before the attempt is counted")
+				}
+				return tt.code
+			})
+			if got != tt.wantVerified || err != tt.wantErr {
+				t.Fatalf("Attempt() = %t, %v, want %t, %v", got, err, tt.wantVerified, tt.wantErr)
+			}
+			if verified != tt.wantVerify {
+				t.Errorf("verified = %t, want %t", verified, tt.wantVerify)
+			}
+			if len(repo.attempts) != len(tt.wantFailures) {
+				t.Errorf("attempts = %v, want %v", repo.attempts, tt.wantFailures)
+			}
+			for key, want := range tt.wantFailures {
+				if attempt := repo.attempts[key]; attempt.Failures != want {
+					t.Errorf("%s failures = %d, want %d", key, attempt.Failures, want)
+				}
+			}
+			if _, alarmed := alarmService.pushed[masterID]; alarmed != tt.wantAlarmed {
+				t.Errorf("master alarmed = %t, want %t", alarmed, tt.wantAlarmed)
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ type MemberService interface {
 	Create(email string, name string, profileURL string, authType string) (*entity.Member, error)
 	Get(ID uint) (*entity.Member, error)
 	GetByEmail(email string) (*entity.Member, error)
+	GetAllByIDs(ids []uint) (*entity.Members, error)
 	Update(member *entity.Member) (*entity.Member, error)
 	Delete(email string) error
 	VerifyNickName(name string) (bool, error)
@@ -56,6 +57,10 @@ func (s *memberService) GetByEmail(email string) (*entity.Member, error) {
 	return member, nil
 }
 
+func (s *memberService) GetAllByIDs(ids []uint) (*entity.Members, error) {
+	return s.memberRepository.GetAllByIDs(ids)
+}
+
 func (s *memberService) Update(member *entity.Member) (*entity.Member, error) {
 	updatedMember, err := s.memberRepository.Update(member)
 	if err != nil {
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	relayPollInterval = time.Second
+	relayLease        = time.Minute
+	relayBatchSize    = 50
+)
+
+// OutboxRelay delivers outbox messages to scheduler.
+type OutboxRelay interface {
+	Start()
+	Stop()
+}
+
+type outboxRelay struct {
+	outboxRepository repository.OutboxRepository
+	scheduler        Scheduler
+	stop             chan struct{}
+	wg               sync.WaitGroup
+}
+
+// NewOutboxRelay ...
+func NewOutboxRelay(or repository.OutboxRepository, sch Scheduler) OutboxRelay {
+	return &outboxRelay{
+		outboxRepository: or,
+		scheduler:        sch,
+		stop:             make(chan struct{}),
+	}
+}
+
+// Start runs relay goroutine.
+func (r *outboxRelay) Start() {
+	r.wg.Add(1)
+	go r.run()
+}
+
+// Stop waits until current batch is delivered.
+func (r *outboxRelay) Stop() {
+	close(r.stop)
+	r.wg.Wait()
+}
+
+func (r *outboxRelay) run() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(relayPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		}
+
+		messages, err := r.outboxRepository.Claim(time.Now(), relayLease, relayBatchSize)
+		if err != nil {
+			logger.Error(err.Error())
+			continue
+		}
+		// messages are delivered one by one in order
+		for i := range messages {
+			r.relay(&messages[i])
+		}
+	}
+}
+
+func (r *outboxRelay) relay(message *entity.OutboxMessage) {
+	if err := r.deliver(message); err != nil {
+		logger.Error("outbox message delivery failed",
+			zap.Uint("messageID", message.ID),
+			zap.String("taskID", message.Task.ID),
+			zap.Uint("attempts", message.Attempts+1),
+			zap.Error(err))
+		message.Retry(err, time.Now())
+	} else {
+		message.Deliver(time.Now())
+	}
+	if err := r.outboxRepository.Update(message); err != nil {
+		logger.Error(err.Error())
+	}
+}
+
+// deliver is idempotent. A message can be delivered twice when relay stops after delivery and before update.
+// Scheduler accepts the same task instance twice, but never an other instance which has the same name.
+func (r *outboxRelay) deliver(message *entity.OutboxMessage) error {
+	switch message.Action {
+	case entity.OutboxSchedule:
+		if _, err := r.scheduler.Schedule(&message.Task); err != nil {
+			return err
+		}
+	case entity.OutboxCancel:
+		if err := r.scheduler.Cancel(message.Task.ID); err != nil && err != entity.ErrScheduledTaskNotFound {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/vo"
+)
+
+func TestOutboxRelayDeliver(t *testing.T) {
+	task := entity.NewScheduledTask("1-2-ROOM_PERIOD_FIN", vo.NewTaskVO(1, "", vo.RoomPeriodFinCode), "", rightNow)
+	transient := errors.New("unavailable")
+	tests := []struct {
+		name        string
+		message     *entity.OutboxMessage
+		held        bool // the task is held by scheduler
+		scheduleErr error
+		cancelErr   error
+		wantErr     error
+		wantHeld    bool
+	}{
+		{"schedule", entity.NewScheduleMessage(task), false, nil, nil, nil, true},
+		// an other instance holds the name. the message must not be marked as delivered.
+		{"schedule conflict", entity.NewScheduleMessage(task), false, entity.ErrScheduledTaskAlreadyExists, nil, entity.ErrScheduledTaskAlreadyExists, false},
+		{"schedule failure", entity.NewScheduleMessage(task), false, transient, nil, transient, false},
+		{"cancel", entity.NewCancelMessage(task.ID), true, nil, nil, nil, false},
+		{"cancel of removed task", entity.NewCancelMessage(task.ID), false, nil, nil, nil, false},
+		{"cancel failure", entity.NewCancelMessage(task.ID), true, nil, transient, transient, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduler := newFakeScheduler()
+			if tt.held {
+				if _, err := scheduler.Schedule(task); err != nil {
+					t.Fatal(err)
+				}
+			}
+			scheduler.scheduleErr, scheduler.cancelErr = tt.scheduleErr, tt.cancelErr
+			relay := &outboxRelay{scheduler: scheduler}
+			if err := relay.deliver(tt.message); err != tt.wantErr {
+				t.Fatalf("deliver() = %v, want %v", err, tt.wantErr)
+			}
+			if _, err := scheduler.Get(task.ID); (err == nil) != tt.wantHeld {
+				t.Errorf("held = %t, want %t", err == nil, tt.wantHeld)
+			}
+		})
+	}
+}
+
+func TestOutboxRelayRelay(t *testing.T) {
+	task := entity.NewScheduledTask("1-2-ROOM_PERIOD_FIN", vo.NewTaskVO(1, "", vo.RoomPeriodFinCode), "", rightNow)
+	transient := errors.New("unavailable")
+	tests := []struct {
+		name          string
+		attempts      uint // of previous deliveries
+		scheduleErr   error
+		wantDelivered bool
+		wantRetried   bool // postponed to a next attempt
+		wantFailed    bool // never retried
+	}{
+		{"delivered", 0, nil, true, false, false},
+		{"delivered on retry", 3, nil, true, false, false},
+		{"transient failure is retried", 0, transient, false, true, false},
+		{"conflict is retried", 0, entity.ErrScheduledTaskAlreadyExists, false, true, false},
+		{"gives up at the 10th attempt", 9, transient, false, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeOutboxRepository{}
+			scheduler := newFakeScheduler()
+			scheduler.scheduleErr = tt.scheduleErr
+			relay := &outboxRelay{outboxRepository: repo, scheduler: scheduler}
+			message := entity.NewScheduleMessage(task)
+			message.Attempts = tt.attempts
+			claimedAt := message.NextAttemptAt
+			relay.relay(message)
+
+			if len(repo.updated) != 1 {
+				t.Fatalf("message is updated %d times, want once", len(repo.updated))
+			}
+			updated := repo.updated[0]
+			if updated.Attempts != tt.attempts+1 {
+				t.Errorf("attempts = %d, want %d", updated.Attempts, tt.attempts+1)
+			}
+			if delivered := updated.DeliveredAt != nil; delivered != tt.wantDelivered {
+				t.Errorf("delivered = %t, want %t", delivered, tt.wantDelivered)
+			}
+			if retried := updated.NextAttemptAt.After(claimedAt) && updated.FailedAt == nil; retried != tt.wantRetried {
+				t.Errorf("retried = %t, want %t (next attempt at %s)", retried, tt.wantRetried, updated.NextAttemptAt)
+			}
+			if failed := updated.FailedAt != nil; failed != tt.wantFailed {
+				t.Errorf("failed = %t, want %t", failed, tt.wantFailed)
+			}
+			if tt.scheduleErr != nil && updated.LastError != tt.scheduleErr.Error() {
+				t.Errorf("LastError = %q, want %q", updated.LastError, tt.scheduleErr.Error())
+			}
+		})
+	}
+}
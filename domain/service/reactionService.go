@@ -0,0 +1,128 @@
+package service
+
+import (
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"github.com/ExchangeDiary/exchange-diary/domain/vo"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
+)
+
+// ReactionService ...
+type ReactionService interface {
+	Toggle(diary *entity.Diary, memberID uint, emoji entity.Emoji) (*entity.Reaction, error)
+	GetMine(diaryID, memberID uint) (*entity.Reaction, error)
+	PopulateReactions(diaries *entity.Diaries) (*entity.Diaries, error)
+	PopulateReaction(diary *entity.Diary) (*entity.Diary, error)
+}
+
+type reactionService struct {
+	roomService        RoomService
+	memberService      MemberService
+	alarmService       AlarmService
+	reactionRepository repository.ReactionRepository
+}
+
+// NewReactionService ...
+func NewReactionService(rr repository.ReactionRepository, rs RoomService, ms MemberService, as AlarmService) ReactionService {
+	return &reactionService{
+		reactionRepository: rr,
+		roomService:        rs,
+		memberService:      ms,
+		alarmService:       as,
+	}
+}
+
+// Toggle reacts on the diary with the emoji.
+// - 반응하지 않은 경우: 반응을 추가하고, 작성자에게 알림을 보낸다.
+// - 같은 emoji로 이미 반응한 경우: 반응을 취소한다. (nil을 반환)
+// - 다른 emoji로 이미 반응한 경우: emoji를 변경한다.
+func (rs *reactionService) Toggle(diary *entity.Diary, memberID uint, emoji entity.Emoji) (*entity.Reaction, error) {
+	reaction, err := rs.reactionRepository.GetByUnq(diary.ID, memberID)
+	switch err {
+	case nil:
+		if reaction.Emoji == emoji {
+			return nil, rs.reactionRepository.Delete(reaction)
+		}
+		reaction.Emoji = emoji
+		return rs.reactionRepository.Update(reaction)
+	case entity.ErrReactionNotFound:
+		createdReaction, err := rs.reactionRepository.Create(entity.NewReaction(diary.ID, memberID, emoji))
+		if err != nil {
+			return nil, err
+		}
+		// 알림 실패가 반응을 실패시키지는 않는다.
+		if err := rs.notifyAuthor(diary, memberID); err != nil {
+			logger.Error(err.Error())
+		}
+		return createdReaction, nil
+	default:
+		return nil, err
+	}
+}
+
+// GetMine returns member's reaction on the diary.
+func (rs *reactionService) GetMine(diaryID, memberID uint) (*entity.Reaction, error) {
+	return rs.reactionRepository.GetByUnq(diaryID, memberID)
+}
+
+// notifyAuthor sends MEMBER_REACTED_DIARY alarm to the diary author.
+// It is pushed only when the author turns on the alarm, but it is always listed in the alarm list.
+func (rs *reactionService) notifyAuthor(diary *entity.Diary, memberID uint) error {
+	if diary.AuthorID == memberID {
+		return nil
+	}
+	author, err := rs.memberService.Get(diary.AuthorID)
+	if err != nil {
+		return err
+	}
+	member, err := rs.memberService.Get(memberID)
+	if err != nil {
+		return err
+	}
+	room, err := rs.roomService.Get(diary.RoomID, entity.Ignore)
+	if err != nil {
+		return err
+	}
+
+	alarm, err := rs.alarmService.Create(author.ID, room.ID, vo.MemberReactedDiaryCode, room.Name, diary.Title, member.Name)
+	if err != nil {
+		return err
+	}
+	if !author.AlarmFlag {
+		return nil
+	}
+	return rs.alarmService.PushByID(author.ID, alarm)
+}
+
+// PopulateReactions counts reactions of every diary at once.
+func (rs *reactionService) PopulateReactions(diaries *entity.Diaries) (*entity.Diaries, error) {
+	diaryIDs := []uint{}
+	for _, diary := range *diaries {
+		diaryIDs = append(diaryIDs, diary.ID)
+	}
+	counts, err := rs.reactionRepository.CountAll(diaryIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range *diaries {
+		(*diaries)[i].Reactions = countsOrEmpty(counts[(*diaries)[i].ID])
+	}
+	return diaries, nil
+}
+
+// PopulateReaction ...
+func (rs *reactionService) PopulateReaction(diary *entity.Diary) (*entity.Diary, error) {
+	counts, err := rs.reactionRepository.CountAll([]uint{diary.ID})
+	if err != nil {
+		return nil, err
+	}
+	diary.Reactions = countsOrEmpty(counts[diary.ID])
+	return diary, nil
+}
+
+func countsOrEmpty(counts entity.ReactionCounts) entity.ReactionCounts {
+	if counts == nil {
+		return entity.ReactionCounts{}
+	}
+	return counts
+}
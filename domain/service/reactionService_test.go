@@ -0,0 +1,45 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+)
+
+func TestReactionServiceNotifyAuthor(t *testing.T) {
+	const author, mutedAuthor, reactor = 1, 2, 3
+	members := map[uint]entity.Member{
+		author:      {ID: author, AlarmFlag: true},
+		mutedAuthor: {ID: mutedAuthor, AlarmFlag: false},
+		reactor:     {ID: reactor, Name: "reactor", AlarmFlag: true},
+	}
+	tests := []struct {
+		name        string
+		author      uint
+		wantCreated bool
+		wantPushed  bool
+	}{
+		{"author", author, true, true},
+		{"muted author gets an alarm without push", mutedAuthor, true, false},
+		{"reaction on own diary", reactor, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alarmService := &fakeAlarmService{}
+			rs := &reactionService{
+				roomService:   &fakeRoomService{rooms: newFakeRoomRepository(entity.Room{ID: 10, Name: "room"})},
+				memberService: &fakeMemberService{members: members},
+				alarmService:  alarmService,
+			}
+			if err := rs.notifyAuthor(&entity.Diary{RoomID: 10, AuthorID: tt.author}, reactor); err != nil {
+				t.Fatal(err)
+			}
+			if created := len(alarmService.created) == 1 && alarmService.created[0].MemberID == tt.author; created != tt.wantCreated {
+				t.Errorf("alarms = %+v, want created for %d: %t", alarmService.created, tt.author, tt.wantCreated)
+			}
+			if _, pushed := alarmService.pushed[tt.author]; pushed != tt.wantPushed {
+				t.Errorf("pushed = %t, want %t", pushed, tt.wantPushed)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"github.com/ExchangeDiary/exchange-diary/domain/vo"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	reconcilePageSize      = 100
+	reconcileTimeTolerance = time.Second
+)
+
+// ReconcileService repairs room schedules.
+// It compares tasks which every room should have on current turn with tasks which scheduler holds,
+// recreates missing or stale ones, and deletes orphans of deleted rooms or members.
+type ReconcileService interface {
+	Reconcile(dryRun bool) (*vo.ReconcileReport, error)
+	Start(interval time.Duration, dryRun bool)
+	Stop()
+}
+
+type reconcileService struct {
+	unitOfWork              repository.UnitOfWork
+	roomRepository          repository.RoomRepository
+	memberRepository        repository.MemberRepository
+	taskExecutionRepository repository.TaskExecutionRepository
+	scheduler               Scheduler
+	callbackURL             string
+	stop                    chan struct{}
+	wg                      sync.WaitGroup
+}
+
+// NewReconcileService ...
+// callbackURL is a task callback url of recreated tasks, because reconciler runs without a request.
+func NewReconcileService(uow repository.UnitOfWork, rr repository.RoomRepository, mr repository.MemberRepository, ter repository.TaskExecutionRepository, sch Scheduler, callbackURL string) ReconcileService {
+	return &reconcileService{
+		unitOfWork:              uow,
+		roomRepository:          rr,
+		memberRepository:        mr,
+		taskExecutionRepository: ter,
+		scheduler:               sch,
+		callbackURL:             callbackURL,
+		stop:                    make(chan struct{}),
+	}
+}
+
+// Reconcile scans every room once. In dry-run mode, it only reports what would be repaired.
+// Repairs are written to outbox, so they are delivered in order with other task intents.
+func (rcs *reconcileService) Reconcile(dryRun bool) (*vo.ReconcileReport, error) {
+	report := &vo.ReconcileReport{DryRun: dryRun, StartedAt: time.Now(), Items: []vo.ReconcileItem{}}
+	messages := []*entity.OutboxMessage{}
+	rooms := map[uint]*entity.Room{}
+
+	// 1. missing or stale tasks of every room
+	var afterID uint
+	for {
+		page, err := rcs.roomRepository.GetPage(afterID, reconcilePageSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(*page) == 0 {
+			break
+		}
+		for i := range *page {
+			room := &(*page)[i]
+			afterID = room.ID
+			rooms[room.ID] = room
+			report.Rooms++
+
+			items, roomMessages, err := rcs.reconcileRoom(room, report.StartedAt)
+			if err != nil {
+				return nil, err
+			}
+			report.Items = append(report.Items, items...)
+			messages = append(messages, roomMessages...)
+		}
+	}
+
+	// 2. orphan tasks
+	items, orphanMessages, err := rcs.reconcileOrphans(rooms)
+	if err != nil {
+		return nil, err
+	}
+	report.Items = append(report.Items, items...)
+	messages = append(messages, orphanMessages...)
+
+	if !dryRun && len(messages) > 0 {
+		if err := rcs.unitOfWork.Do(func(tx repository.Transaction) error {
+			return tx.Outbox().Create(messages...)
+		}); err != nil {
+			return nil, err
+		}
+	}
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+func (rcs *reconcileService) reconcileRoom(room *entity.Room, now time.Time) ([]vo.ReconcileItem, []*entity.OutboxMessage, error) {
+	items := []vo.ReconcileItem{}
+	messages := []*entity.OutboxMessage{}
+	if room.DueAt == nil || room.IsArchived() {
+		return items, messages, nil
+	}
+
+	// reminders are not sent to unsigned member, but the turn should be passed.
+	turnMember, err := rcs.memberRepository.Get(room.TurnAccountID)
+	if err == entity.ErrMemberNotFound {
+		turnMember = nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	for _, expected := range turnTasks(rcs.callbackURL, room, turnMember) {
+		if expected.Task.Code == vo.MemberOnDutyCode && room.Turn <= 1 {
+			// the first turn starts when the room is created, so there is no on-duty alarm.
+			continue
+		}
+		if expected.Task.Code != vo.RoomPeriodFinCode && (room.DueAt.Before(now) || isPassed(expected, now)) {
+			// alarms are useless after the turn is ended, and reminders are not sent late.
+			continue
+		}
+
+		held, err := rcs.scheduler.Get(expected.ID)
+		switch err {
+		case nil:
+			if !isStaleTask(held, expected) {
+				continue
+			}
+			items = append(items, reconcileItem(expected, vo.ReconcileStale))
+			messages = append(messages, entity.NewCancelMessage(expected.ID), entity.NewScheduleMessage(expected))
+		case entity.ErrScheduledTaskNotFound:
+			executed, err := rcs.taskExecutionRepository.Exists(expected.ID, expected.Task.Fingerprint())
+			if err != nil {
+				return nil, nil, err
+			}
+			if executed {
+				continue
+			}
+			items = append(items, reconcileItem(expected, vo.ReconcileMissing))
+			messages = append(messages, entity.NewScheduleMessage(expected))
+		default:
+			return nil, nil, err
+		}
+	}
+	return items, messages, nil
+}
+
+// reconcileOrphans finds scheduled tasks whose room is deleted or archived, or whose member left the room (or unsigned),
+// and reminders of an offset which the room does not have anymore.
+func (rcs *reconcileService) reconcileOrphans(rooms map[uint]*entity.Room) ([]vo.ReconcileItem, []*entity.OutboxMessage, error) {
+	items := []vo.ReconcileItem{}
+	messages := []*entity.OutboxMessage{}
+
+	tasks, err := rcs.scheduler.List()
+	if err != nil {
+		return nil, nil, err
+	}
+	members := map[uint]bool{}
+	for i := range tasks {
+		task := &tasks[i]
+		roomID, accountID, code, ok := parseUniqueTaskID(task.ID)
+		if !ok {
+			continue
+		}
+
+		room, scanned := rooms[roomID]
+		if !scanned {
+			// the room may be created after scan
+			if room, err = rcs.roomRepository.GetByID(roomID); err != nil && err != entity.ErrRoomNotFound {
+				return nil, nil, err
+			}
+		}
+		orphan := room == nil || room.IsArchived() || !room.IsAlreadyJoined(accountID)
+		if offset, isReminder := code.ReminderOffset(); !orphan && isReminder {
+			orphan = !room.HasReminderOffset(offset)
+		}
+		if !orphan {
+			exists, checked := members[accountID]
+			if !checked {
+				_, err := rcs.memberRepository.Get(accountID)
+				if err != nil && err != entity.ErrMemberNotFound {
+					return nil, nil, err
+				}
+				exists = err == nil
+				members[accountID] = exists
+			}
+			orphan = !exists
+		}
+		if orphan {
+			items = append(items, reconcileItem(task, vo.ReconcileOrphan))
+			messages = append(messages, entity.NewCancelMessage(task.ID))
+		}
+	}
+	return items, messages, nil
+}
+
+// Start runs Reconcile periodically.
+func (rcs *reconcileService) Start(interval time.Duration, dryRun bool) {
+	rcs.wg.Add(1)
+	go func() {
+		defer rcs.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-rcs.stop:
+				return
+			case <-ticker.C:
+			}
+			report, err := rcs.Reconcile(dryRun)
+			if err != nil {
+				logger.Error(err.Error())
+				continue
+			}
+			logReconcileReport(report)
+		}
+	}()
+}
+
+// Stop ...
+func (rcs *reconcileService) Stop() {
+	close(rcs.stop)
+	rcs.wg.Wait()
+}
+
+func logReconcileReport(report *vo.ReconcileReport) {
+	logger.Info("reconcile finished",
+		zap.Bool("dryRun", report.DryRun),
+		zap.Uint("rooms", report.Rooms),
+		zap.Int("missing", report.Count(vo.ReconcileMissing)),
+		zap.Int("stale", report.Count(vo.ReconcileStale)),
+		zap.Int("orphan", report.Count(vo.ReconcileOrphan)),
+		zap.Duration("elapsed", report.FinishedAt.Sub(report.StartedAt)))
+	for _, item := range report.Items {
+		logger.Info("reconcile item",
+			zap.String("taskID", item.TaskID),
+			zap.String("reason", string(item.Reason)))
+	}
+}
+
+// isStaleTask reports whether held task is scheduled on another turn than expected.
+// Only ROOM_PERIOD_FIN task is compared by schedule time, because reminders can be rescheduled by admin.
+// Tasks which were scheduled before turn was recorded on task (Turn == 0) are left as they are.
+func isStaleTask(held, expected *entity.ScheduledTask) bool {
+	if held.Task.Turn == 0 {
+		return false
+	}
+	if held.Task.Turn != expected.Task.Turn {
+		return true
+	}
+	if held.Task.DueAt != nil && !isSameTime(*held.Task.DueAt, *expected.Task.DueAt) {
+		return true
+	}
+	return expected.Task.Code == vo.RoomPeriodFinCode && !isSameTime(held.ScheduledAt, expected.ScheduledAt)
+}
+
+func isSameTime(a, b time.Time) bool {
+	diff := a.Sub(b)
+	return -reconcileTimeTolerance <= diff && diff <= reconcileTimeTolerance
+}
+
+func isPassed(task *entity.ScheduledTask, now time.Time) bool {
+	return !task.ScheduledAt.IsZero() && task.ScheduledAt.Before(now)
+}
+
+func reconcileItem(task *entity.ScheduledTask, reason vo.ReconcileReason) vo.ReconcileItem {
+	item := vo.ReconcileItem{
+		TaskID: task.ID,
+		RoomID: task.Task.RoomID,
+		Code:   task.Task.Code,
+		Reason: reason,
+	}
+	if !task.ScheduledAt.IsZero() {
+		scheduledAt := task.ScheduledAt
+		item.ScheduledAt = &scheduledAt
+	}
+	return item
+}
+
+// parseUniqueTaskID is the inverse of genUniqueTaskID
+func parseUniqueTaskID(taskID string) (roomID, accountID uint, code vo.TaskCode, ok bool) {
+	if n, err := fmt.Sscanf(taskID, "%d-%d-%s", &roomID, &accountID, &code); err != nil || n != 3 {
+		return 0, 0, "", false
+	}
+	return roomID, accountID, code, true
+}
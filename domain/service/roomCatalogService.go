@@ -0,0 +1,34 @@
+package service
+
+import (
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+)
+
+// RoomCatalogService serves the room create form, and validates rooms against it.
+type RoomCatalogService interface {
+	Get() *entity.RoomCatalog
+	Validate(theme string, period uint8) error
+	GroupRooms(rooms entity.Rooms) []entity.RoomThemeGroup
+}
+
+type roomCatalogService struct {
+	catalog *entity.RoomCatalog
+}
+
+// NewRoomCatalogService ...
+// catalog is seeded from config, and is not changed while running.
+func NewRoomCatalogService(catalog *entity.RoomCatalog) RoomCatalogService {
+	return &roomCatalogService{catalog: catalog}
+}
+
+func (rcs *roomCatalogService) Get() *entity.RoomCatalog {
+	return rcs.catalog
+}
+
+func (rcs *roomCatalogService) Validate(theme string, period uint8) error {
+	return rcs.catalog.Validate(theme, period)
+}
+
+func (rcs *roomCatalogService) GroupRooms(rooms entity.Rooms) []entity.RoomThemeGroup {
+	return rcs.catalog.GroupRooms(rooms)
+}
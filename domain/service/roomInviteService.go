@@ -0,0 +1,116 @@
+package service
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"github.com/golang-jwt/jwt"
+)
+
+// RoomInviteService ...
+// The master issues invite links of a room. A link carries a signed token of the invite,
+// so that a member can join the room without knowing room id and code.
+type RoomInviteService interface {
+	Issue(roomID, masterID uint, ttl time.Duration, maxUses uint) (*entity.RoomInvite, error)
+	GetAll(roomID, masterID uint) ([]entity.RoomInvite, error)
+	Revoke(id, roomID, masterID uint) error
+	Join(inviteToken string, accountID uint) (*entity.Room, error)
+	Token(invite *entity.RoomInvite) (string, error)
+	DeepLink(inviteToken string) string
+}
+
+type roomInviteService struct {
+	roomInviteRepository repository.RoomInviteRepository
+	roomService          RoomService
+	inviteVerifier       TokenVerifier
+	deepLinkURL          string
+}
+
+// NewRoomInviteService ...
+// deepLinkURL is an app link which opens the join screen of mobile app. (ex. exchangediary://rooms/join)
+func NewRoomInviteService(rir repository.RoomInviteRepository, rs RoomService, inviteVerifier TokenVerifier, deepLinkURL string) RoomInviteService {
+	return &roomInviteService{
+		roomInviteRepository: rir,
+		roomService:          rs,
+		inviteVerifier:       inviteVerifier,
+		deepLinkURL:          deepLinkURL,
+	}
+}
+
+func (ris *roomInviteService) Issue(roomID, masterID uint, ttl time.Duration, maxUses uint) (*entity.RoomInvite, error) {
+	room, err := ris.getMasterRoom(roomID, masterID)
+	if err != nil {
+		return nil, err
+	}
+	if room.IsArchived() {
+		return nil, entity.ErrRoomArchived
+	}
+	invite, err := entity.NewRoomInvite(roomID, masterID, ttl, maxUses, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return ris.roomInviteRepository.Create(invite)
+}
+
+func (ris *roomInviteService) GetAll(roomID, masterID uint) ([]entity.RoomInvite, error) {
+	if _, err := ris.getMasterRoom(roomID, masterID); err != nil {
+		return nil, err
+	}
+	return ris.roomInviteRepository.GetAllByRoomID(roomID)
+}
+
+func (ris *roomInviteService) Revoke(id, roomID, masterID uint) error {
+	if _, err := ris.getMasterRoom(roomID, masterID); err != nil {
+		return err
+	}
+	invite, err := ris.roomInviteRepository.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if invite.RoomID != roomID {
+		return entity.ErrRoomInviteNotFound
+	}
+	if err := invite.Revoke(time.Now()); err != nil {
+		return err
+	}
+	return ris.roomInviteRepository.Revoke(invite)
+}
+
+// Join verifies the invite token, then joins the room with the same flow as room code.
+func (ris *roomInviteService) Join(inviteToken string, accountID uint) (*entity.Room, error) {
+	claims, err := ris.inviteVerifier.VerifyRoomInvite(inviteToken)
+	if err != nil {
+		return nil, err
+	}
+	invite, err := ris.roomInviteRepository.GetByID(claims.InviteID)
+	if err != nil {
+		return nil, err
+	}
+	if !invite.IsIssuedBy(claims) {
+		return nil, entity.ErrInvalidRoomInvite
+	}
+	return ris.roomService.JoinRoomByInvite(invite, accountID)
+}
+
+// Token signs the invite. Tokens are not stored, since the same invite is always signed to the same token.
+func (ris *roomInviteService) Token(invite *entity.RoomInvite) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, invite.Claims())
+	return token.SignedString([]byte(ris.inviteVerifier.SecretKey))
+}
+
+func (ris *roomInviteService) DeepLink(inviteToken string) string {
+	return ris.deepLinkURL + "?" + url.Values{"token": {inviteToken}}.Encode()
+}
+
+func (ris *roomInviteService) getMasterRoom(roomID, masterID uint) (*entity.Room, error) {
+	room, err := ris.roomService.Get(roomID, entity.Ignore)
+	if err != nil {
+		return nil, err
+	}
+	if !room.IsMaster(masterID) {
+		return nil, entity.ErrNotRoomMaster
+	}
+	return room, nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"github.com/golang-jwt/jwt"
+)
+
+type fakeRoomInviteRepository struct {
+	repository.RoomInviteRepository
+	invites map[uint]entity.RoomInvite
+}
+
+func (f *fakeRoomInviteRepository) GetByID(id uint) (*entity.RoomInvite, error) {
+	invite, ok := f.invites[id]
+	if !ok {
+		return nil, entity.ErrRoomInviteNotFound
+	}
+	return &invite, nil
+}
+
+func TestRoomInviteServiceJoin(t *testing.T) {
+	const secretKey = "secret"
+	invite, err := entity.NewRoomInvite(1, 2, entity.DefaultRoomInviteTTL, 3, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	invite.ID = 10
+	legacy := *invite
+	legacy.ID, legacy.Nonce = 11, ""
+
+	sign := func(claims entity.RoomInviteClaims, key string) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	forged := invite.Claims()
+	forged.Nonce = "0123456789abcdef0123456789abcdef"
+	otherRoom := invite.Claims()
+	otherRoom.RoomID = 5
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{"issued token", sign(invite.Claims(), secretKey), nil},
+		{"other key", sign(invite.Claims(), "ROOM_INVITE_SECRET_KEY"), entity.ErrInvalidRoomInvite},
+		{"guessed nonce", sign(forged, secretKey), entity.ErrInvalidRoomInvite},
+		{"other room", sign(otherRoom, secretKey), entity.ErrInvalidRoomInvite},
+		{"invite without nonce", sign(legacy.Claims(), secretKey), entity.ErrInvalidRoomInvite},
+		{"malformed", "not a token", entity.ErrInvalidRoomInvite},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ris := NewRoomInviteService(
+				&fakeRoomInviteRepository{invites: map[uint]entity.RoomInvite{invite.ID: *invite, legacy.ID: legacy}},
+				&fakeRoomService{},
+				NewTokenVerifier(secretKey),
+				"",
+			)
+			if _, err := ris.Join(tt.token, 3); err != tt.wantErr {
+				t.Errorf("Join() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ExchangeDiary/exchange-diary/domain/entity"
 	"github.com/ExchangeDiary/exchange-diary/domain/repository"
@@ -9,33 +10,49 @@ import (
 
 // RoomService ...
 type RoomService interface {
-	Create(masterID uint, name, code, hint, theme string, period uint8) (*entity.Room, error)
+	Create(masterID uint, name, code, hint, theme string, period uint8, endAt *time.Time) (*entity.Room, error)
 	Get(id uint, orderBy entity.RoomMemberOrderBy) (*entity.Room, error)
-	GetAllJoinedRooms(accountID uint) (*entity.Rooms, error)
+	GetAllJoinedRooms(accountID uint, option entity.RoomListOption) (*entity.Rooms, error)
+	GetAllJoinedRoomIDs(accountID uint) ([]uint, error)
+	PopulateUnreadCount(room *entity.Room, accountID uint) (*entity.Room, error)
 	Update(room *entity.Room) (*entity.Room, error)
 	Delete(room *entity.Room) error
-	JoinRoom(id, accountID uint, code string) (bool, error)
+	JoinRoom(id, accountID uint, code, clientIP string) (bool, error)
+	JoinRoomByInvite(invite *entity.RoomInvite, accountID uint) (*entity.Room, error)
 	LeaveRoom(id, accountID uint) error
+	SetAway(id, accountID uint, until *time.Time) (*entity.Room, error)
 }
 
 type roomService struct {
-	roomMemberService RoomMemberService
-	roomRepository    repository.RoomRepository
+	unitOfWork           repository.UnitOfWork
+	roomMemberService    RoomMemberService
+	roomRepository       repository.RoomRepository
+	readMarkerRepository repository.ReadMarkerRepository
+	joinAttemptService   JoinAttemptService
+	diaryRepository      repository.DiaryRepository
 }
 
 // NewRoomService ...
-func NewRoomService(rr repository.RoomRepository, rms RoomMemberService) RoomService {
+func NewRoomService(rr repository.RoomRepository, rms RoomMemberService, rmr repository.ReadMarkerRepository, uow repository.UnitOfWork, jas JoinAttemptService, dr repository.DiaryRepository) RoomService {
 	return &roomService{
-		roomRepository:    rr,
-		roomMemberService: rms,
+		roomRepository:       rr,
+		roomMemberService:    rms,
+		readMarkerRepository: rmr,
+		unitOfWork:           uow,
+		joinAttemptService:   jas,
+		diaryRepository:      dr,
 	}
 }
 
-func (rs *roomService) Create(masterID uint, name, code, hint, theme string, period uint8) (*entity.Room, error) {
+// Create creates a room. The room is archived when it reaches endAt. (nil for no end)
+func (rs *roomService) Create(masterID uint, name, code, hint, theme string, period uint8, endAt *time.Time) (*entity.Room, error) {
 	room, err := entity.NewRoom(masterID, name, code, hint, theme, period)
 	if err != nil {
 		return nil, err
 	}
+	if err := room.SetEndAt(endAt); err != nil {
+		return nil, err
+	}
 	createdRoom, err := rs.roomRepository.Create(room)
 	if err != nil {
 		return nil, err
@@ -64,8 +81,9 @@ func (rs *roomService) Get(id uint, orderBy entity.RoomMemberOrderBy) (room *ent
 	return populatedRoom, nil
 }
 
-// SELECT * FROM `rooms` WHERE id IN (memberRoomIDs) OR master_id = accountID ORDER BY  created_at desc;
-func (rs *roomService) GetAllJoinedRooms(accountID uint) (*entity.Rooms, error) {
+// SELECT * FROM `rooms` WHERE id IN (memberRoomIDs) OR master_id = accountID ORDER BY  updated_at desc;
+// Rooms are filtered by option.Theme, then ordered by option.Sort.
+func (rs *roomService) GetAllJoinedRooms(accountID uint, option entity.RoomListOption) (*entity.Rooms, error) {
 	// O(1)
 	memberRoomIDs, err := rs.roomMemberService.GetAllRoomIDs(accountID)
 	if err != nil {
@@ -76,14 +94,92 @@ func (rs *roomService) GetAllJoinedRooms(accountID uint) (*entity.Rooms, error)
 	if err != nil {
 		return nil, err
 	}
+	if option.Theme != "" {
+		filtered := entity.Rooms{}
+		for _, room := range *rooms {
+			if room.Theme == option.Theme {
+				filtered = append(filtered, room)
+			}
+		}
+		rooms = &filtered
+	}
 
 	populatedRooms, err := rs.roomMemberService.PopulateRoomsMembers(rooms)
 	if err != nil {
 		return nil, err
 	}
+	if populatedRooms, err = rs.populateUnreadCounts(populatedRooms, accountID); err != nil {
+		return nil, err
+	}
+	if populatedRooms, err = rs.populateLastDiaryAts(populatedRooms); err != nil {
+		return nil, err
+	}
+	populatedRooms.Sort(option.Sort)
 	return populatedRooms, nil
 }
 
+// GetAllJoinedRoomIDs returns ids of rooms which account is a member or master of.
+func (rs *roomService) GetAllJoinedRoomIDs(accountID uint) ([]uint, error) {
+	memberRoomIDs, err := rs.roomMemberService.GetAllRoomIDs(accountID)
+	if err != nil {
+		return nil, err
+	}
+	// master rooms are not stored in roomMember
+	rooms, err := rs.roomRepository.GetAll(accountID, memberRoomIDs)
+	if err != nil {
+		return nil, err
+	}
+	roomIDs := []uint{}
+	for _, room := range *rooms {
+		roomIDs = append(roomIDs, room.ID)
+	}
+	return roomIDs, nil
+}
+
+// PopulateUnreadCount sets the number of diaries which account has not read yet.
+func (rs *roomService) PopulateUnreadCount(room *entity.Room, accountID uint) (*entity.Room, error) {
+	counts, err := rs.readMarkerRepository.UnreadCounts(accountID, []uint{room.ID})
+	if err != nil {
+		return nil, err
+	}
+	room.UnreadCount = counts[room.ID]
+	return room, nil
+}
+
+// populateUnreadCounts counts unread diaries of every room at once. O(1)
+func (rs *roomService) populateUnreadCounts(rooms *entity.Rooms, accountID uint) (*entity.Rooms, error) {
+	roomIDs := []uint{}
+	for _, room := range *rooms {
+		roomIDs = append(roomIDs, room.ID)
+	}
+	counts, err := rs.readMarkerRepository.UnreadCounts(accountID, roomIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range *rooms {
+		(*rooms)[i].UnreadCount = counts[(*rooms)[i].ID]
+	}
+	return rooms, nil
+}
+
+// populateLastDiaryAts sets created time of the latest diary of every room at once. O(1)
+func (rs *roomService) populateLastDiaryAts(rooms *entity.Rooms) (*entity.Rooms, error) {
+	roomIDs := []uint{}
+	for _, room := range *rooms {
+		roomIDs = append(roomIDs, room.ID)
+	}
+	lastCreatedAts, err := rs.diaryRepository.LastCreatedAts(roomIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range *rooms {
+		if lastCreatedAt, ok := lastCreatedAts[(*rooms)[i].ID]; ok {
+			(*rooms)[i].LastDiaryAt = &lastCreatedAt
+		}
+	}
+	return rooms, nil
+}
+
 func (rs *roomService) Update(room *entity.Room) (*entity.Room, error) {
 	room, err := rs.roomRepository.Update(room)
 	if err != nil {
@@ -101,38 +197,88 @@ func (rs *roomService) Delete(room *entity.Room) error {
 	return nil
 }
 
-func (rs *roomService) JoinRoom(id, accountID uint, code string) (bool, error) {
+// JoinRoom adds the account to the room if code is correct.
+// Wrong codes are counted per (room, member) and per clientIP, and entity.ErrRoomJoinLocked is returned while locked out.
+func (rs *roomService) JoinRoom(id, accountID uint, code, clientIP string) (bool, error) {
 	// get a room
 	room, err := rs.Get(id, entity.JoinedOrder)
 	if err != nil {
 		return false, err
 	}
 	if room.IsAlreadyJoined(accountID) {
-		return false, fmt.Errorf("Already joined room")
+		return false, entity.ErrAlreadyJoinedRoom
+	}
+	if room.IsArchived() {
+		return false, entity.ErrRoomArchived
 	}
 	// validate code
-	if room.Code != code {
-		return false, fmt.Errorf("Invalid code is given")
+	verified, err := rs.joinAttemptService.Attempt(room, accountID, clientIP, func() bool {
+		return room.VerifyCode(code)
+	})
+	if err != nil {
+		return false, err
 	}
-
-	// check room is full
-	if room.IsMemberFull() {
-		return false, fmt.Errorf("The total number of member in a room cannot exceed 10, this count includes master(1)")
+	if !verified {
+		return false, entity.ErrInvalidRoomCode
 	}
-
-	// ===TODO: tx start===
-	// 	1. add roomMember
-	if _, err := rs.roomMemberService.Add(id, accountID); err != nil {
+	if _, err := rs.join(room.ID, accountID); err != nil {
 		return false, err
 	}
-	// 	2. append room.Orders
-	room.AppendMember(accountID)
-	if _, err := rs.roomRepository.Update(room); err != nil {
-		return false, err
+	return true, nil
+}
+
+// JoinRoomByInvite adds the account to the room of a valid invite, and counts the invite use in the same transaction.
+func (rs *roomService) JoinRoomByInvite(invite *entity.RoomInvite, accountID uint) (*entity.Room, error) {
+	if err := invite.Validate(time.Now()); err != nil {
+		return nil, err
 	}
-	// ===TODO: tx end===
+	return rs.join(invite.RoomID, accountID, func(tx repository.Transaction) error {
+		return tx.RoomInvites().Use(invite.ID)
+	})
+}
 
-	return true, nil
+// join adds the account as a room member. within runs in the same transaction.
+// The room is locked, so that members who join at the same time are not lost.
+func (rs *roomService) join(id, accountID uint, within ...func(tx repository.Transaction) error) (*entity.Room, error) {
+	var room *entity.Room
+	if err := rs.unitOfWork.Do(func(tx repository.Transaction) (err error) {
+		if room, err = tx.Rooms().GetByIDForUpdate(id); err != nil {
+			return err
+		}
+		if room.IsAlreadyJoined(accountID) {
+			return entity.ErrAlreadyJoinedRoom
+		}
+		if room.IsArchived() {
+			return entity.ErrRoomArchived
+		}
+		// check room is full
+		if room.IsMemberFull() {
+			return entity.ErrRoomMemberFull
+		}
+
+		roomMember, err := entity.NewRoomMember(room.ID, accountID)
+		if err != nil {
+			return err
+		}
+		// 	1. add roomMember
+		if _, err := tx.RoomMembers().Create(roomMember); err != nil {
+			return err
+		}
+		// 	2. append room.Orders
+		room.AppendMember(accountID)
+		if _, err := tx.Rooms().Update(room); err != nil {
+			return err
+		}
+		for _, fn := range within {
+			if err := fn(tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return room, nil
 }
 
 func (rs *roomService) LeaveRoom(id, accountID uint) error {
@@ -168,14 +314,15 @@ func (rs *roomService) doMasterLeaveProcess(room *entity.Room, accountID uint) e
 		return err
 	}
 
-	// 새로운 마스터를 멤버에서 제외
-	if err := rs.roomMemberService.Delete(room.ID, room.MasterID); err != nil {
+	return rs.unitOfWork.Do(func(tx repository.Transaction) error {
+		// 새로운 마스터를 멤버에서 제외
+		if err := deleteRoomMember(tx, room.ID, room.MasterID); err != nil {
+			return err
+		}
+		// Update room
+		_, err := tx.Rooms().Update(room)
 		return err
-	}
-
-	// Update room
-	_, err := rs.roomRepository.Update(room)
-	return err
+	})
 }
 
 func (rs *roomService) doMemberLeaveProcess(room *entity.Room, accountID uint) error {
@@ -183,14 +330,42 @@ func (rs *roomService) doMemberLeaveProcess(room *entity.Room, accountID uint) e
 	if _, err := room.RemoveMember(accountID); err != nil {
 		return err
 	}
-	// Update room
-	if _, err := rs.roomRepository.Update(room); err != nil {
-		return err
+	return rs.unitOfWork.Do(func(tx repository.Transaction) error {
+		// Update room
+		if _, err := tx.Rooms().Update(room); err != nil {
+			return err
+		}
+		// roomMember에서 row 제거
+		return deleteRoomMember(tx, room.ID, accountID)
+	})
+}
+
+// SetAway sets the member away from the room until the time, so that the member is skipped on turn rotation.
+// nil until comes back right away.
+func (rs *roomService) SetAway(id, accountID uint, until *time.Time) (*entity.Room, error) {
+	var room *entity.Room
+	// the room is locked, so that concurrent changes of other members' away are not lost.
+	if err := rs.unitOfWork.Do(func(tx repository.Transaction) (err error) {
+		if room, err = tx.Rooms().GetByIDForUpdate(id); err != nil {
+			return err
+		}
+		if !room.IsAlreadyJoined(accountID) {
+			return entity.ErrNotJoinedRoom
+		}
+		if err := room.SetAway(accountID, until, time.Now()); err != nil {
+			return err
+		}
+		return tx.Rooms().UpdateAwayUntil(room)
+	}); err != nil {
+		return nil, err
 	}
+	return room, nil
+}
 
-	// roomMember에서 row 제거
-	if err := rs.roomMemberService.Delete(room.ID, accountID); err != nil {
+func deleteRoomMember(tx repository.Transaction, roomID, accountID uint) error {
+	roomMember, err := tx.RoomMembers().GetByUnq(roomID, accountID)
+	if err != nil {
 		return err
 	}
-	return nil
+	return tx.RoomMembers().Delete(roomMember)
 }
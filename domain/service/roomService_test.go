@@ -0,0 +1,54 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+)
+
+func TestRoomServiceSetAway(t *testing.T) {
+	const roomID, master, member, stranger = 1, 2, 3, 4
+	now := time.Now()
+	tomorrow := now.Add(time.Hour * 24).Truncate(time.Second)
+	nextWeek := now.Add(time.Hour * 24 * 7).Truncate(time.Second)
+	yesterday := now.Add(-time.Hour * 24)
+
+	tests := []struct {
+		name      string
+		accountID uint
+		until     *time.Time
+		wantErr   error
+		wantAway  map[uint]time.Time
+	}{
+		{"set away", member, &tomorrow, nil, map[uint]time.Time{master: nextWeek, member: tomorrow}},
+		{"come back", master, nil, nil, map[uint]time.Time{}},
+		{"past", member, &yesterday, entity.ErrInvalidAwayUntil, map[uint]time.Time{master: nextWeek}},
+		{"not joined", stranger, &tomorrow, entity.ErrNotJoinedRoom, map[uint]time.Time{master: nextWeek}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow := newFakeUnitOfWork()
+			uow.tx.rooms.rooms[roomID] = entity.Room{
+				ID:        roomID,
+				Name:      "room",
+				MasterID:  master,
+				Orders:    []uint{master, member},
+				AwayUntil: map[uint]time.Time{master: nextWeek},
+			}
+			rs := NewRoomService(nil, nil, nil, uow, nil, nil)
+
+			if _, err := rs.SetAway(roomID, tt.accountID, tt.until); err != tt.wantErr {
+				t.Fatalf("SetAway() = %v, want %v", err, tt.wantErr)
+			}
+			saved := uow.tx.rooms.rooms[roomID]
+			if !reflect.DeepEqual(saved.AwayUntil, tt.wantAway) {
+				t.Errorf("away = %v, want %v", saved.AwayUntil, tt.wantAway)
+			}
+			if saved.Name != "room" {
+				t.Errorf("other columns are changed: %+v", saved)
+			}
+		})
+	}
+}
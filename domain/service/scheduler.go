@@ -0,0 +1,150 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	localPollInterval = time.Second
+	localTaskLease    = time.Minute * 5
+	localMaxAttempts  = 5
+	localRetryBackoff = time.Second * 10
+)
+
+// Scheduler schedules callback tasks. (google cloud tasks or local db table)
+type Scheduler interface {
+	// Schedule registers a task instance, and replaces the previous instance of the same task id.
+	// Registering the same instance again is not an error.
+	Schedule(task *entity.ScheduledTask) (*entity.ScheduledTask, error)
+	// Cancel and Get find the latest instance by task id.
+	Cancel(id string) error
+	Get(id string) (*entity.ScheduledTask, error)
+	List() ([]entity.ScheduledTask, error)
+}
+
+// TaskHandler runs a scheduled task. It is the same handler which tasks callback api uses.
+type TaskHandler func(task *entity.ScheduledTask) error
+
+// LocalScheduler is a Scheduler backed by scheduled_tasks table.
+// Due tasks are polled and run by in-process worker pool, so it works without google cloud tasks.
+type LocalScheduler interface {
+	Scheduler
+	Start(handler TaskHandler)
+	Stop()
+}
+
+type localScheduler struct {
+	scheduledTaskRepository repository.ScheduledTaskRepository
+	workers                 int
+	jobs                    chan entity.ScheduledTask
+	stop                    chan struct{}
+	wg                      sync.WaitGroup
+}
+
+// NewLocalScheduler ...
+func NewLocalScheduler(str repository.ScheduledTaskRepository, workers int) LocalScheduler {
+	if workers < 1 {
+		workers = 1
+	}
+	return &localScheduler{
+		scheduledTaskRepository: str,
+		workers:                 workers,
+		jobs:                    make(chan entity.ScheduledTask),
+		stop:                    make(chan struct{}),
+	}
+}
+
+// Schedule registers a task. If the task already exists, it is replaced.
+func (ls *localScheduler) Schedule(task *entity.ScheduledTask) (*entity.ScheduledTask, error) {
+	if task.ScheduledAt.IsZero() {
+		task.ScheduledAt = time.Now()
+	}
+	return ls.scheduledTaskRepository.Save(task)
+}
+
+func (ls *localScheduler) Cancel(id string) error {
+	return ls.scheduledTaskRepository.Delete(id)
+}
+
+func (ls *localScheduler) Get(id string) (*entity.ScheduledTask, error) {
+	return ls.scheduledTaskRepository.GetByID(id)
+}
+
+func (ls *localScheduler) List() ([]entity.ScheduledTask, error) {
+	return ls.scheduledTaskRepository.GetAll()
+}
+
+// Start runs poller and worker goroutines.
+func (ls *localScheduler) Start(handler TaskHandler) {
+	for i := 0; i < ls.workers; i++ {
+		ls.wg.Add(1)
+		go ls.work(handler)
+	}
+	ls.wg.Add(1)
+	go ls.poll()
+}
+
+// Stop waits until running tasks are finished.
+func (ls *localScheduler) Stop() {
+	close(ls.stop)
+	ls.wg.Wait()
+}
+
+func (ls *localScheduler) poll() {
+	defer ls.wg.Done()
+	defer close(ls.jobs)
+
+	ticker := time.NewTicker(localPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ls.stop:
+			return
+		case <-ticker.C:
+		}
+
+		tasks, err := ls.scheduledTaskRepository.Claim(time.Now(), localTaskLease, ls.workers)
+		if err != nil {
+			logger.Error(err.Error())
+			continue
+		}
+		for _, task := range tasks {
+			select {
+			case <-ls.stop:
+				// unfinished claims are retried after the lease is expired
+				return
+			case ls.jobs <- task:
+			}
+		}
+	}
+}
+
+func (ls *localScheduler) work(handler TaskHandler) {
+	defer ls.wg.Done()
+	for task := range ls.jobs {
+		err := handler(&task)
+		if err != nil {
+			logger.Error("local scheduled task failed", zap.String("taskID", task.ID), zap.Uint("attempts", task.Attempts+1), zap.Error(err))
+		}
+		if isRetryable(err) && task.Attempts+1 < localMaxAttempts {
+			// like cloud tasks, transient error is retried with exponential backoff
+			err = ls.scheduledTaskRepository.Retry(&task, time.Now().Add(localRetryBackoff<<task.Attempts))
+		} else {
+			err = ls.scheduledTaskRepository.Release(&task)
+		}
+		if err != nil {
+			logger.Error(err.Error())
+		}
+	}
+}
+
+// isRetryable reports whether a task should be retried. It matches callback api response status.
+func isRetryable(err error) bool {
+	return err != nil && err != entity.ErrTaskAlreadyExecuted && !entity.IsPermanent(err)
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"github.com/ExchangeDiary/exchange-diary/domain/vo"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
+)
+
+const swapRequestTTL = time.Hour * 24
+
+// SwapRequestService ...
+// A member proposes to trade places in orders of current cycle with another member.
+// Accepted swap is applied from the next turn until the cycle ends, then the room follows room.Orders again.
+// The member on duty and members who already wrote in current cycle can neither request nor accept a swap.
+// Unanswered request is expired by SWAP_REQUEST_EXPIRE task after swapRequestTTL.
+type SwapRequestService interface {
+	Request(roomID, requesterID, responderID uint) (*entity.SwapRequest, error)
+	Respond(id, roomID, responderID uint, accept bool) (*entity.SwapRequest, error)
+	Expire(id uint) error
+	GetAllPending(roomID, memberID uint) ([]entity.SwapRequest, error)
+}
+
+type swapRequestService struct {
+	unitOfWork            repository.UnitOfWork
+	swapRequestRepository repository.SwapRequestRepository
+	roomService           RoomService
+	memberService         MemberService
+	alarmService          AlarmService
+	callbackURL           string
+}
+
+// NewSwapRequestService ...
+// callbackURL is a task callback url of swap request expiry.
+func NewSwapRequestService(uow repository.UnitOfWork, srr repository.SwapRequestRepository, rs RoomService, ms MemberService, as AlarmService, callbackURL string) SwapRequestService {
+	return &swapRequestService{
+		unitOfWork:            uow,
+		swapRequestRepository: srr,
+		roomService:           rs,
+		memberService:         ms,
+		alarmService:          as,
+		callbackURL:           callbackURL,
+	}
+}
+
+// Request creates a pending swap request and its expiry task, then alarms the responder.
+func (ss *swapRequestService) Request(roomID, requesterID, responderID uint) (*entity.SwapRequest, error) {
+	room, err := ss.roomService.Get(roomID, entity.Ignore)
+	if err != nil {
+		return nil, err
+	}
+	if !room.IsAlreadyJoined(requesterID) {
+		return nil, entity.ErrNotJoinedRoom
+	}
+	if room.IsArchived() {
+		return nil, entity.ErrRoomArchived
+	}
+	if requesterID == responderID || !room.IsAlreadyJoined(responderID) {
+		return nil, entity.ErrInvalidSwapRequest
+	}
+	if room.IsTurn(requesterID) || room.IsTurn(responderID) {
+		return nil, entity.ErrSwapOnDuty
+	}
+	if !room.IsUpcoming(requesterID) || !room.IsUpcoming(responderID) {
+		return nil, entity.ErrSwapPassed
+	}
+	if _, err := ss.swapRequestRepository.GetPending(roomID, requesterID, responderID); err == nil {
+		return nil, entity.ErrSwapRequestAlreadyExists
+	} else if err != entity.ErrSwapRequestNotFound {
+		return nil, err
+	}
+	requester, err := ss.memberService.Get(requesterID)
+	if err != nil {
+		return nil, err
+	}
+
+	var request *entity.SwapRequest
+	if err := ss.unitOfWork.Do(func(tx repository.Transaction) (err error) {
+		if request, err = tx.SwapRequests().Create(entity.NewSwapRequest(roomID, requesterID, responderID, time.Now().Add(swapRequestTTL))); err != nil {
+			return
+		}
+		return tx.Outbox().Create(entity.NewScheduleMessage(ss.expireTask(request, requester)))
+	}); err != nil {
+		return nil, err
+	}
+
+	ss.notify(responderID, room, vo.SwapRequestedCode, requester)
+	return request, nil
+}
+
+// Respond accepts or declines the request. Accepted request swaps both members in orders of current cycle.
+// The room is read again with a lock in the transaction, so that a turn rotation in the meantime is not overwritten.
+func (ss *swapRequestService) Respond(id, roomID, responderID uint, accept bool) (*entity.SwapRequest, error) {
+	request, err := ss.swapRequestRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if request.RoomID != roomID {
+		return nil, entity.ErrSwapRequestNotFound
+	}
+	if request.ResponderID != responderID {
+		return nil, entity.ErrInvalidSwapRequest
+	}
+	if err := request.Respond(accept, time.Now()); err != nil {
+		return nil, err
+	}
+	responder, err := ss.memberService.Get(responderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var room *entity.Room
+	if err := ss.unitOfWork.Do(func(tx repository.Transaction) (err error) {
+		if room, err = tx.Rooms().GetByIDForUpdate(roomID); err != nil {
+			return
+		}
+		if accept {
+			if room.IsArchived() {
+				return entity.ErrRoomArchived
+			}
+			if err = room.SwapOrders(request.RequesterID, request.ResponderID); err != nil {
+				return
+			}
+			if _, err = tx.Rooms().Update(room); err != nil {
+				return
+			}
+		}
+		if request, err = tx.SwapRequests().Update(request); err != nil {
+			return
+		}
+		return tx.Outbox().Create(entity.NewCancelMessage(genSwapRequestTaskID(request.ID)))
+	}); err != nil {
+		return nil, err
+	}
+
+	code := vo.TaskCode(vo.SwapDeclinedCode)
+	if accept {
+		code = vo.SwapAcceptedCode
+	}
+	ss.notify(request.RequesterID, room, code, responder)
+	return request, nil
+}
+
+// Expire expires the request if it is still unanswered, then alarms the requester.
+// It is called by SWAP_REQUEST_EXPIRE task. Already answered request is ignored.
+func (ss *swapRequestService) Expire(id uint) error {
+	request, err := ss.swapRequestRepository.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := request.Expire(); err != nil {
+		return nil
+	}
+	if _, err := ss.swapRequestRepository.Update(request); err != nil {
+		if err == entity.ErrSwapRequestNotPending {
+			return nil
+		}
+		return err
+	}
+
+	room, err := ss.roomService.Get(request.RoomID, entity.Ignore)
+	if err != nil {
+		return err
+	}
+	responder, err := ss.memberService.Get(request.ResponderID)
+	if err != nil {
+		return err
+	}
+	ss.notify(request.RequesterID, room, vo.SwapExpiredCode, responder)
+	return nil
+}
+
+func (ss *swapRequestService) GetAllPending(roomID, memberID uint) ([]entity.SwapRequest, error) {
+	return ss.swapRequestRepository.GetAllPending(roomID, memberID)
+}
+
+// notify sends the swap alarm to receiver. Failure of alarm does not fail the request.
+func (ss *swapRequestService) notify(receiverID uint, room *entity.Room, code vo.TaskCode, author *entity.Member) {
+	alarm, err := ss.alarmService.Create(receiverID, room.ID, code, room.Name, "", author.Name)
+	if err != nil {
+		logger.Error(err.Error())
+		return
+	}
+	if err := ss.alarmService.PushByID(receiverID, alarm); err != nil {
+		logger.Error(err.Error())
+	}
+}
+
+func (ss *swapRequestService) expireTask(request *entity.SwapRequest, requester *entity.Member) *entity.ScheduledTask {
+	task := vo.NewTaskVO(request.RoomID, requester.Email, vo.SwapRequestExpireCode)
+	task.SwapRequestID = request.ID
+	return entity.NewScheduledTask(genSwapRequestTaskID(request.ID), task, ss.callbackURL, request.ExpiresAt)
+}
+
+func genSwapRequestTaskID(id uint) string {
+	return fmt.Sprintf("swap-%d", id)
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+)
+
+func TestSwapRequestServiceRespond(t *testing.T) {
+	const roomID, requestID, first, requester, responder = 1, 1, 2, 3, 4
+	archivedAt := time.Now()
+
+	tests := []struct {
+		name        string
+		room        entity.Room // room when the request is answered
+		responderID uint
+		accept      bool
+		wantErr     error
+		wantStatus  entity.SwapRequestStatus
+		wantOrders  []uint // of current cycle
+	}{
+		{"accept", entity.Room{Orders: []uint{first, requester, responder}, TurnAccountID: first, Turn: 1},
+			responder, true, nil, entity.SwapAccepted, []uint{first, responder, requester}},
+		// the turn is passed after the request is made. the rotation must not be overwritten.
+		{"accept after rotation", entity.Room{Orders: []uint{first, requester, responder}, TurnAccountID: first, Turn: 4},
+			responder, true, nil, entity.SwapAccepted, []uint{first, responder, requester}},
+		{"requester already wrote", entity.Room{Orders: []uint{requester, first, responder}, TurnAccountID: first, Turn: 2},
+			responder, true, entity.ErrSwapPassed, entity.SwapPending, []uint{requester, first, responder}},
+		{"decline", entity.Room{Orders: []uint{first, requester, responder}, TurnAccountID: first, Turn: 1},
+			responder, false, nil, entity.SwapDeclined, []uint{first, requester, responder}},
+		{"requester is on duty", entity.Room{Orders: []uint{first, requester, responder}, TurnAccountID: requester, Turn: 2},
+			responder, true, entity.ErrSwapOnDuty, entity.SwapPending, []uint{first, requester, responder}},
+		{"responder is on duty", entity.Room{Orders: []uint{first, requester, responder}, TurnAccountID: responder, Turn: 3},
+			responder, true, entity.ErrSwapOnDuty, entity.SwapPending, []uint{first, requester, responder}},
+		{"decline while on duty", entity.Room{Orders: []uint{first, requester, responder}, TurnAccountID: responder, Turn: 3},
+			responder, false, nil, entity.SwapDeclined, []uint{first, requester, responder}},
+		{"archived", entity.Room{Orders: []uint{first, requester, responder}, TurnAccountID: first, ArchivedAt: &archivedAt},
+			responder, true, entity.ErrRoomArchived, entity.SwapPending, []uint{first, requester, responder}},
+		{"not responder", entity.Room{Orders: []uint{first, requester, responder}, TurnAccountID: first},
+			first, true, entity.ErrInvalidSwapRequest, entity.SwapPending, []uint{first, requester, responder}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow := newFakeUnitOfWork()
+			tt.room.ID = roomID
+			uow.tx.rooms.rooms[roomID] = tt.room
+			uow.tx.swaps.requests[requestID] = entity.SwapRequest{
+				ID: requestID, RoomID: roomID, RequesterID: requester, ResponderID: responder,
+				Status: entity.SwapPending, ExpiresAt: time.Now().Add(swapRequestTTL),
+			}
+			members := map[uint]entity.Member{}
+			for _, id := range []uint{first, requester, responder} {
+				members[id] = entity.Member{ID: id}
+			}
+			ss := NewSwapRequestService(uow, uow.tx.swaps, nil, &fakeMemberService{members: members}, &fakeAlarmService{}, "")
+
+			if _, err := ss.Respond(requestID, roomID, tt.responderID, tt.accept); err != tt.wantErr {
+				t.Fatalf("Respond() = %v, want %v", err, tt.wantErr)
+			}
+			if got := uow.tx.swaps.requests[requestID].Status; got != tt.wantStatus {
+				t.Errorf("status = %s, want %s", got, tt.wantStatus)
+			}
+			saved := uow.tx.rooms.rooms[roomID]
+			if !reflect.DeepEqual(saved.CycleOrders(), tt.wantOrders) {
+				t.Errorf("orders of current cycle = %v, want %v", saved.CycleOrders(), tt.wantOrders)
+			}
+			// an accepted swap lasts only for current cycle
+			if !reflect.DeepEqual(saved.Orders, tt.room.Orders) {
+				t.Errorf("orders = %v, want %v", saved.Orders, tt.room.Orders)
+			}
+			if saved.Turn != tt.room.Turn || saved.TurnAccountID != tt.room.TurnAccountID {
+				t.Errorf("turn = %d (%d), want %d (%d)", saved.Turn, saved.TurnAccountID, tt.room.Turn, tt.room.TurnAccountID)
+			}
+		})
+	}
+}
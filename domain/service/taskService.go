@@ -1,26 +1,20 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
 	"github.com/ExchangeDiary/exchange-diary/domain/vo"
-	"github.com/ExchangeDiary/exchange-diary/infrastructure/clients/google/tasks"
-
-	taskspb "google.golang.org/genproto/googleapis/cloud/tasks/v2"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
 )
 
-const (
-	oneHour  = time.Hour * 1
-	fourHour = time.Hour * 4
-)
+const taskExecutionLease = time.Minute * 5
 
 var rightNow = time.Time{}
 
-// TODO: delete
-var mockDiaryTitle = "MOCK 일기장 이름"
-
 // TaskService ...
 type TaskService interface {
 	DoRoomPeriodFINTask(roomID uint, baseURL string) error
@@ -28,106 +22,217 @@ type TaskService interface {
 	DoMemberBeforeTask(roomID uint, email string, code vo.TaskCode) (err error)
 	DoMemberPostedDiaryTask(roomID uint, baseURL string) error
 
-	RegisterRoomPeriodFINTask(c *tasks.Client, baseURL string, roomID, accountID uint, dueAt *time.Time) (taskID string, err error)
+	RegisterRoomPeriodFINTask(baseURL string, room *entity.Room) (taskID string, err error)
 	RegisterMemberPostedDiaryTask(roomID uint, baseURL string) (taskID string, err error)
+	UpdateReminderOffsets(baseURL string, roomID uint, patch func(room *entity.Room) (*entity.Room, error)) (*entity.Room, error)
+	SkipTurn(roomID, accountID uint, baseURL string) error
+	RegisterRoomEndTask(baseURL string, room *entity.Room) (taskID string, err error)
+	ArchiveRoom(roomID uint) (*entity.Room, error)
+	UnarchiveRoom(roomID uint, baseURL string) (*entity.Room, error)
+
+	GetTask(code vo.TaskCode, roomID, turnAccountID uint) (*entity.ScheduledTask, error)
+	DeleteTask(code vo.TaskCode, roomID, turnAccountID uint) error
 
-	GetTask(c *tasks.Client, code vo.TaskCode, roomID, turnAccountID uint) (*taskspb.Task, error)
-	DeleteTask(c *tasks.Client, code vo.TaskCode, roomID, turnAccountID uint) error
+	Handle(task *entity.ScheduledTask) error
 }
 
 type taskService struct {
-	alarmService  AlarmService
-	roomService   RoomService
-	memberService MemberService
+	unitOfWork              repository.UnitOfWork
+	taskExecutionRepository repository.TaskExecutionRepository
+	scheduler               Scheduler
+	alarmService            AlarmService
+	roomService             RoomService
+	memberService           MemberService
+	diaryService            DiaryService
+	swapRequestService      SwapRequestService
 }
 
 // NewTaskService ...
-func NewTaskService(as AlarmService, rs RoomService, ms MemberService) TaskService {
+func NewTaskService(uow repository.UnitOfWork, ter repository.TaskExecutionRepository, sch Scheduler, as AlarmService, rs RoomService, ms MemberService, ds DiaryService, srs SwapRequestService) TaskService {
 	return &taskService{
-		alarmService:  as,
-		roomService:   rs,
-		memberService: ms,
+		unitOfWork:              uow,
+		taskExecutionRepository: ter,
+		scheduler:               sch,
+		alarmService:            as,
+		roomService:             rs,
+		memberService:           ms,
+		diaryService:            ds,
+		swapRequestService:      srs,
 	}
 }
 
 func (ts *taskService) DoRoomPeriodFINTask(roomID uint, baseURL string) error {
-	// 1. room update
-	// 만약 방이 사라졌다면 error fin
-	room, err := ts.roomService.Get(roomID, entity.Ignore)
-	if err != nil {
-		return err
-	}
+	return ts.unitOfWork.Do(func(tx repository.Transaction) error {
+		// 만약 방이 사라졌다면 error fin
+		room, err := tx.Rooms().GetByIDForUpdate(roomID)
+		if err != nil {
+			return err
+		}
+		return ts.rotateTurn(tx, room, baseURL)
+	})
+}
 
-	dueAt := room.NextDueAt()
-	nxtTurnAccountID := room.NextTurn()
-	if _, err := ts.roomService.Update(room); err != nil {
-		return err
+// rotateTurn passes the turn of the room to next member.
+// The room should be locked by tx, so that concurrent changes of the room are not overwritten.
+// Room update and every task intent (preceding ones first) are written by tx,
+// then outbox relay registers the tasks to scheduler.
+func (ts *taskService) rotateTurn(tx repository.Transaction, room *entity.Room, baseURL string, preceding ...*entity.OutboxMessage) error {
+	if room.IsArchived() {
+		return entity.ErrRoomArchived
+	}
+	// ROOM_END task may be lost. the room is archived instead of passing the turn.
+	if now := time.Now(); room.IsEnded(now) {
+		if err := room.Archive(now); err != nil {
+			return err
+		}
+		return archive(tx, room, preceding...)
 	}
 
+	room.DueAt = room.NextDueAt()
+	nxtTurnAccountID := room.NextTurn()
 	nxtMember, err := ts.memberService.Get(nxtTurnAccountID)
 	if err != nil {
 		return err
 	}
 
-	taskClient := tasks.GetClient()
-	// 2. MEMBER_ON_DUTY task register
-	if _, err := taskClient.RegisterTask(
-		taskClient.BuildTask(baseURL,
-			genUniqueTaskID(roomID, nxtTurnAccountID, vo.MemberOnDutyCode),
-			vo.NewTaskVO(roomID, nxtMember.Email, vo.MemberOnDutyCode).Encode(),
-			taskspb.HttpMethod_POST,
-			rightNow,
-		)); err != nil {
+	messages := supersede(preceding, turnTasks(baseURL, room, nxtMember))
+	// 1. room update
+	if _, err := tx.Rooms().Update(room); err != nil {
 		return err
 	}
-
-	// 3. MEMBER_BEFORE_1HR task register
-	if _, err := taskClient.RegisterTask(
-		taskClient.BuildTask(baseURL,
-			genUniqueTaskID(roomID, nxtTurnAccountID, vo.MemberBefore1HRCode),
-			vo.NewTaskVO(roomID, nxtMember.Email, vo.MemberBefore1HRCode).Encode(),
-			taskspb.HttpMethod_POST,
-			dueAt.Add(-oneHour))); err != nil {
+	// 2. 이전 턴에서 작성중이던 임시저장본 제거
+	if err := tx.DiaryDrafts().DeleteAllBefore(room.ID, room.Turn); err != nil {
 		return err
 	}
-	// 4. MEMBER_BEFORE_4HR task register
-	if _, err := taskClient.RegisterTask(
-		taskClient.BuildTask(baseURL,
-			genUniqueTaskID(roomID, nxtTurnAccountID, vo.MemberBefore4HRCode),
-			vo.NewTaskVO(roomID, nxtMember.Email, vo.MemberBefore4HRCode).Encode(),
-			taskspb.HttpMethod_POST,
-			dueAt.Add(-fourHour))); err != nil {
-		return err
+	// 3. task register
+	return tx.Outbox().Create(messages...)
+}
+
+// SkipTurn passes the turn of on-duty member right away, as ROOM_PERIOD_FIN task does.
+// The next writer is notified by MEMBER_ON_DUTY task.
+func (ts *taskService) SkipTurn(roomID, accountID uint, baseURL string) error {
+	return ts.unitOfWork.Do(func(tx repository.Transaction) error {
+		room, err := tx.Rooms().GetByIDForUpdate(roomID)
+		if err != nil {
+			return err
+		}
+		if !room.IsTurn(accountID) {
+			return entity.ErrNotDiaryTurn
+		}
+		return ts.rotateTurn(tx, room, baseURL,
+			entity.NewCancelMessage(genUniqueTaskID(room.ID, room.TurnAccountID, vo.RoomPeriodFinCode)))
+	})
+}
+
+// RegisterRoomEndTask registers ROOM_END task which archives the room at its end date.
+func (ts *taskService) RegisterRoomEndTask(baseURL string, room *entity.Room) (taskID string, err error) {
+	if room.EndAt == nil {
+		return "", entity.ErrInvalidRoomEndAt
 	}
-	// 5. Next ROOM_PERIOD_FIN task register
-	if _, err := ts.RegisterRoomPeriodFINTask(
-		taskClient,
-		baseURL,
-		roomID,
-		nxtTurnAccountID,
-		dueAt,
-	); err != nil {
+	message := entity.NewScheduleMessage(roomEndTask(baseURL, room))
+	if err := ts.unitOfWork.Do(func(tx repository.Transaction) error {
+		return tx.Outbox().Create(message)
+	}); err != nil {
+		return "", err
+	}
+	return message.Task.ID, nil
+}
+
+// DoRoomEndTask archives the room, if it is not archived (or unarchived with a new end date) yet.
+func (ts *taskService) DoRoomEndTask(roomID uint) error {
+	return ts.unitOfWork.Do(func(tx repository.Transaction) error {
+		room, err := tx.Rooms().GetByIDForUpdate(roomID)
+		if err != nil {
+			return err
+		}
+		if room.IsArchived() {
+			return nil
+		}
+		now := time.Now()
+		if !room.IsEnded(now) {
+			return entity.ErrStaleTask
+		}
+		if err := room.Archive(now); err != nil {
+			return err
+		}
+		return archive(tx, room)
+	})
+}
+
+// ArchiveRoom stops turn rotation of the room and makes it read-only. (diaries and joins are rejected)
+// Every pending task of current turn and ROOM_END task are cancelled.
+func (ts *taskService) ArchiveRoom(roomID uint) (*entity.Room, error) {
+	var room *entity.Room
+	if err := ts.unitOfWork.Do(func(tx repository.Transaction) (err error) {
+		if room, err = tx.Rooms().GetByIDForUpdate(roomID); err != nil {
+			return err
+		}
+		if err := room.Archive(time.Now()); err != nil {
+			return err
+		}
+		return archive(tx, room, entity.NewCancelMessage(genRoomEndTaskID(room.ID)))
+	}); err != nil {
+		return nil, err
+	}
+	return room, nil
+}
+
+// archive saves archived room, and cancels tasks of current turn for every code by tx. The room should be locked by tx.
+// Tasks which failed to be cancelled are removed by reconciler as orphans.
+func archive(tx repository.Transaction, room *entity.Room, preceding ...*entity.OutboxMessage) error {
+	messages := append([]*entity.OutboxMessage{}, preceding...)
+	codes := append([]vo.TaskCode{}, vo.TaskCodes...)
+	for _, offset := range room.ReminderOffsets {
+		codes = append(codes, vo.MemberBeforeCode(offset))
+	}
+	for _, code := range codes {
+		messages = append(messages, cancelMessage(code, room.ID, room.TurnAccountID))
+	}
+	if _, err := tx.Rooms().Update(room); err != nil {
 		return err
 	}
-	return nil
+	return tx.Outbox().Create(messages...)
 }
 
-func (ts *taskService) RegisterRoomPeriodFINTask(c *tasks.Client, baseURL string, roomID, accountID uint, dueAt *time.Time) (taskID string, err error) {
-	var task *taskspb.Task
-	code := vo.RoomPeriodFinCode
-	if task, err = c.RegisterTask(
-		c.BuildTask(
-			baseURL,
-			genUniqueTaskID(roomID, accountID, code),
-			vo.NewTaskVO(roomID, "", code).Encode(),
-			taskspb.HttpMethod_POST,
-			*dueAt,
-		),
-	); err != nil {
-		return "", err
+// UnarchiveRoom reopens the room, and rebuilds the schedule of current turn from now.
+// Current turn member gets a whole period, and ROOM_END task is registered again if the room has an end date.
+func (ts *taskService) UnarchiveRoom(roomID uint, baseURL string) (*entity.Room, error) {
+	var room *entity.Room
+	if err := ts.unitOfWork.Do(func(tx repository.Transaction) (err error) {
+		if room, err = tx.Rooms().GetByIDForUpdate(roomID); err != nil {
+			return err
+		}
+		if err := room.Unarchive(time.Now()); err != nil {
+			return err
+		}
+		// reminders are not sent to unsigned member, but the turn should be passed.
+		turnMember, err := ts.memberService.Get(room.TurnAccountID)
+		if err == entity.ErrMemberNotFound {
+			turnMember = nil
+		} else if err != nil {
+			return err
+		}
+
+		messages := []*entity.OutboxMessage{}
+		for _, task := range turnTasks(baseURL, room, turnMember) {
+			messages = append(messages, entity.NewScheduleMessage(task))
+		}
+		if room.EndAt != nil {
+			messages = append(messages, entity.NewScheduleMessage(roomEndTask(baseURL, room)))
+		}
+		if _, err := tx.Rooms().Update(room); err != nil {
+			return err
+		}
+		return tx.Outbox().Create(messages...)
+	}); err != nil {
+		return nil, err
 	}
+	return room, nil
+}
 
-	return task.Name, nil
+func (ts *taskService) RegisterRoomPeriodFINTask(baseURL string, room *entity.Room) (taskID string, err error) {
+	task := vo.NewTaskVO(room.ID, "", vo.RoomPeriodFinCode).OnTurn(room.Turn, room.DueAt)
+	return ts.schedule(baseURL, room.TurnAccountID, task, *room.DueAt)
 }
 
 func (ts *taskService) DoMemberOnDutyTask(roomID uint, email string) (err error) {
@@ -135,6 +240,9 @@ func (ts *taskService) DoMemberOnDutyTask(roomID uint, email string) (err error)
 	if err != nil {
 		return err
 	}
+	if room.IsArchived() {
+		return entity.ErrRoomArchived
+	}
 
 	member, err := ts.memberService.GetByEmail(email)
 	if err != nil {
@@ -157,11 +265,18 @@ func (ts *taskService) DoMemberBeforeTask(roomID uint, email string, code vo.Tas
 	if err != nil {
 		return err
 	}
+	if room.IsArchived() {
+		return entity.ErrRoomArchived
+	}
 
 	member, err := ts.memberService.GetByEmail(email)
 	if err != nil {
 		return err
 	}
+	if offset, ok := code.ReminderOffset(); ok && member.IsReminderOptedOut(offset) {
+		logger.Info("member opted out of the reminder. email: " + email + ", code: " + string(code))
+		return nil
+	}
 
 	alarm, err := ts.alarmService.Create(member.ID, roomID, code, room.Name, "", "")
 	if err != nil {
@@ -179,13 +294,18 @@ func (ts *taskService) DoMemberPostedDiaryTask(roomID uint, baseURL string) erro
 		return err
 	}
 
-	// 1. BroadCast alarm to RoomMember (except current member)
-	member, err := ts.memberService.Get(room.TurnAccountID)
+	diary, err := ts.diaryService.GetLatest(roomID)
 	if err != nil {
 		return err
 	}
+	// 이미 턴이 넘어간 뒤에 도착한 task는 무시한다.
+	if diary.Turn != room.Turn || !room.IsTurn(diary.AuthorID) {
+		return entity.Permanent(fmt.Errorf("There is no diary posted on current turn. roomID: %d, turn: %d", roomID, room.Turn))
+	}
 
-	alarm, err := ts.alarmService.Create(member.ID, roomID, vo.MemberPostedDiaryCode, room.Name, mockDiaryTitle, member.Name)
+	// 1. BroadCast alarm to RoomMember (except current member)
+	member := diary.Author
+	alarm, err := ts.alarmService.Create(member.ID, roomID, vo.MemberPostedDiaryCode, room.Name, diary.Title, member.Name)
 	if err != nil {
 		return err
 	}
@@ -193,53 +313,315 @@ func (ts *taskService) DoMemberPostedDiaryTask(roomID uint, baseURL string) erro
 		return err
 	}
 
-	// 2. 기존에 존재하는 ROOM_PERIOD_FIN task 업데이트 (바로 실행되도록 트리거)
-	taskClient := tasks.GetClient()
-	taskID := genUniqueTaskID(room.ID, room.TurnAccountID, vo.RoomPeriodFinCode)
-	if err := taskClient.DeleteTask(taskID); err != nil {
-		return err
-	}
-	return ts.DoRoomPeriodFINTask(room.ID, baseURL)
+	// 2. 기존에 존재하는 ROOM_PERIOD_FIN task 제거 후 바로 다음 턴으로 넘긴다.
+	return ts.unitOfWork.Do(func(tx repository.Transaction) error {
+		locked, err := tx.Rooms().GetByIDForUpdate(roomID)
+		if err != nil {
+			return err
+		}
+		// the turn may be passed by ROOM_PERIOD_FIN task in the meantime.
+		if locked.Turn != diary.Turn || !locked.IsTurn(diary.AuthorID) {
+			return entity.Permanent(fmt.Errorf("There is no diary posted on current turn. roomID: %d, turn: %d", roomID, locked.Turn))
+		}
+		return ts.rotateTurn(tx, locked, baseURL,
+			entity.NewCancelMessage(genUniqueTaskID(locked.ID, locked.TurnAccountID, vo.RoomPeriodFinCode)))
+	})
 }
 
+// RegisterMemberPostedDiaryTask registers MEMBER_POSTED_DIARY task which runs right away.
 func (ts *taskService) RegisterMemberPostedDiaryTask(roomID uint, baseURL string) (taskID string, err error) {
-	// TODO: diary crud 만들어지면 구현
-	return "", nil
+	room, err := ts.roomService.Get(roomID, entity.Ignore)
+	if err != nil {
+		return "", err
+	}
+	author, err := ts.memberService.Get(room.TurnAccountID)
+	if err != nil {
+		return "", err
+	}
+
+	task := vo.NewTaskVO(roomID, author.Email, vo.MemberPostedDiaryCode).OnTurn(room.Turn, room.DueAt)
+	return ts.schedule(baseURL, author.ID, task, rightNow)
 }
 
-func (ts *taskService) UpdateRoomPeriodFINTaskETA(c *tasks.Client, baseURL string, roomID, turnAccountID uint, nextDueAt *time.Time) error {
-	code := vo.RoomPeriodFinCode
-	taskID := genUniqueTaskID(roomID, turnAccountID, vo.RoomPeriodFinCode)
-	if _, err := c.UpdateTask(
-		taskID,
-		c.BuildTask(baseURL,
-			genUniqueTaskID(roomID, turnAccountID, code),
-			vo.NewTaskVO(roomID, "", code).Encode(),
-			taskspb.HttpMethod_POST,
-			*nextDueAt,
-		)); err != nil {
-		return err
+// UpdateReminderOffsets patches the room which is locked, and saves it with reminder tasks of current turn
+// in a single transaction. patch returns the room whose reminder offsets are changed.
+// Only removed reminders are cancelled and only added ones are scheduled, so unchanged reminders are kept as they are.
+// Reminders whose time is already passed are not registered, and archived room has no reminder.
+func (ts *taskService) UpdateReminderOffsets(baseURL string, roomID uint, patch func(room *entity.Room) (*entity.Room, error)) (*entity.Room, error) {
+	var room *entity.Room
+	if err := ts.unitOfWork.Do(func(tx repository.Transaction) error {
+		locked, err := tx.Rooms().GetByIDForUpdate(roomID)
+		if err != nil {
+			return err
+		}
+		previous := locked.ReminderOffsets
+		if room, err = patch(locked); err != nil {
+			return err
+		}
+		messages := []*entity.OutboxMessage{}
+		if !room.IsArchived() {
+			if messages, err = ts.reminderMessages(baseURL, room, previous); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.Rooms().Update(room); err != nil {
+			return err
+		}
+		if len(messages) == 0 {
+			return nil
+		}
+		return tx.Outbox().Create(messages...)
+	}); err != nil {
+		return nil, err
 	}
-	return nil
+	return room, nil
 }
 
-func (ts *taskService) GetTask(c *tasks.Client, code vo.TaskCode, roomID, turnAccountID uint) (task *taskspb.Task, err error) {
-	taskID := genUniqueTaskID(roomID, turnAccountID, code)
-	if task, err = c.GetTask(taskID); err != nil {
+// reminderMessages returns outbox messages of reminder tasks which differ between previous and room's offsets.
+func (ts *taskService) reminderMessages(baseURL string, room *entity.Room, previous []time.Duration) ([]*entity.OutboxMessage, error) {
+	messages := []*entity.OutboxMessage{}
+	for _, offset := range previous {
+		if !room.HasReminderOffset(offset) {
+			messages = append(messages, entity.NewCancelMessage(genUniqueTaskID(room.ID, room.TurnAccountID, vo.MemberBeforeCode(offset))))
+		}
+	}
+	// reminders are not sent to unsigned member
+	turnMember, err := ts.memberService.Get(room.TurnAccountID)
+	if err == entity.ErrMemberNotFound {
+		return messages, nil
+	}
+	if err != nil {
 		return nil, err
 	}
+
+	previousRoom := entity.Room{ReminderOffsets: previous}
+	now := time.Now()
+	for _, task := range turnTasks(baseURL, room, turnMember) {
+		offset, ok := task.Task.Code.ReminderOffset()
+		if ok && !previousRoom.HasReminderOffset(offset) && !isPassed(task, now) {
+			messages = append(messages, entity.NewScheduleMessage(task))
+		}
+	}
+	return messages, nil
+}
+
+func (ts *taskService) UpdateRoomPeriodFINTaskETA(baseURL string, roomID, turnAccountID uint, nextDueAt *time.Time) error {
+	return ts.unitOfWork.Do(func(tx repository.Transaction) error {
+		return tx.Outbox().Create(
+			entity.NewCancelMessage(genUniqueTaskID(roomID, turnAccountID, vo.RoomPeriodFinCode)),
+			ts.scheduleMessage(baseURL, turnAccountID, vo.NewTaskVO(roomID, "", vo.RoomPeriodFinCode).OnTurn(0, nextDueAt), *nextDueAt),
+		)
+	})
+}
+
+func (ts *taskService) GetTask(code vo.TaskCode, roomID, turnAccountID uint) (*entity.ScheduledTask, error) {
+	return ts.scheduler.Get(genUniqueTaskID(roomID, turnAccountID, code))
+}
+
+// DeleteTask writes a cancel intent. The task is removed by outbox relay.
+func (ts *taskService) DeleteTask(code vo.TaskCode, roomID, turnAccountID uint) error {
+	return ts.unitOfWork.Do(func(tx repository.Transaction) error {
+		return tx.Outbox().Create(cancelMessage(code, roomID, turnAccountID))
+	})
+}
+
+func cancelMessage(code vo.TaskCode, roomID, turnAccountID uint) *entity.OutboxMessage {
+	return entity.NewCancelMessage(genUniqueTaskID(roomID, turnAccountID, code))
+}
+
+// Handle runs a scheduled task exactly once per (task id, fingerprint).
+// It is called by tasks callback api (google cloud tasks) or local scheduler workers.
+// Every attempt is recorded on the execution ledger. Returned error is
+//   - entity.ErrTaskAlreadyExecuted: duplicated delivery (ignored)
+//   - entity.ErrTaskInProgress: the same task is running (should be retried later)
+//   - entity.PermanentTaskError: never succeeds on retry
+//   - otherwise transient error (should be retried)
+func (ts *taskService) Handle(task *entity.ScheduledTask) error {
+	startedAt := time.Now()
+	execution, err := ts.taskExecutionRepository.Acquire(
+		entity.NewTaskExecution(task.ID, task.Task.Fingerprint(), task.Task.RoomID, string(task.Task.Code)),
+		startedAt,
+		taskExecutionLease,
+	)
+	if err == entity.ErrTaskAlreadyExecuted || err == entity.ErrTaskInProgress {
+		if aerr := ts.taskExecutionRepository.CreateAttempt(&entity.TaskAttempt{
+			ExecutionID: execution.ID,
+			Number:      execution.Attempts,
+			Outcome:     entity.AttemptDuplicate,
+			Error:       err.Error(),
+			StartedAt:   startedAt,
+			FinishedAt:  time.Now(),
+		}); aerr != nil {
+			logger.Error(aerr.Error())
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	err = classifyTaskError(ts.dispatch(task))
+	finishedAt := time.Now()
+	attempt := &entity.TaskAttempt{
+		ExecutionID: execution.ID,
+		Number:      execution.Attempts,
+		Outcome:     execution.Finish(err, finishedAt),
+		StartedAt:   startedAt,
+		FinishedAt:  finishedAt,
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+	if ferr := ts.taskExecutionRepository.Finish(execution, attempt); ferr != nil {
+		logger.Error(ferr.Error())
+	}
+	return err
+}
+
+// dispatch runs a task by its code.
+func (ts *taskService) dispatch(task *entity.ScheduledTask) (err error) {
+	baseURL := task.CallbackURL
+	dto := task.Task
+	if err = ts.checkTurn(dto); err != nil {
+		return
+	}
+	switch dto.Code {
+	case vo.RoomPeriodFinCode:
+		err = ts.DoRoomPeriodFINTask(dto.RoomID, baseURL)
+	case vo.MemberOnDutyCode:
+		err = ts.DoMemberOnDutyTask(dto.RoomID, dto.Email)
+	case vo.MemberPostedDiaryCode:
+		err = ts.DoMemberPostedDiaryTask(dto.RoomID, baseURL)
+	case vo.DeferredPushCode:
+		err = ts.alarmService.PushDeferred(dto.AlarmID, dto.Email)
+	case vo.SwapRequestExpireCode:
+		err = ts.swapRequestService.Expire(dto.SwapRequestID)
+	case vo.RoomEndCode:
+		err = ts.DoRoomEndTask(dto.RoomID)
+	default:
+		if _, ok := dto.Code.ReminderOffset(); ok {
+			err = ts.DoMemberBeforeTask(dto.RoomID, dto.Email, dto.Code)
+			return
+		}
+		err = entity.Permanent(fmt.Errorf("Not registered task code. [ " + string(dto.Code) + " ]"))
+	}
 	return
 }
 
-func (ts *taskService) DeleteTask(c *tasks.Client, code vo.TaskCode, roomID, turnAccountID uint) error {
-	taskID := genUniqueTaskID(roomID, turnAccountID, code)
-	if err := c.DeleteTask(taskID); err != nil {
+// checkTurn ignores a task which is scheduled on a passed turn. (ex. reminder of a member who already posted)
+func (ts *taskService) checkTurn(task vo.TaskVO) error {
+	if task.Turn == 0 {
+		return nil
+	}
+	room, err := ts.roomService.Get(task.RoomID, entity.Ignore)
+	if err != nil {
 		return err
 	}
+	if room.Turn != task.Turn {
+		return entity.ErrStaleTask
+	}
 	return nil
 }
 
+// classifyTaskError marks errors which never succeed on retry as permanent.
+func classifyTaskError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, entity.ErrRoomNotFound),
+		errors.Is(err, entity.ErrMemberNotFound),
+		errors.Is(err, entity.ErrAlarmNotFound),
+		errors.Is(err, entity.ErrSwapRequestNotFound),
+		errors.Is(err, entity.ErrStaleTask),
+		errors.Is(err, entity.ErrNotDiaryTurn),
+		errors.Is(err, entity.ErrRoomArchived):
+		return entity.Permanent(err)
+	}
+	return err
+}
+
+// turnTasks returns every task of room's current turn.
+// MEMBER_ON_DUTY and reminder (MEMBER_BEFORE_*) tasks of room's reminder offsets are sent to turnMember,
+// and ROOM_PERIOD_FIN task passes the turn to next member at room.DueAt.
+// If turnMember is nil (ex. unsigned member), only ROOM_PERIOD_FIN task is returned.
+func turnTasks(baseURL string, room *entity.Room, turnMember *entity.Member) []*entity.ScheduledTask {
+	dueAt := *room.DueAt
+	newTask := func(email string, code vo.TaskCode, scheduledAt time.Time) *entity.ScheduledTask {
+		return entity.NewScheduledTask(
+			genUniqueTaskID(room.ID, room.TurnAccountID, code),
+			vo.NewTaskVO(room.ID, email, code).OnTurn(room.Turn, room.DueAt),
+			baseURL,
+			scheduledAt,
+		)
+	}
+
+	tasks := []*entity.ScheduledTask{}
+	if turnMember != nil {
+		tasks = append(tasks, newTask(turnMember.Email, vo.MemberOnDutyCode, rightNow))
+		for _, offset := range room.ReminderOffsets {
+			tasks = append(tasks, newTask(turnMember.Email, vo.MemberBeforeCode(offset), dueAt.Add(-offset)))
+		}
+	}
+	return append(tasks, newTask("", vo.RoomPeriodFinCode, dueAt))
+}
+
+// supersede returns preceding messages followed by schedule messages of tasks.
+// A cancel of the task id which is scheduled again is dropped, since the new instance replaces the old one.
+// (ex. the same member takes the next turn in a single member room)
+// Otherwise a retried cancel could remove the new instance, which is delivered earlier.
+func supersede(preceding []*entity.OutboxMessage, tasks []*entity.ScheduledTask) []*entity.OutboxMessage {
+	scheduled := map[string]bool{}
+	for _, task := range tasks {
+		scheduled[task.ID] = true
+	}
+	messages := []*entity.OutboxMessage{}
+	for _, message := range preceding {
+		if message.Action == entity.OutboxCancel && scheduled[message.Task.ID] {
+			continue
+		}
+		messages = append(messages, message)
+	}
+	for _, task := range tasks {
+		messages = append(messages, entity.NewScheduleMessage(task))
+	}
+	return messages
+}
+
+// roomEndTask returns ROOM_END task of the room. It is not bound to a turn, so it is fingerprinted by the end date.
+func roomEndTask(baseURL string, room *entity.Room) *entity.ScheduledTask {
+	return entity.NewScheduledTask(
+		genRoomEndTaskID(room.ID),
+		vo.NewTaskVO(room.ID, "", vo.RoomEndCode).OnTurn(0, room.EndAt),
+		baseURL,
+		*room.EndAt,
+	)
+}
+
+// schedule writes a schedule intent and returns the task id. The task is registered by outbox relay.
+func (ts *taskService) schedule(baseURL string, accountID uint, task vo.TaskVO, scheduledAt time.Time) (taskID string, err error) {
+	message := ts.scheduleMessage(baseURL, accountID, task, scheduledAt)
+	if err := ts.unitOfWork.Do(func(tx repository.Transaction) error {
+		return tx.Outbox().Create(message)
+	}); err != nil {
+		return "", err
+	}
+	return message.Task.ID, nil
+}
+
+func (ts *taskService) scheduleMessage(baseURL string, accountID uint, task vo.TaskVO, scheduledAt time.Time) *entity.OutboxMessage {
+	return entity.NewScheduleMessage(entity.NewScheduledTask(
+		genUniqueTaskID(task.RoomID, accountID, task.Code),
+		task,
+		baseURL,
+		scheduledAt,
+	))
+}
+
 // room_id + AccountID + eventCode
 func genUniqueTaskID(roomID, turnAccountID uint, code vo.TaskCode) string {
 	return fmt.Sprintf("%d-%d-%s", roomID, turnAccountID, code)
 }
+
+func genRoomEndTaskID(roomID uint) string {
+	return fmt.Sprintf("room-end-%d", roomID)
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/vo"
+)
+
+// outboxActions summarizes outbox messages as "ACTION task-id", sorted.
+func outboxActions(messages []*entity.OutboxMessage) []string {
+	actions := []string{}
+	for _, message := range messages {
+		actions = append(actions, string(message.Action)+" "+message.Task.ID)
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+func TestTaskServiceUpdateReminderOffsets(t *testing.T) {
+	const roomID, accountID = 1, 2
+	taskID := func(offset time.Duration) string {
+		return genUniqueTaskID(roomID, accountID, vo.MemberBeforeCode(offset))
+	}
+	dueAt := time.Now().Add(time.Hour * 2)
+	archivedAt := time.Now()
+
+	tests := []struct {
+		name        string
+		previous    []time.Duration
+		offsets     []time.Duration
+		archivedAt  *time.Time
+		unsigned    bool
+		wantActions []string
+	}{
+		{"add and remove", []time.Duration{time.Hour, time.Minute * 90}, []time.Duration{time.Hour, time.Minute * 30},
+			nil, false, []string{"CANCEL " + taskID(time.Minute*90), "SCHEDULE " + taskID(time.Minute*30)}},
+		{"unchanged", []time.Duration{time.Hour}, []time.Duration{time.Hour}, nil, false, []string{}},
+		{"passed reminder is not scheduled", []time.Duration{time.Hour}, []time.Duration{time.Hour, time.Hour * 3},
+			nil, false, []string{}},
+		{"remove every reminder", []time.Duration{time.Hour, time.Minute * 30}, []time.Duration{},
+			nil, false, []string{"CANCEL " + taskID(time.Hour), "CANCEL " + taskID(time.Minute*30)}},
+		{"unsigned turn member", []time.Duration{time.Hour}, []time.Duration{time.Minute * 30},
+			nil, true, []string{"CANCEL " + taskID(time.Hour)}},
+		{"archived room", []time.Duration{time.Hour}, []time.Duration{time.Minute * 30}, &archivedAt, false, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room := &entity.Room{
+				ID:              roomID,
+				Orders:          []uint{accountID},
+				TurnAccountID:   accountID,
+				DueAt:           &dueAt,
+				ReminderOffsets: tt.previous,
+				ArchivedAt:      tt.archivedAt,
+			}
+			members := map[uint]entity.Member{}
+			if !tt.unsigned {
+				members[accountID] = entity.Member{ID: accountID, Email: "a@voda.com"}
+			}
+			uow := newFakeUnitOfWork()
+			uow.tx.rooms.rooms[roomID] = *room
+			ts := NewTaskService(uow, nil, nil, nil, nil, &fakeMemberService{members: members}, nil, nil)
+
+			_, err := ts.UpdateReminderOffsets("https://voda.com", roomID, func(room *entity.Room) (*entity.Room, error) {
+				room.ReminderOffsets = tt.offsets
+				return room, nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := outboxActions(uow.tx.outbox.messages); !reflect.DeepEqual(got, tt.wantActions) {
+				t.Errorf("outbox = %v, want %v", got, tt.wantActions)
+			}
+			if saved, ok := uow.tx.rooms.rooms[roomID]; !ok || !reflect.DeepEqual(saved.ReminderOffsets, tt.offsets) {
+				t.Errorf("saved room offsets = %v, want %v", saved.ReminderOffsets, tt.offsets)
+			}
+		})
+	}
+}
+
+func TestSupersede(t *testing.T) {
+	task := func(id string) *entity.ScheduledTask {
+		return entity.NewScheduledTask(id, vo.NewTaskVO(1, "", vo.RoomPeriodFinCode), "", rightNow)
+	}
+	tests := []struct {
+		name      string
+		preceding []*entity.OutboxMessage
+		tasks     []*entity.ScheduledTask
+		want      []string
+	}{
+		{"no preceding", nil, []*entity.ScheduledTask{task("1-2-FIN")}, []string{"SCHEDULE 1-2-FIN"}},
+		{"cancel of an other id is kept", []*entity.OutboxMessage{entity.NewCancelMessage("1-2-FIN")},
+			[]*entity.ScheduledTask{task("1-3-FIN")}, []string{"CANCEL 1-2-FIN", "SCHEDULE 1-3-FIN"}},
+		{"cancel of the same id is dropped", []*entity.OutboxMessage{entity.NewCancelMessage("1-2-FIN")},
+			[]*entity.ScheduledTask{task("1-2-FIN")}, []string{"SCHEDULE 1-2-FIN"}},
+		{"preceding schedule is kept", []*entity.OutboxMessage{entity.NewScheduleMessage(task("1-2-FIN"))},
+			[]*entity.ScheduledTask{task("1-2-FIN")}, []string{"SCHEDULE 1-2-FIN", "SCHEDULE 1-2-FIN"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, message := range supersede(tt.preceding, tt.tasks) {
+				got = append(got, string(message.Action)+" "+message.Task.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("supersede() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskServiceSkipTurn(t *testing.T) {
+	const roomID, first, second = 1, 2, 3
+	dueAt := time.Now().Add(time.Hour * 12).Truncate(time.Second)
+	nextDueAt := dueAt.Add(entity.PeriodToDuration(1))
+	members := map[uint]entity.Member{
+		first:  {ID: first, Email: "a@voda.com"},
+		second: {ID: second, Email: "b@voda.com"},
+	}
+
+	tests := []struct {
+		name     string
+		orders   []uint
+		skipper  uint
+		wantErr  error
+		wantTurn uint
+		wantFins map[uint]time.Time // turn account id -> ROOM_PERIOD_FIN time
+	}{
+		{"single member", []uint{first}, first, nil, first, map[uint]time.Time{first: nextDueAt}},
+		{"two members", []uint{first, second}, first, nil, second, map[uint]time.Time{second: nextDueAt}},
+		{"not turn", []uint{first, second}, second, entity.ErrNotDiaryTurn, first, map[uint]time.Time{first: dueAt}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room := &entity.Room{ID: roomID, Period: 1, Orders: tt.orders, TurnAccountID: first, Turn: 1, DueAt: &dueAt}
+			uow := newFakeUnitOfWork()
+			uow.tx.rooms.rooms[roomID] = *room
+			scheduler := newFakeScheduler()
+			for _, task := range turnTasks("https://voda.com", room, nil) {
+				if _, err := scheduler.Schedule(&entity.NewScheduleMessage(task).Task); err != nil {
+					t.Fatal(err)
+				}
+			}
+			ts := NewTaskService(uow, nil, scheduler, nil, &fakeRoomService{rooms: uow.tx.rooms},
+				&fakeMemberService{members: members}, nil, nil)
+
+			if err := ts.SkipTurn(roomID, tt.skipper, "https://voda.com"); err != tt.wantErr {
+				t.Fatalf("SkipTurn() = %v, want %v", err, tt.wantErr)
+			}
+			// the turn is checked on the locked room, so that the turn is not passed twice.
+			if uow.tx.rooms.locks != 1 {
+				t.Errorf("room is locked %d times, want once", uow.tx.rooms.locks)
+			}
+			// a failed delivery is retried after the next messages, so the outbox is relayed in reverse order.
+			messages := uow.tx.outbox.messages
+			for i := len(messages) - 1; i >= 0; i-- {
+				if err := (&outboxRelay{scheduler: scheduler}).deliver(messages[i]); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := uow.tx.rooms.rooms[roomID].TurnAccountID; got != tt.wantTurn {
+				t.Errorf("turn account = %d, want %d", got, tt.wantTurn)
+			}
+			for _, accountID := range tt.orders {
+				task, err := scheduler.Get(genUniqueTaskID(roomID, accountID, vo.RoomPeriodFinCode))
+				want, ok := tt.wantFins[accountID]
+				if !ok {
+					if err != entity.ErrScheduledTaskNotFound {
+						t.Errorf("FIN of %d should be cancelled, got %v", accountID, err)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("FIN of %d: %v", accountID, err)
+				}
+				if !task.ScheduledAt.Equal(want) {
+					t.Errorf("FIN of %d scheduled at %s, want %s", accountID, task.ScheduledAt, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTaskServiceArchiveRoom(t *testing.T) {
+	const roomID, accountID = 1, 2
+	dueAt := time.Now().Add(time.Hour * 12).Truncate(time.Second)
+	endAt := dueAt.Add(entity.PeriodToDuration(7))
+	member := entity.Member{ID: accountID, Email: "a@voda.com"}
+	turnTaskIDs := []string{
+		genUniqueTaskID(roomID, accountID, vo.MemberBeforeCode(time.Hour)),
+		genUniqueTaskID(roomID, accountID, vo.MemberOnDutyCode),
+		genUniqueTaskID(roomID, accountID, vo.RoomPeriodFinCode),
+		genRoomEndTaskID(roomID),
+	}
+	sort.Strings(turnTaskIDs)
+
+	tests := []struct {
+		name      string
+		steps     []string // "archive" or "unarchive", relayed after each step
+		wantErr   error    // of the last step
+		wantTasks []string
+	}{
+		{"archive cancels every task", []string{"archive"}, nil, []string{}},
+		{"unarchive right after archive reschedules every task", []string{"archive", "unarchive"}, nil, turnTaskIDs},
+		{"archive again", []string{"archive", "unarchive", "archive"}, nil, []string{}},
+		{"archived room", []string{"archive", "archive"}, entity.ErrRoomArchived, []string{}},
+		{"not archived room", []string{"unarchive"}, entity.ErrRoomNotArchived, turnTaskIDs},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room := &entity.Room{
+				ID:              roomID,
+				Period:          1,
+				Orders:          []uint{accountID},
+				TurnAccountID:   accountID,
+				Turn:            1,
+				DueAt:           &dueAt,
+				EndAt:           &endAt,
+				ReminderOffsets: []time.Duration{time.Hour},
+			}
+			uow := newFakeUnitOfWork()
+			uow.tx.rooms.rooms[roomID] = *room
+			scheduler := newFakeScheduler()
+			for _, task := range append(turnTasks("https://voda.com", room, &member), roomEndTask("https://voda.com", room)) {
+				if _, err := scheduler.Schedule(&entity.NewScheduleMessage(task).Task); err != nil {
+					t.Fatal(err)
+				}
+			}
+			ts := NewTaskService(uow, nil, scheduler, nil, &fakeRoomService{rooms: uow.tx.rooms},
+				&fakeMemberService{members: map[uint]entity.Member{accountID: member}}, nil, nil)
+
+			var err error
+			for _, step := range tt.steps {
+				uow.calls, uow.tx.rooms.locks = 0, 0
+				if step == "archive" {
+					_, err = ts.ArchiveRoom(roomID)
+				} else {
+					_, err = ts.UnarchiveRoom(roomID, "https://voda.com")
+				}
+				// the room is locked, and the room and every task intent are written in a single transaction
+				if err == nil && (uow.calls != 1 || uow.tx.rooms.locks != 1) {
+					t.Errorf("%s ran %d transactions with %d locks, want 1", step, uow.calls, uow.tx.rooms.locks)
+				}
+				relayOutbox(t, uow, scheduler)
+			}
+			if err != tt.wantErr {
+				t.Fatalf("%s = %v, want %v", tt.steps[len(tt.steps)-1], err, tt.wantErr)
+			}
+
+			tasks, _ := scheduler.List()
+			got := []string{}
+			for _, task := range tasks {
+				got = append(got, task.ID)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.wantTasks) {
+				t.Errorf("tasks = %v, want %v", got, tt.wantTasks)
+			}
+			saved := uow.tx.rooms.rooms[roomID]
+			if archived := tt.steps[len(tt.steps)-1] == "archive" || tt.wantErr == entity.ErrRoomArchived; saved.IsArchived() != archived {
+				t.Errorf("room archived = %t, want %t", saved.IsArchived(), archived)
+			}
+			if fin, err := scheduler.Get(genUniqueTaskID(roomID, accountID, vo.RoomPeriodFinCode)); err == nil && !fin.ScheduledAt.Equal(*saved.DueAt) {
+				t.Errorf("FIN scheduled at %s, want room due at %s", fin.ScheduledAt, saved.DueAt)
+			}
+		})
+	}
+}
+
+func TestRoomEndTask(t *testing.T) {
+	endAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	extendedEndAt := endAt.AddDate(0, 0, 7)
+
+	tests := []struct {
+		name      string
+		endAt     time.Time
+		otherEnd  time.Time
+		wantEqual bool
+	}{
+		{"same end date", endAt, endAt, true},
+		{"extended end date", endAt, extendedEndAt, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := roomEndTask("https://voda.com", &entity.Room{ID: 1, EndAt: &tt.endAt})
+			other := roomEndTask("https://voda.com", &entity.Room{ID: 1, EndAt: &tt.otherEnd})
+			if task.ID != other.ID {
+				t.Errorf("task id = %s, want %s", other.ID, task.ID)
+			}
+			if got := task.Task.Fingerprint() == other.Task.Fingerprint(); got != tt.wantEqual {
+				t.Errorf("fingerprint %s == %s is %t, want %t", task.Task.Fingerprint(), other.Task.Fingerprint(), got, tt.wantEqual)
+			}
+		})
+	}
+}
@@ -46,3 +46,29 @@ func (t *TokenVerifier) Verify(authCode string) (claims *entity.AuthCodeClaims,
 
 	return claims, err
 }
+
+// VerifyRoomInvite checks signature and expiry of an invite token.
+// Revocation and usage are not checked here, since they are kept by the invite itself.
+func (t *TokenVerifier) VerifyRoomInvite(inviteToken string) (*entity.RoomInviteClaims, error) {
+	token, err := jwt.ParseWithClaims(
+		inviteToken,
+		&entity.RoomInviteClaims{},
+		func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
+			}
+			return []byte(t.SecretKey), nil
+		},
+	)
+	if err != nil {
+		if verr, ok := err.(*jwt.ValidationError); ok && verr.Errors&jwt.ValidationErrorExpired != 0 {
+			return nil, entity.ErrRoomInviteExpired
+		}
+		return nil, entity.ErrInvalidRoomInvite
+	}
+	claims, ok := token.Claims.(*entity.RoomInviteClaims)
+	if !ok || !token.Valid {
+		return nil, entity.ErrInvalidRoomInvite
+	}
+	return claims, nil
+}
@@ -13,6 +13,16 @@ func Contains(s []uint, x uint) bool {
 	return false
 }
 
+// Index returns the index of the first x in uint slice, or -1 if it is not present
+func Index(s []uint, x uint) int {
+	for i, v := range s {
+		if v == x {
+			return i
+		}
+	}
+	return -1
+}
+
 // Remove delete uint value from slice. It returns (newSlice, removedID)
 // 0 means invalid value
 func Remove(s []uint, val uint) ([]uint, uint) {
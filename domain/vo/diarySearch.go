@@ -0,0 +1,32 @@
+package vo
+
+import (
+	"strings"
+	"time"
+)
+
+// DiarySearchVO is a condition of diary full-text search
+type DiarySearchVO struct {
+	Keyword  string
+	RoomIDs  []uint     // rooms which member can access
+	AuthorID uint       // 0 means every author
+	From     *time.Time // inclusive, nil means unbounded
+	To       *time.Time // exclusive, nil means unbounded
+	Limit    uint
+	Offset   uint
+}
+
+// Terms splits keyword into unique search terms
+func (sv DiarySearchVO) Terms() []string {
+	terms := []string{}
+	seen := map[string]bool{}
+	for _, term := range strings.Fields(sv.Keyword) {
+		lower := strings.ToLower(term)
+		if seen[lower] {
+			continue
+		}
+		seen[lower] = true
+		terms = append(terms, term)
+	}
+	return terms
+}
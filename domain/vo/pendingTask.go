@@ -0,0 +1,15 @@
+package vo
+
+import "time"
+
+// PendingTask is a task of a room which is waiting on scheduler
+// AccountID is the turn account which the task is registered for.
+type PendingTask struct {
+	TaskID      string
+	AccountID   uint
+	Email       string
+	Code        TaskCode
+	Turn        uint
+	DueAt       *time.Time
+	ScheduledAt time.Time // zero value means right now
+}
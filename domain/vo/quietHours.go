@@ -0,0 +1,80 @@
+package vo
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidQuietHours is returned when quiet hours are not formatted as HH:MM
+var ErrInvalidQuietHours = errors.New("quiet hours must be formatted as HH:MM")
+
+// QuietHours is a daily window of member's local time when non-urgent pushes are deferred.
+// Start and End are minutes from midnight, and the window may pass midnight. (ex. 23:00 ~ 08:00)
+// If Start equals End, quiet hours are disabled.
+type QuietHours struct {
+	Start uint16
+	End   uint16
+}
+
+// ParseQuietHours parses HH:MM formatted start and end
+func ParseQuietHours(start, end string) (QuietHours, error) {
+	s, err := parseClock(start)
+	if err != nil {
+		return QuietHours{}, err
+	}
+	e, err := parseClock(end)
+	if err != nil {
+		return QuietHours{}, err
+	}
+	return QuietHours{Start: s, End: e}, nil
+}
+
+func parseClock(clock string) (uint16, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, ErrInvalidQuietHours
+	}
+	return uint16(t.Hour()*60 + t.Minute()), nil
+}
+
+// IsEnabled ...
+func (q QuietHours) IsEnabled() bool {
+	return q.Start != q.End
+}
+
+// StartClock returns HH:MM formatted start
+func (q QuietHours) StartClock() string {
+	return fmt.Sprintf("%02d:%02d", q.Start/60, q.Start%60)
+}
+
+// EndClock returns HH:MM formatted end
+func (q QuietHours) EndClock() string {
+	return fmt.Sprintf("%02d:%02d", q.End/60, q.End%60)
+}
+
+// Until returns the end of quiet hours, if t is in quiet hours. t must be in member's location.
+func (q QuietHours) Until(t time.Time) (time.Time, bool) {
+	if !q.IsEnabled() {
+		return time.Time{}, false
+	}
+	minute := uint16(t.Hour()*60 + t.Minute())
+	endOn := func(day int) time.Time {
+		return time.Date(t.Year(), t.Month(), day, int(q.End/60), int(q.End%60), 0, 0, t.Location())
+	}
+	if q.Start < q.End {
+		if q.Start <= minute && minute < q.End {
+			return endOn(t.Day()), true
+		}
+		return time.Time{}, false
+	}
+	// the window passes midnight
+	switch {
+	case minute >= q.Start:
+		return endOn(t.Day() + 1), true
+	case minute < q.End:
+		return endOn(t.Day()), true
+	default:
+		return time.Time{}, false
+	}
+}
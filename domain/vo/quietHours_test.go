@@ -0,0 +1,73 @@
+package vo
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // member time zones are loaded without system tzdata
+)
+
+func TestParseQuietHours(t *testing.T) {
+	tests := []struct {
+		start, end string
+		want       QuietHours
+		wantErr    error
+	}{
+		{"23:00", "08:00", QuietHours{Start: 23 * 60, End: 8 * 60}, nil},
+		{"00:00", "00:00", QuietHours{}, nil},
+		{"13:30", "14:05", QuietHours{Start: 13*60 + 30, End: 14*60 + 5}, nil},
+		{"24:00", "08:00", QuietHours{}, ErrInvalidQuietHours},
+		{"23:00", "8", QuietHours{}, ErrInvalidQuietHours},
+	}
+	for _, tt := range tests {
+		t.Run(tt.start+"~"+tt.end, func(t *testing.T) {
+			got, err := ParseQuietHours(tt.start, tt.end)
+			if got != tt.want || err != tt.wantErr {
+				t.Fatalf("ParseQuietHours() = %+v, %v, want %+v, %v", got, err, tt.want, tt.wantErr)
+			}
+			if err == nil && (got.StartClock() != tt.start || got.EndClock() != tt.end) {
+				t.Errorf("clocks = %s ~ %s, want %s ~ %s", got.StartClock(), got.EndClock(), tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestQuietHoursUntil(t *testing.T) {
+	seoul, err := time.LoadLocation("Asia/Seoul")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	overnight := QuietHours{Start: 23 * 60, End: 8 * 60}
+	daytime := QuietHours{Start: 13 * 60, End: 14*60 + 30}
+
+	tests := []struct {
+		name   string
+		q      QuietHours
+		t      time.Time
+		want   time.Time
+		wantOK bool
+	}{
+		{"disabled", QuietHours{Start: 60, End: 60}, time.Date(2022, 3, 1, 1, 0, 0, 0, seoul), time.Time{}, false},
+		{"daytime, before", daytime, time.Date(2022, 3, 1, 12, 59, 0, 0, seoul), time.Time{}, false},
+		{"daytime, start", daytime, time.Date(2022, 3, 1, 13, 0, 0, 0, seoul), time.Date(2022, 3, 1, 14, 30, 0, 0, seoul), true},
+		{"daytime, end is not quiet", daytime, time.Date(2022, 3, 1, 14, 30, 0, 0, seoul), time.Time{}, false},
+		{"overnight, before midnight", overnight, time.Date(2022, 3, 1, 23, 30, 0, 0, seoul), time.Date(2022, 3, 2, 8, 0, 0, 0, seoul), true},
+		{"overnight, after midnight", overnight, time.Date(2022, 3, 2, 7, 59, 0, 0, seoul), time.Date(2022, 3, 2, 8, 0, 0, 0, seoul), true},
+		{"overnight, not quiet", overnight, time.Date(2022, 3, 2, 8, 0, 0, 0, seoul), time.Time{}, false},
+		{"overnight, end of month", overnight, time.Date(2022, 2, 28, 23, 0, 0, 0, seoul), time.Date(2022, 3, 1, 8, 0, 0, 0, seoul), true},
+		{"overnight, end of year", overnight, time.Date(2022, 12, 31, 23, 59, 0, 0, seoul), time.Date(2023, 1, 1, 8, 0, 0, 0, seoul), true},
+		// daylight saving time starts at 2022-03-13 02:00 in new york. the end is still 08:00 of local time.
+		{"overnight, daylight saving time", overnight, time.Date(2022, 3, 12, 23, 0, 0, 0, newYork), time.Date(2022, 3, 13, 8, 0, 0, 0, newYork), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.q.Until(tt.t)
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Errorf("Until(%s) = %s, %t, want %s, %t", tt.t, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package vo
+
+import "time"
+
+// ReconcileReason is why a task is repaired by reconciler
+type ReconcileReason string
+
+const (
+	// ReconcileMissing : expected task is neither scheduled nor executed
+	ReconcileMissing ReconcileReason = "MISSING"
+	// ReconcileStale : scheduled task has different turn or schedule time with expected one
+	ReconcileStale ReconcileReason = "STALE"
+	// ReconcileOrphan : scheduled task of a room or member which does not exist anymore
+	ReconcileOrphan ReconcileReason = "ORPHAN"
+)
+
+// ReconcileItem is a task which reconciler recreates or deletes
+type ReconcileItem struct {
+	TaskID      string          `json:"taskId"`
+	RoomID      uint            `json:"roomId"`
+	Code        TaskCode        `json:"code"`
+	Reason      ReconcileReason `json:"reason"`
+	ScheduledAt *time.Time      `json:"scheduledAt,omitempty"`
+}
+
+// ReconcileReport is a result of reconciliation.
+// If DryRun is true, items are only reported and nothing is changed.
+type ReconcileReport struct {
+	DryRun     bool            `json:"dryRun"`
+	StartedAt  time.Time       `json:"startedAt"`
+	FinishedAt time.Time       `json:"finishedAt"`
+	Rooms      uint            `json:"rooms"`
+	Items      []ReconcileItem `json:"items"`
+}
+
+// Count returns number of items of the reason
+func (r *ReconcileReport) Count(reason ReconcileReason) (count int) {
+	for _, item := range r.Items {
+		if item.Reason == reason {
+			count++
+		}
+	}
+	return
+}
@@ -3,13 +3,23 @@ package vo
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // TaskVO ...
+// Turn and DueAt are the room's turn which the task is scheduled on. (zero if unknown)
+// AlarmID is only used by DEFERRED_PUSH task, and SwapRequestID by SWAP_REQUEST_EXPIRE task.
 type TaskVO struct {
-	RoomID uint
-	Email  string
-	Code   TaskCode
+	RoomID        uint       `json:"room_id"`
+	Email         string     `json:"email"`
+	Code          TaskCode   `json:"code"`
+	Turn          uint       `json:"turn,omitempty"`
+	DueAt         *time.Time `json:"due_at,omitempty"`
+	AlarmID       uint       `json:"alarm_id,omitempty"`
+	SwapRequestID uint       `json:"swap_request_id,omitempty"`
 }
 
 // TaskCode ...
@@ -20,14 +30,97 @@ const (
 	RoomPeriodFinCode TaskCode = "ROOM_PERIOD_FIN"
 	// MemberOnDutyCode task code type
 	MemberOnDutyCode = "MEMBER_ON_DUTY"
-	// MemberBefore1HRCode task code type
+	// MemberBefore1HRCode task code type (reminder of 1 hour offset, see MemberBeforeCode)
 	MemberBefore1HRCode = "MEMBER_BEFORE_1HR"
-	// MemberBefore4HRCode task code type
+	// MemberBefore4HRCode task code type (reminder of 4 hour offset, see MemberBeforeCode)
 	MemberBefore4HRCode = "MEMBER_BEFORE_4HR"
 	// MemberPostedDiaryCode task code type
 	MemberPostedDiaryCode = "MEMBER_POSTED_DIARY"
+	// MemberReactedDiaryCode alarm code type (pushed right away, not registered as a task)
+	MemberReactedDiaryCode = "MEMBER_REACTED_DIARY"
+	// MemberCommentedDiaryCode alarm code type (pushed right away, not registered as a task)
+	MemberCommentedDiaryCode = "MEMBER_COMMENTED_DIARY"
+	// ExportReadyCode alarm code type (pushed when export archive is uploaded)
+	ExportReadyCode = "EXPORT_READY"
+	// DeferredPushCode task code type (pushes an alarm which was deferred by member's quiet hours)
+	DeferredPushCode = "DEFERRED_PUSH"
+	// SwapRequestExpireCode task code type (expires an unanswered turn swap request)
+	SwapRequestExpireCode = "SWAP_REQUEST_EXPIRE"
+	// SwapRequestedCode alarm code type (pushed to the member who is asked to swap)
+	SwapRequestedCode = "SWAP_REQUESTED"
+	// SwapAcceptedCode alarm code type (pushed to the requester)
+	SwapAcceptedCode = "SWAP_ACCEPTED"
+	// SwapDeclinedCode alarm code type (pushed to the requester)
+	SwapDeclinedCode = "SWAP_DECLINED"
+	// SwapExpiredCode alarm code type (pushed to the requester)
+	SwapExpiredCode = "SWAP_EXPIRED"
+	// RoomEndCode task code type (archives a room which reaches its end date)
+	RoomEndCode = "ROOM_END"
+	// RoomJoinLockedCode alarm code type (pushed to the master when a member is locked out by wrong room codes)
+	RoomJoinLockedCode = "ROOM_JOIN_LOCKED"
 )
 
+const memberBeforeCodePrefix = "MEMBER_BEFORE_"
+
+// TaskCodes are codes of tasks which are registered on scheduler, except reminders.
+// Reminder codes are generated from room's reminder offsets. (see MemberBeforeCode)
+var TaskCodes = []TaskCode{
+	RoomPeriodFinCode,
+	MemberOnDutyCode,
+	MemberPostedDiaryCode,
+}
+
+// MemberBeforeCode returns reminder task code of the offset.
+// ex) 1h -> MEMBER_BEFORE_1HR, 24h -> MEMBER_BEFORE_24HR, 30m -> MEMBER_BEFORE_30MIN
+func MemberBeforeCode(offset time.Duration) TaskCode {
+	if offset%time.Hour == 0 {
+		return TaskCode(fmt.Sprintf("%s%dHR", memberBeforeCodePrefix, offset/time.Hour))
+	}
+	return TaskCode(fmt.Sprintf("%s%dMIN", memberBeforeCodePrefix, offset/time.Minute))
+}
+
+// ReminderOffset returns the offset of reminder task code. ok is false if code is not a reminder.
+func (c TaskCode) ReminderOffset() (offset time.Duration, ok bool) {
+	if !strings.HasPrefix(string(c), memberBeforeCodePrefix) {
+		return 0, false
+	}
+	amount := strings.TrimPrefix(string(c), memberBeforeCodePrefix)
+	unit := time.Hour
+	switch {
+	case strings.HasSuffix(amount, "HR"):
+		amount = strings.TrimSuffix(amount, "HR")
+	case strings.HasSuffix(amount, "MIN"):
+		amount, unit = strings.TrimSuffix(amount, "MIN"), time.Minute
+	default:
+		return 0, false
+	}
+	n, err := strconv.ParseUint(amount, 10, 32)
+	if err != nil || n == 0 {
+		return 0, false
+	}
+	return time.Duration(n) * unit, true
+}
+
+// IsUrgent returns whether the alarm of the code must be pushed even in quiet hours.
+// Reminders are urgent, because deferred reminders may arrive after the due time.
+func (c TaskCode) IsUrgent() bool {
+	_, ok := c.ReminderOffset()
+	return ok
+}
+
+// IsScheduled returns whether the code is a code of task which is registered on scheduler.
+func (c TaskCode) IsScheduled() bool {
+	if _, ok := c.ReminderOffset(); ok {
+		return true
+	}
+	for _, code := range TaskCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
 // NewTaskVO ...
 func NewTaskVO(roomID uint, email string, code TaskCode) TaskVO {
 	return TaskVO{
@@ -37,6 +130,22 @@ func NewTaskVO(roomID uint, email string, code TaskCode) TaskVO {
 	}
 }
 
+// OnTurn returns a copy of task which is scheduled on the turn.
+func (tv TaskVO) OnTurn(turn uint, dueAt *time.Time) TaskVO {
+	tv.Turn = turn
+	tv.DueAt = dueAt
+	return tv
+}
+
+// Fingerprint identifies the turn which the task is scheduled on.
+func (tv TaskVO) Fingerprint() string {
+	var dueAt int64
+	if tv.DueAt != nil {
+		dueAt = tv.DueAt.Unix()
+	}
+	return fmt.Sprintf("turn:%d/due:%d", tv.Turn, dueAt)
+}
+
 // Encode converts ValueObject to []byte
 func (tv TaskVO) Encode() []byte {
 	reqBodyBytes := new(bytes.Buffer)
@@ -0,0 +1,80 @@
+package vo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemberBeforeCode(t *testing.T) {
+	tests := []struct {
+		offset time.Duration
+		want   TaskCode
+	}{
+		{time.Hour, "MEMBER_BEFORE_1HR"},
+		{time.Hour * 24, "MEMBER_BEFORE_24HR"},
+		{time.Minute * 30, "MEMBER_BEFORE_30MIN"},
+		{time.Minute * 90, "MEMBER_BEFORE_90MIN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.offset.String(), func(t *testing.T) {
+			code := MemberBeforeCode(tt.offset)
+			if code != tt.want {
+				t.Fatalf("MemberBeforeCode(%s) = %s, want %s", tt.offset, code, tt.want)
+			}
+			if offset, ok := code.ReminderOffset(); !ok || offset != tt.offset {
+				t.Errorf("%s.ReminderOffset() = %s, %t, want %s, true", code, offset, ok, tt.offset)
+			}
+		})
+	}
+}
+
+func TestTaskCodeReminderOffset(t *testing.T) {
+	tests := []struct {
+		code   TaskCode
+		want   time.Duration
+		wantOK bool
+	}{
+		{"MEMBER_BEFORE_1HR", time.Hour, true},
+		{"MEMBER_BEFORE_30MIN", time.Minute * 30, true},
+		{MemberOnDutyCode, 0, false},
+		{RoomPeriodFinCode, 0, false},
+		{"MEMBER_BEFORE_", 0, false},
+		{"MEMBER_BEFORE_HR", 0, false},
+		{"MEMBER_BEFORE_0HR", 0, false},
+		{"MEMBER_BEFORE_-1HR", 0, false},
+		{"MEMBER_BEFORE_1DAY", 0, false},
+		{"MEMBER_BEFORE_1.5HR", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			got, ok := tt.code.ReminderOffset()
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ReminderOffset() = %s, %t, want %s, %t", got, ok, tt.want, tt.wantOK)
+			}
+			if tt.code.IsUrgent() != tt.wantOK {
+				t.Errorf("IsUrgent() = %t, want %t", tt.code.IsUrgent(), tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestTaskCodeIsScheduled(t *testing.T) {
+	tests := []struct {
+		code TaskCode
+		want bool
+	}{
+		{RoomPeriodFinCode, true},
+		{MemberOnDutyCode, true},
+		{MemberPostedDiaryCode, true},
+		{MemberBeforeCode(time.Hour * 2), true},
+		{MemberReactedDiaryCode, false},
+		{ExportReadyCode, false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			if got := tt.code.IsScheduled(); got != tt.want {
+				t.Errorf("IsScheduled() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
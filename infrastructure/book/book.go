@@ -0,0 +1,93 @@
+// Package book renders a room's diaries into a printable document.
+// Every renderer is written in pure go, so that it runs without any external service.
+package book
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Format is an output format of a book
+type Format string
+
+const (
+	// HTML is a self-contained html document. Photos and QR codes are embedded as data uri.
+	HTML Format = "html"
+	// PDF is an A4 pdf document.
+	PDF Format = "pdf"
+)
+
+// ParseFormat ...
+func ParseFormat(format string) (Format, error) {
+	switch Format(format) {
+	case HTML, PDF:
+		return Format(format), nil
+	case "":
+		return PDF, nil
+	default:
+		return "", errors.Errorf("'%s' is invalid book format", format)
+	}
+}
+
+// ContentType returns mime type of the format
+func (f Format) ContentType() string {
+	if f == HTML {
+		return "text/html; charset=utf-8"
+	}
+	return "application/pdf"
+}
+
+// Book is a printable document of a room's diaries.
+type Book struct {
+	Title    string
+	Theme    string
+	Contents []Chapter // table of contents by author
+	Pages    []Page    // diaries in turn order
+}
+
+// Chapter is an author's diaries in the table of contents
+type Chapter struct {
+	Author string
+	Turns  []uint
+}
+
+// Page is a diary of the book
+type Page struct {
+	Turn       uint
+	Title      string
+	Author     string
+	Body       string
+	Photo      []byte // raw jpeg, png or gif
+	AudioTitle string
+	AudioURL   string // encoded into QR code
+	CreatedAt  *time.Time
+}
+
+// Anchor is an html id of the page
+func (p *Page) Anchor() string {
+	return fmt.Sprintf("diary-%d", p.Turn)
+}
+
+// Page returns page of the turn
+func (b *Book) Page(turn uint) *Page {
+	for i := range b.Pages {
+		if b.Pages[i].Turn == turn {
+			return &b.Pages[i]
+		}
+	}
+	return nil
+}
+
+// Render renders book in the format
+func Render(b *Book, format Format) ([]byte, error) {
+	switch format {
+	case HTML:
+		return RenderHTML(b)
+	case PDF:
+		return RenderPDF(b)
+	default:
+		return nil, errors.Errorf("'%s' is invalid book format", format)
+	}
+}
@@ -0,0 +1,88 @@
+package book
+
+import (
+	"bytes"
+	"encoding/base64"
+	"html/template"
+	"net/http"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const qrCodePixelSize = 256
+
+var htmlTemplate = template.Must(template.New("book").Funcs(template.FuncMap{
+	"photo":  photoDataURI,
+	"qrcode": qrCodeDataURI,
+	"date": func(p Page) string {
+		if p.CreatedAt == nil {
+			return ""
+		}
+		return p.CreatedAt.Format("2006.01.02")
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="ko">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+@page { size: A4; margin: 20mm; }
+body { font-family: "Noto Sans KR", "Apple SD Gothic Neo", "Malgun Gothic", sans-serif; color: #222; line-height: 1.7; }
+section, article { page-break-after: always; }
+.cover { text-align: center; padding-top: 35%; }
+.cover h1 { font-size: 32pt; margin: 0; }
+.cover p { font-size: 14pt; color: #666; }
+.toc a { color: inherit; text-decoration: none; }
+.toc ol { list-style: none; padding-left: 1em; }
+article h2 { margin-bottom: 0; }
+.meta { color: #888; font-size: 9pt; }
+.photo { display: block; max-width: 100%; max-height: 110mm; margin: 1em auto; }
+.body { white-space: pre-wrap; }
+.audio { display: flex; align-items: center; gap: 1em; margin-top: 2em; font-size: 9pt; color: #666; }
+.audio img { width: 28mm; height: 28mm; }
+</style>
+</head>
+<body>
+<section class="cover">
+<h1>{{.Title}}</h1>
+{{if .Theme}}<p>{{.Theme}}</p>{{end}}
+</section>
+<section class="toc">
+<h2>목차</h2>
+{{range .Contents}}{{$book := $}}<h3>{{.Author}}</h3>
+<ol>{{range .Turns}}{{with $book.Page .}}<li><a href="#{{.Anchor}}">{{.Turn}}. {{.Title}}</a></li>{{end}}{{end}}</ol>
+{{end}}</section>
+{{range $page := .Pages}}<article id="{{.Anchor}}">
+<h2>{{.Title}}</h2>
+<p class="meta">{{.Author}} · {{date .}} · {{.Turn}}번째 일기</p>
+{{with photo .Photo}}<img class="photo" src="{{.}}" alt="photo">{{end}}
+<div class="body">{{.Body}}</div>
+{{if .AudioURL}}{{with qrcode .AudioURL}}<div class="audio"><img src="{{.}}" alt="audio qrcode"><span>음성: {{$page.AudioTitle}}</span></div>{{end}}{{end}}
+</article>
+{{end}}</body>
+</html>
+`))
+
+// RenderHTML renders book into a self-contained html document.
+func RenderHTML(b *Book) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, b); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func photoDataURI(photo []byte) template.URL {
+	if len(photo) == 0 {
+		return ""
+	}
+	return template.URL("data:" + http.DetectContentType(photo) + ";base64," + base64.StdEncoding.EncodeToString(photo))
+}
+
+func qrCodeDataURI(content string) template.URL {
+	png, err := qrcode.Encode(content, qrcode.Medium, qrCodePixelSize)
+	if err != nil {
+		return ""
+	}
+	return template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(png))
+}
@@ -0,0 +1,409 @@
+package book
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"strings"
+	"unicode/utf8"
+
+	// register image decoders for photos
+	_ "image/gif"
+	_ "image/png"
+
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// A4 in points
+const (
+	pageWidth    = 595.28
+	pageHeight   = 841.89
+	margin       = 56.0
+	contentWidth = pageWidth - margin*2
+
+	lineHeight     = 1.7
+	titleFontSize  = 18.0
+	bodyFontSize   = 11.0
+	metaFontSize   = 9.0
+	maxPhotoHeight = 300.0
+	qrCodeSize     = 80.0
+)
+
+// Korean font is not embedded. HYGoThic-Medium is one of the predefined CJK fonts
+// which every pdf viewer provides, and UniKS-UCS2-H maps UCS-2 code to its glyphs.
+const pdfFont = `<< /Type /Font /Subtype /Type0 /BaseFont /HYGoThic-Medium /Encoding /UniKS-UCS2-H /DescendantFonts [4 0 R] >>`
+
+const pdfCIDFont = `<< /Type /Font /Subtype /CIDFontType0 /BaseFont /HYGoThic-Medium ` +
+	`/CIDSystemInfo << /Registry (Adobe) /Ordering (Korea1) /Supplement 2 >> ` +
+	`/FontDescriptor 5 0 R /DW 1000 /W [1 95 500] >>`
+
+const pdfFontDescriptor = `<< /Type /FontDescriptor /FontName /HYGoThic-Medium /Flags 6 ` +
+	`/FontBBox [-6 -145 1003 880] /ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>`
+
+// fixed object numbers: 1 catalog, 2 pages, 3~5 font
+const firstImageObject = 6
+
+type pdfImage struct {
+	name       string
+	width      int
+	height     int
+	colorSpace string
+	data       []byte // jpeg
+}
+
+type pdfPage struct {
+	content bytes.Buffer
+}
+
+type pdfWriter struct {
+	pages  []*pdfPage
+	images []*pdfImage
+	y      float64 // cursor of current page, from bottom
+}
+
+// RenderPDF renders book into an A4 pdf document.
+// It consists of cover, table of contents and a diary per page.
+func RenderPDF(b *Book) ([]byte, error) {
+	cover := &pdfWriter{}
+	cover.writeCover(b)
+
+	diaries := &pdfWriter{}
+	startPages := map[uint]int{}
+	for i := range b.Pages {
+		startPages[b.Pages[i].Turn] = len(diaries.pages)
+		if err := diaries.writeDiary(&b.Pages[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	// page numbers of toc depend on the number of toc pages
+	toc := &pdfWriter{}
+	toc.writeContents(b, func(turn uint) int { return 0 })
+	offset := len(cover.pages) + len(toc.pages) + 1
+	toc = &pdfWriter{}
+	toc.writeContents(b, func(turn uint) int { return startPages[turn] + offset })
+
+	doc := &pdfWriter{images: diaries.images}
+	doc.pages = append(doc.pages, cover.pages...)
+	doc.pages = append(doc.pages, toc.pages...)
+	doc.pages = append(doc.pages, diaries.pages...)
+	for i, page := range doc.pages[1:] {
+		number := fmt.Sprint(i + 2)
+		writeText(&page.content, (pageWidth-textWidth(number, metaFontSize))/2, margin/2, metaFontSize, 0.5, number)
+	}
+	return doc.bytes()
+}
+
+func (w *pdfWriter) newPage() {
+	w.pages = append(w.pages, &pdfPage{})
+	w.y = pageHeight - margin
+}
+
+func (w *pdfWriter) page() *pdfPage {
+	return w.pages[len(w.pages)-1]
+}
+
+// ensure starts a new page if current page does not have the height of space.
+func (w *pdfWriter) ensure(height float64) {
+	if w.y-height < margin {
+		w.newPage()
+	}
+}
+
+func (w *pdfWriter) paragraph(s string, size, gray float64) {
+	for _, line := range wrap(s, size, contentWidth) {
+		w.ensure(size * lineHeight)
+		w.y -= size * lineHeight
+		writeText(&w.page().content, margin, w.y, size, gray, line)
+	}
+}
+
+func (w *pdfWriter) writeCover(b *Book) {
+	w.newPage()
+	writeText(&w.page().content, (pageWidth-textWidth(b.Title, 28))/2, pageHeight*0.6, 28, 0, b.Title)
+	if b.Theme != "" {
+		writeText(&w.page().content, (pageWidth-textWidth(b.Theme, 14))/2, pageHeight*0.6-40, 14, 0.4, b.Theme)
+	}
+}
+
+func (w *pdfWriter) writeContents(b *Book, pageOf func(turn uint) int) {
+	w.newPage()
+	w.paragraph("목차", titleFontSize, 0)
+	w.y -= bodyFontSize
+	for _, chapter := range b.Contents {
+		w.ensure(bodyFontSize * lineHeight * 3)
+		w.y -= bodyFontSize
+		w.paragraph(chapter.Author, 14, 0)
+		for _, turn := range chapter.Turns {
+			page := b.Page(turn)
+			if page == nil {
+				continue
+			}
+			number := fmt.Sprint(pageOf(turn))
+			title := truncate(fmt.Sprintf("%d. %s", page.Turn, page.Title), bodyFontSize, contentWidth-60)
+
+			w.ensure(bodyFontSize * lineHeight)
+			w.y -= bodyFontSize * lineHeight
+			writeText(&w.page().content, margin+12, w.y, bodyFontSize, 0.2, title)
+			writeText(&w.page().content, pageWidth-margin-textWidth(number, bodyFontSize), w.y, bodyFontSize, 0.2, number)
+		}
+	}
+}
+
+func (w *pdfWriter) writeDiary(p *Page) error {
+	w.newPage()
+	w.paragraph(p.Title, titleFontSize, 0)
+	meta := fmt.Sprintf("%s · %d번째 일기", p.Author, p.Turn)
+	if p.CreatedAt != nil {
+		meta = fmt.Sprintf("%s · %s · %d번째 일기", p.Author, p.CreatedAt.Format("2006.01.02"), p.Turn)
+	}
+	w.paragraph(meta, metaFontSize, 0.5)
+	w.y -= bodyFontSize
+
+	if len(p.Photo) > 0 {
+		// a broken photo should not fail the whole book, so the page is written without it.
+		if img, err := w.addImage(p.Photo); err != nil {
+			logger.Error(fmt.Sprintf("book: skip photo of turn %d. %s", p.Turn, err.Error()))
+		} else {
+			width, height := fit(float64(img.width), float64(img.height), contentWidth, maxPhotoHeight)
+			w.ensure(height)
+			w.y -= height
+			fmt.Fprintf(&w.page().content, "q %.2f 0 0 %.2f %.2f %.2f cm /%s Do Q\n", width, height, margin+(contentWidth-width)/2, w.y, img.name)
+			w.y -= bodyFontSize
+		}
+	}
+
+	w.paragraph(p.Body, bodyFontSize, 0.1)
+
+	if p.AudioURL != "" {
+		w.ensure(qrCodeSize + bodyFontSize*2)
+		w.y -= qrCodeSize + bodyFontSize*2
+		if err := writeQRCode(&w.page().content, margin, w.y, qrCodeSize, p.AudioURL); err != nil {
+			return err
+		}
+		label := truncate("음성: "+p.AudioTitle, metaFontSize, contentWidth-qrCodeSize-12)
+		writeText(&w.page().content, margin+qrCodeSize+12, w.y+qrCodeSize/2, metaFontSize, 0.4, label)
+	}
+	return nil
+}
+
+// addImage registers a photo as jpeg XObject. Non-jpeg photo is re-encoded into jpeg.
+func (w *pdfWriter) addImage(raw []byte) (*pdfImage, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	img := &pdfImage{
+		name:       fmt.Sprintf("Im%d", len(w.images)+1),
+		width:      cfg.Width,
+		height:     cfg.Height,
+		colorSpace: "/DeviceRGB",
+		data:       raw,
+	}
+	switch {
+	case format == "jpeg" && cfg.ColorModel == color.YCbCrModel:
+	case format == "jpeg" && cfg.ColorModel == color.GrayModel:
+		img.colorSpace = "/DeviceGray"
+	default:
+		decoded, _, err := image.Decode(bytes.NewReader(raw))
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, decoded, &jpeg.Options{Quality: 90}); err != nil {
+			return nil, err
+		}
+		img.data = buf.Bytes()
+	}
+	w.images = append(w.images, img)
+	return img, nil
+}
+
+// bytes serializes the document
+func (w *pdfWriter) bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	offsets := []int{}
+	writeObject := func(dict string, stream []byte) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\n", len(offsets), dict)
+		if stream != nil {
+			buf.WriteString("stream\n")
+			buf.Write(stream)
+			buf.WriteString("\nendstream\n")
+		}
+		buf.WriteString("endobj\n")
+	}
+
+	firstPageObject := firstImageObject + len(w.images)
+	kids := []string{}
+	for i := range w.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", firstPageObject+i*2))
+	}
+	xobjects := []string{}
+	for i, img := range w.images {
+		xobjects = append(xobjects, fmt.Sprintf("/%s %d 0 R", img.name, firstImageObject+i))
+	}
+	resources := fmt.Sprintf("<< /Font << /F1 3 0 R >> /XObject << %s >> >>", strings.Join(xobjects, " "))
+
+	buf.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+	writeObject("<< /Type /Catalog /Pages 2 0 R >>", nil)
+	writeObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(w.pages)), nil)
+	writeObject(pdfFont, nil)
+	writeObject(pdfCIDFont, nil)
+	writeObject(pdfFontDescriptor, nil)
+	for _, img := range w.images {
+		writeObject(fmt.Sprintf(
+			"<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace %s /BitsPerComponent 8 /Filter /DCTDecode /Length %d >>",
+			img.width, img.height, img.colorSpace, len(img.data),
+		), img.data)
+	}
+	for i, page := range w.pages {
+		writeObject(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources %s /Contents %d 0 R >>",
+			pageWidth, pageHeight, resources, firstPageObject+i*2+1,
+		), nil)
+		content, err := deflate(page.content.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		writeObject(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>", len(content)), content)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.Bytes(), nil
+}
+
+func deflate(b []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	if _, err := zw.Write(b); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeText(content *bytes.Buffer, x, y, size, gray float64, s string) {
+	fmt.Fprintf(content, "BT %.2f g /F1 %.2f Tf %.2f %.2f Td <%s> Tj ET\n", gray, size, x, y, encodeUCS2(s))
+}
+
+// writeQRCode draws QR code modules as filled rectangles. (x, y) is a bottom-left corner.
+func writeQRCode(content *bytes.Buffer, x, y, size float64, url string) error {
+	qr, err := qrcode.New(url, qrcode.Medium)
+	if err != nil {
+		return err
+	}
+	bitmap := qr.Bitmap()
+	module := size / float64(len(bitmap))
+	content.WriteString("q 0 g\n")
+	for row, modules := range bitmap {
+		for col, dark := range modules {
+			if dark {
+				fmt.Fprintf(content, "%.2f %.2f %.2f %.2f re\n", x+float64(col)*module, y+size-float64(row+1)*module, module, module)
+			}
+		}
+	}
+	content.WriteString("f Q\n")
+	return nil
+}
+
+// encodeUCS2 encodes text into hex string of UCS-2 big endian.
+// Characters out of BMP are replaced with '?'.
+func encodeUCS2(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			r = ' '
+		case r > 0xFFFF, r < ' ':
+			r = '?'
+		}
+		fmt.Fprintf(&b, "%04X", r)
+	}
+	return b.String()
+}
+
+// runeWidth is a width of glyph in em. ascii glyphs are half width. (see /W of pdfCIDFont)
+func runeWidth(r rune) float64 {
+	if r < utf8.RuneSelf {
+		return 0.5
+	}
+	return 1
+}
+
+func textWidth(s string, size float64) float64 {
+	width := 0.0
+	for _, r := range s {
+		width += runeWidth(r)
+	}
+	return width * size
+}
+
+// wrap breaks text into lines which fit in the width.
+// ascii words are kept in a line if possible, while korean is broken per character.
+func wrap(s string, size, width float64) []string {
+	lines := []string{}
+	for _, paragraph := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		line := []rune{}
+		lineWidth := 0.0
+		lastSpace := -1
+		for _, r := range paragraph {
+			w := runeWidth(r) * size
+			if lineWidth+w > width && len(line) > 0 {
+				if r != ' ' && lastSpace > 0 && runeWidth(line[len(line)-1]) < 1 {
+					lines = append(lines, string(line[:lastSpace]))
+					line = append([]rune{}, line[lastSpace+1:]...)
+				} else {
+					lines = append(lines, string(line))
+					line = []rune{}
+				}
+				lineWidth = textWidth(string(line), size)
+				lastSpace = -1
+				if r == ' ' {
+					continue
+				}
+			}
+			if r == ' ' {
+				lastSpace = len(line)
+			}
+			line = append(line, r)
+			lineWidth += w
+		}
+		lines = append(lines, string(line))
+	}
+	return lines
+}
+
+func truncate(s string, size, width float64) string {
+	if textWidth(s, size) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && textWidth(string(runes)+"...", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// fit scales (width, height) down to the box keeping its ratio.
+func fit(width, height, maxWidth, maxHeight float64) (float64, float64) {
+	scale := 1.0
+	if width > maxWidth {
+		scale = maxWidth / width
+	}
+	if height*scale > maxHeight {
+		scale = maxHeight / height
+	}
+	return width * scale, height * scale
+}
@@ -0,0 +1,49 @@
+package book
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+)
+
+func encodePNG(t *testing.T) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 3))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRenderPDFPhotos(t *testing.T) {
+	valid := encodePNG(t)
+	tests := []struct {
+		name       string
+		photos     [][]byte
+		wantImages int
+	}{
+		{"no photo", [][]byte{nil}, 0},
+		{"photo", [][]byte{valid}, 1},
+		{"undecodable photo is skipped", [][]byte{[]byte("not an image")}, 0},
+		{"truncated photo is skipped", [][]byte{valid[:len(valid)/2]}, 0},
+		{"only broken photo is skipped", [][]byte{valid, []byte("not an image"), valid}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &Book{Title: "교환일기", Contents: []Chapter{{Author: "voda"}}}
+			for i, photo := range tt.photos {
+				turn := uint(i + 1)
+				b.Contents[0].Turns = append(b.Contents[0].Turns, turn)
+				b.Pages = append(b.Pages, Page{Turn: turn, Title: "title", Author: "voda", Body: "body", Photo: photo})
+			}
+
+			doc, err := RenderPDF(b)
+			if err != nil {
+				t.Fatalf("RenderPDF() error = %v", err)
+			}
+			if got := bytes.Count(doc, []byte("/Subtype /Image")); got != tt.wantImages {
+				t.Errorf("images = %d, want %d", got, tt.wantImages)
+			}
+		})
+	}
+}
@@ -33,7 +33,9 @@ type Client struct {
 	ctx    context.Context
 }
 
-func init() {
+// load initializes the client only once, on the first GetClient call.
+// (not on package init, so that packages importing it can be tested without credentials)
+func load() {
 	logger.Info("init firebase alarm client")
 	clientOnce.Do(func() {
 		var app *fb.App
@@ -67,6 +69,7 @@ func init() {
 
 // GetClient ...
 func GetClient() *Client {
+	load()
 	return firebaseClient
 }
 
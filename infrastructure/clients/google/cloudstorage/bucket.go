@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strconv"
 
 	"cloud.google.com/go/storage"
 	"github.com/pkg/errors"
 )
 
+const lockedMetadataKey = "locked"
+
 // VBucket is Voda Bucket which is wrapper of google cloud storage "Bucket"
 type VBucket struct {
 	// Name is needed to retrieve items.
@@ -50,14 +53,70 @@ func (vb *VBucket) VItem(id string) (*VItem, error) {
 }
 
 // RemoveItem will delete a google storage Object
+// locked object cannot be removed.
 func (vb *VBucket) RemoveItem(id string) error {
+	if err := vb.checkNotLocked(id); err != nil {
+		return err
+	}
 	return vb.Bucket().Object(id).Delete(vb.ctx)
 }
 
+// Lock puts temporary hold on the object, so it cannot be overwritten or deleted.
+// It returns locked item to get content hash of the object.
+func (vb *VBucket) Lock(id string) (*VItem, error) {
+	return vb.updateLock(id, true)
+}
+
+// Unlock releases temporary hold of the object.
+func (vb *VBucket) Unlock(id string) (*VItem, error) {
+	return vb.updateLock(id, false)
+}
+
+func (vb *VBucket) updateLock(id string, locked bool) (*VItem, error) {
+	item, err := vb.VItem(id)
+	if err != nil {
+		return nil, err
+	}
+	metadata := item.StorageObject().Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	metadata[lockedMetadataKey] = strconv.FormatBool(locked)
+
+	attr, err := vb.Bucket().Object(id).Update(vb.ctx, storage.ObjectAttrsToUpdate{
+		TemporaryHold: locked,
+		Metadata:      metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return vb.convertToVItem(attr)
+}
+
+// checkNotLocked returns ErrLocked if the object is locked.
+// If there is no object, it returns nil.
+func (vb *VBucket) checkNotLocked(id string) error {
+	item, err := vb.VItem(id)
+	if err != nil {
+		if err == ErrNotFound {
+			return nil
+		}
+		return err
+	}
+	if item.IsLocked() {
+		return ErrLocked
+	}
+	return nil
+}
+
 // Put sends a request to upload content to the container. The arguments
 // received are the name of the item, a reader representing the
 // content, and the size of the file.
+// locked object cannot be overwritten.
 func (vb *VBucket) Put(name string, r io.Reader, size int64, metadata map[string]interface{}) (*VItem, error) {
+	if err := vb.checkNotLocked(name); err != nil {
+		return nil, err
+	}
 	obj := vb.Bucket().Object(name)
 
 	mdPrepped, err := prepMetadata(metadata)
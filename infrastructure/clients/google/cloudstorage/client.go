@@ -19,6 +19,8 @@ const Kind = "google"
 var (
 	// ErrNotFound ...
 	ErrNotFound = errors.New("google storage not found")
+	// ErrLocked is returned when a locked object is about to be overwritten or deleted.
+	ErrLocked = errors.New("google storage object is locked")
 )
 
 var (
@@ -33,8 +35,10 @@ type VClient struct {
 	ctx    context.Context
 }
 
-func init() {
-	logger.Info("lazy init cloud storage")
+// load initializes the client only once, on the first GetClient call.
+// (not on package init, so that packages importing it can be tested without credentials)
+func load() {
+	logger.Info("init cloud storage")
 	// LazyGlobal loading ...
 	clientOnce.Do(func() {
 		var client *storage.Client
@@ -61,6 +65,7 @@ func init() {
 
 // GetClient ...
 func GetClient() *VClient {
+	load()
 	return vodaStorageClient
 }
 
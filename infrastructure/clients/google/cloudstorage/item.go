@@ -2,6 +2,7 @@ package cloudstorage
 
 import (
 	"context"
+	"encoding/hex"
 	"io"
 	"net/url"
 	"time"
@@ -77,6 +78,20 @@ func (vi *VItem) ETag() (string, error) {
 	return vi.etag, nil
 }
 
+// Hash returns hex encoded md5 hash of the content.
+func (vi *VItem) Hash() string {
+	return hex.EncodeToString([]byte(vi.hash))
+}
+
+// IsLocked returns whether the object is locked by VBucket.Lock
+func (vi *VItem) IsLocked() bool {
+	if vi.object != nil && vi.object.TemporaryHold {
+		return true
+	}
+	locked, _ := vi.metadata[lockedMetadataKey].(string)
+	return locked == "true"
+}
+
 // StorageObject returns the Google Storage Object
 func (vi *VItem) StorageObject() *storage.ObjectAttrs {
 	return vi.object
@@ -0,0 +1,94 @@
+package tasks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/api/idtoken"
+)
+
+// SignatureHeader is a http header of task callback HMAC signature.
+// format: t=<unix timestamp>,v1=<hex encoded HMAC-SHA256 of "<timestamp>.<body>">
+const SignatureHeader = "X-Voda-Task-Signature"
+
+// signatureTolerance is maximum difference between signed time and now.
+// A task is signed with its scheduled time, and cloud tasks may retry it later.
+const signatureTolerance = time.Hour * 24
+
+var googleIssuers = map[string]bool{
+	"accounts.google.com":         true,
+	"https://accounts.google.com": true,
+}
+
+var (
+	// ErrInvalidSignature ...
+	ErrInvalidSignature = errors.New("invalid task signature")
+	// ErrExpiredSignature ...
+	ErrExpiredSignature = errors.New("task signature is expired")
+)
+
+// Sign returns SignatureHeader value of the body which is going to be sent at given time.
+func Sign(secret string, body []byte, at time.Time) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, signature(secret, timestamp, body))
+}
+
+// VerifySignature checks SignatureHeader value of the body.
+func VerifySignature(secret string, body []byte, header string, now time.Time) error {
+	var timestamp, sig string
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			timestamp = kv[1]
+		case "v1":
+			sig = kv[1]
+		}
+	}
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || sig == "" {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(sig), []byte(signature(secret, timestamp, body))) {
+		return ErrInvalidSignature
+	}
+	if diff := now.Sub(time.Unix(signedAt, 0)); diff > signatureTolerance || diff < -signatureTolerance {
+		return ErrExpiredSignature
+	}
+	return nil
+}
+
+func signature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyOIDC validates google signed OIDC token which cloud tasks attached.
+// Issuer, audience, expiration and the service account email are checked.
+func VerifyOIDC(ctx context.Context, token, audience, serviceAccountEmail string) error {
+	payload, err := idtoken.Validate(ctx, token, audience)
+	if err != nil {
+		return err
+	}
+	if !googleIssuers[payload.Issuer] {
+		return fmt.Errorf("invalid token issuer '%s'", payload.Issuer)
+	}
+	email, _ := payload.Claims["email"].(string)
+	verified, _ := payload.Claims["email_verified"].(bool)
+	if email != serviceAccountEmail || !verified {
+		return fmt.Errorf("token is not issued for task service account. email: '%s'", email)
+	}
+	return nil
+}
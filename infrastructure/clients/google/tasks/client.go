@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/ExchangeDiary/exchange-diary/infrastructure"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/configs"
 	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
 
 	cloudtasks "cloud.google.com/go/cloudtasks/apiv2"
@@ -31,6 +32,7 @@ type Client struct {
 	client    *cloudtasks.Client
 	ctx       context.Context
 	queuePath string
+	auth      *configs.TaskAuth
 }
 
 // TaskName returns google cloud task unique id
@@ -39,11 +41,12 @@ func (c *Client) taskName(taskID string) string {
 	return fmt.Sprintf("%s/tasks/%s", c.queuePath, taskID)
 }
 
+// GetClient returns google cloud tasks client. It is lazily initialized on first call,
+// so the server can start without credentials when local scheduler is used.
 // https://github.com/GoogleCloudPlatform/golang-samples/blob/c5b5b4be9bb51fc05a8939b163374bc23084eb56/tasks/create_http_task.go
-func init() {
-	logger.Info("lazy init google cloud task client")
-	// LazyGlobal loading ...
+func GetClient() *Client {
 	clientOnce.Do(func() {
+		logger.Info("lazy init google cloud task client")
 		var client *cloudtasks.Client
 		var err error
 		ctx := context.Background()
@@ -65,12 +68,9 @@ func init() {
 				infrastructure.Getenv("PROJECT_ID", "voda-342511"),
 				infrastructure.Getenv("LOCATION_ID", "asia-northeast3"),
 				infrastructure.Getenv("QUEUE_ID", "voda-alarm-queue")),
+			auth: configs.TaskAuthConfig(),
 		}
 	})
-}
-
-// GetClient ...
-func GetClient() *Client {
 	return vodaStorageClient
 }
 
@@ -79,17 +79,37 @@ func (c *Client) Close() {
 	c.client.Close()
 }
 
-// BuildTask ...
+// BuildTask builds a http task which is authenticated by OIDC token of task service account.
+// If service account is not set, HMAC signature header is attached instead.
 func (c *Client) BuildTask(url, taskID string, body []byte, httpMethod taskspb.HttpMethod, scheduledAt time.Time) *taskspb.Task {
-	task := &taskspb.Task{
-		Name: c.taskName(taskID),
-		MessageType: &taskspb.Task_HttpRequest{
-			HttpRequest: &taskspb.HttpRequest{
-				HttpMethod: httpMethod,
-				Url:        url,
-				Body:       body,
+	req := &taskspb.HttpRequest{
+		HttpMethod: httpMethod,
+		Url:        url,
+		Body:       body,
+	}
+	switch {
+	case c.auth.ServiceAccountEmail != "":
+		audience := c.auth.Audience
+		if audience == "" {
+			audience = url
+		}
+		req.AuthorizationHeader = &taskspb.HttpRequest_OidcToken{
+			OidcToken: &taskspb.OidcToken{
+				ServiceAccountEmail: c.auth.ServiceAccountEmail,
+				Audience:            audience,
 			},
-		},
+		}
+	case c.auth.HMACSecret != "":
+		signedAt := scheduledAt
+		if signedAt == nilTime {
+			signedAt = time.Now()
+		}
+		req.Headers = map[string]string{SignatureHeader: Sign(c.auth.HMACSecret, body, signedAt)}
+	}
+
+	task := &taskspb.Task{
+		Name:        c.taskName(taskID),
+		MessageType: &taskspb.Task_HttpRequest{HttpRequest: req},
 	}
 	// if ScheduleTime set nil, google cloud task run this task right away
 	if scheduledAt != nilTime {
@@ -112,7 +132,7 @@ func (c *Client) RegisterTask(task *taskspb.Task) (*taskspb.Task, error) {
 	}
 	registeredTask, err := c.client.CreateTask(c.ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("cloudtasks.CreateTask: %v", err)
+		return nil, fmt.Errorf("cloudtasks.CreateTask: %w", err)
 	}
 	return registeredTask, nil
 }
@@ -131,7 +151,8 @@ func (c *Client) UpdateTask(oldID string, task *taskspb.Task) (*taskspb.Task, er
 // GetTask ...
 func (c *Client) GetTask(id string) (*taskspb.Task, error) {
 	return c.client.GetTask(c.ctx, &taskspb.GetTaskRequest{
-		Name: c.taskName(id),
+		Name:         c.taskName(id),
+		ResponseView: taskspb.Task_FULL,
 	})
 }
 
@@ -0,0 +1,177 @@
+package tasks
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"google.golang.org/api/iterator"
+	taskspb "google.golang.org/genproto/googleapis/cloud/tasks/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Scheduler is a google cloud tasks adapter of domain/service/scheduler.go Scheduler interface
+// Each instance of a task is registered under its own name (task id + instance), because cloud tasks does not
+// accept the name of a deleted or executed task for about an hour. The latest name of a task id is kept in taskNameRepository.
+type Scheduler struct {
+	client             *Client
+	taskNameRepository repository.TaskNameRepository
+}
+
+// NewScheduler ...
+func NewScheduler(c *Client, tnr repository.TaskNameRepository) *Scheduler {
+	return &Scheduler{client: c, taskNameRepository: tnr}
+}
+
+// Schedule registers a http callback task which posts task.Task to task.CallbackURL.
+// The previous instance of the same task id is removed, as local scheduler replaces it.
+func (s *Scheduler) Schedule(task *entity.ScheduledTask) (*entity.ScheduledTask, error) {
+	if task.Instance == "" {
+		scheduled := *task
+		scheduled.Instance = entity.NewTaskInstance()
+		task = &scheduled
+	}
+	name := task.Name()
+	registered, err := s.client.RegisterTask(
+		s.client.BuildTask(
+			task.CallbackURL,
+			name,
+			task.Task.Encode(),
+			taskspb.HttpMethod_POST,
+			task.ScheduledAt,
+		),
+	)
+	if err != nil {
+		if grpcCode(err) != codes.AlreadyExists {
+			return nil, err
+		}
+		// the same instance is delivered twice. instance names are never reused by an other task.
+		if registered, err = s.sameInstance(task); err != nil {
+			return nil, err
+		}
+	}
+
+	previous, err := s.taskNameRepository.Get(task.ID)
+	switch {
+	case err == nil && previous != name:
+		if err := notFound(s.client.DeleteTask(previous)); err != nil && err != entity.ErrScheduledTaskNotFound {
+			return nil, err
+		}
+	case err != nil && err != entity.ErrScheduledTaskNotFound:
+		return nil, err
+	}
+	if err := s.taskNameRepository.Save(task.ID, name); err != nil {
+		return nil, err
+	}
+	if registered == nil {
+		return task, nil
+	}
+	return toScheduledTask(registered), nil
+}
+
+// sameInstance returns the registered task of the instance. (nil if it is already executed)
+// entity.ErrScheduledTaskAlreadyExists is returned if the registered one is not the same task.
+func (s *Scheduler) sameInstance(task *entity.ScheduledTask) (*taskspb.Task, error) {
+	registered, err := s.client.GetTask(task.Name())
+	if err := notFound(err); err == entity.ErrScheduledTaskNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if toScheduledTask(registered).Task.Fingerprint() != task.Task.Fingerprint() {
+		return nil, entity.ErrScheduledTaskAlreadyExists
+	}
+	return registered, nil
+}
+
+// Cancel removes the latest instance of the task id.
+func (s *Scheduler) Cancel(id string) error {
+	name, err := s.name(id)
+	if err != nil {
+		return err
+	}
+	err = notFound(s.client.DeleteTask(name))
+	if err != nil && err != entity.ErrScheduledTaskNotFound {
+		return err
+	}
+	if derr := s.taskNameRepository.Delete(id, name); derr != nil {
+		return derr
+	}
+	return err
+}
+
+// Get returns the latest instance of the task id.
+func (s *Scheduler) Get(id string) (*entity.ScheduledTask, error) {
+	name, err := s.name(id)
+	if err != nil {
+		return nil, err
+	}
+	task, err := s.client.GetTask(name)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return toScheduledTask(task), nil
+}
+
+// name returns the latest instance name of the task id.
+// Tasks which were registered before instance names are named by the task id.
+func (s *Scheduler) name(id string) (string, error) {
+	name, err := s.taskNameRepository.Get(id)
+	if err == entity.ErrScheduledTaskNotFound {
+		return id, nil
+	}
+	return name, err
+}
+
+// List returns every task in the queue.
+func (s *Scheduler) List() ([]entity.ScheduledTask, error) {
+	it := s.client.client.ListTasks(s.client.ctx, &taskspb.ListTasksRequest{
+		Parent:       s.client.queuePath,
+		ResponseView: taskspb.Task_FULL,
+	})
+	tasks := []entity.ScheduledTask{}
+	for {
+		task, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, *toScheduledTask(task))
+	}
+	return tasks, nil
+}
+
+func toScheduledTask(task *taskspb.Task) *entity.ScheduledTask {
+	scheduled := &entity.ScheduledTask{}
+	scheduled.ID, scheduled.Instance = entity.ParseTaskName(task.Name[strings.LastIndex(task.Name, "/")+1:])
+	if task.ScheduleTime != nil {
+		scheduled.ScheduledAt = task.ScheduleTime.AsTime()
+	}
+	// http request is only included in FULL view
+	if req := task.GetHttpRequest(); req != nil {
+		scheduled.CallbackURL = req.Url
+		json.Unmarshal(req.Body, &scheduled.Task)
+	}
+	return scheduled
+}
+
+func notFound(err error) error {
+	if grpcCode(err) == codes.NotFound {
+		return entity.ErrScheduledTaskNotFound
+	}
+	return err
+}
+
+// grpcCode unwraps grpc status code from a wrapped error.
+func grpcCode(err error) codes.Code {
+	var se interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &se) {
+		return se.GRPCStatus().Code()
+	}
+	return status.Code(err)
+}
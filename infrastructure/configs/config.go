@@ -1,6 +1,8 @@
 package configs
 
 import (
+	"time"
+
 	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
@@ -9,12 +11,40 @@ import (
 const (
 	typeEXT      = "yaml"
 	defaultPhase = "dev"
+
+	// LocalScheduler runs tasks with in-process workers backed by db table
+	LocalScheduler = "local"
+	// CloudTasksScheduler runs tasks with google cloud tasks http callback
+	CloudTasksScheduler = "cloudtasks"
 )
 
 // Config ...
 type Config struct {
-	DBConfig DBConfig `mapstructure:"db-config"`
-	Client   Client   `mapstructure:"client"`
+	DBConfig  DBConfig        `mapstructure:"db-config"`
+	Server    Server          `mapstructure:"server"`
+	Client    Client          `mapstructure:"client"`
+	Scheduler SchedulerConfig `mapstructure:"scheduler"`
+	TaskAuth  TaskAuth        `mapstructure:"task-auth"`
+	Admin     Admin           `mapstructure:"admin"`
+	Invite    Invite          `mapstructure:"invite"`
+	Catalog   RoomCatalog     `mapstructure:"room-catalog"`
+}
+
+// SchedulerConfig ...
+type SchedulerConfig struct {
+	Kind    string `mapstructure:"kind"`
+	Workers int    `mapstructure:"workers"`
+	// CallbackURL is used for tasks which are recreated by reconciler
+	CallbackURL       string        `mapstructure:"callback-url"`
+	ReconcileInterval time.Duration `mapstructure:"reconcile-interval"`
+	ReconcileDryRun   bool          `mapstructure:"reconcile-dry-run"`
+}
+
+// Server is used to bootstrap the http server.
+// Client ip is read from X-Forwarded-For only when the request comes from one of TrustedProxies (ips or CIDRs).
+// Empty trusts no proxy, and the remote address is used as client ip.
+type Server struct {
+	TrustedProxies []string `mapstructure:"trusted-proxies"`
 }
 
 // DBConfig ...
@@ -26,6 +56,41 @@ type DBConfig struct {
 	Password string `mapstructure:"password"`
 }
 
+// TaskAuth is used to authenticate task callback requests.
+// Cloud tasks sends an OIDC token of ServiceAccountEmail, or a HMAC signature header when only HMACSecret is set.
+type TaskAuth struct {
+	ServiceAccountEmail string `mapstructure:"service-account-email"`
+	Audience            string `mapstructure:"audience"`
+	HMACSecret          string `mapstructure:"hmac-secret"`
+}
+
+// Admin is used to authorize admin api.
+// Only members who logged in with one of Emails can call admin api.
+type Admin struct {
+	Emails []string `mapstructure:"emails"`
+}
+
+// Invite is used to build room invite links.
+// SecretKey signs invite tokens. It is injected from secret manager as ROOM_INVITE_SECRET_KEY env, except dev phase.
+type Invite struct {
+	DeepLinkURL string `mapstructure:"deep-link-url"`
+	SecretKey   string `mapstructure:"secret-key"`
+}
+
+// RoomCatalog is served as room create form. Rooms can only have themes and periods (days) of the catalog.
+type RoomCatalog struct {
+	Themes  []RoomTheme `mapstructure:"themes"`
+	Periods []uint8     `mapstructure:"periods"`
+}
+
+// RoomTheme ...
+type RoomTheme struct {
+	ID            string `mapstructure:"id"`
+	Name          string `mapstructure:"name"`
+	Hashtag       string `mapstructure:"hashtag"`
+	BackgroundURL string `mapstructure:"background-url"`
+}
+
 // Client ...
 type Client struct {
 	Kakao  Kakao  `mapstructure:"kakao"`
@@ -73,6 +138,7 @@ func Load(path string) (Config, error) {
 	viper.AddConfigPath(path)
 	viper.SetConfigName(phase)
 	viper.SetConfigType(typeEXT)
+	viper.BindEnv("invite.secret-key", "ROOM_INVITE_SECRET_KEY")
 
 	err := viper.ReadInConfig()
 
@@ -94,3 +160,12 @@ func DatabaseConfig() *DBConfig {
 		Password: viper.GetString("db-config.password"),
 	}
 }
+
+// TaskAuthConfig ...
+func TaskAuthConfig() *TaskAuth {
+	return &TaskAuth{
+		ServiceAccountEmail: viper.GetString("task-auth.service-account-email"),
+		Audience:            viper.GetString("task-auth.audience"),
+		HMACSecret:          viper.GetString("task-auth.hmac-secret"),
+	}
+}
@@ -5,10 +5,11 @@ import (
 	"os"
 
 	"github.com/ExchangeDiary/exchange-diary/infrastructure/configs"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
 	"github.com/ExchangeDiary/exchange-diary/infrastructure/persistence"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
+	gormlogger "gorm.io/gorm/logger"
 )
 
 func devDsn(cfg *configs.DBConfig) string {
@@ -48,7 +49,7 @@ func ConnectDatabase(phase string) *gorm.DB {
 	}
 
 	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger: gormlogger.Default.LogMode(gormlogger.Info),
 	})
 
 	if err != nil {
@@ -72,4 +73,27 @@ func Migrate(db *gorm.DB) {
 	db.AutoMigrate(&persistence.RoomMemberGorm{})
 	db.AutoMigrate(&persistence.MemberDeviceGorm{})
 	db.AutoMigrate(&persistence.AlarmGorm{})
+	db.AutoMigrate(&persistence.DiaryGorm{})
+	db.AutoMigrate(&persistence.DiaryDraftGorm{})
+	db.AutoMigrate(&persistence.ReadMarkerGorm{})
+	db.AutoMigrate(&persistence.ReactionGorm{})
+	db.AutoMigrate(&persistence.CommentGorm{})
+	db.AutoMigrate(&persistence.ExportGorm{})
+	db.AutoMigrate(&persistence.ScheduledTaskGorm{})
+	db.AutoMigrate(&persistence.OutboxGorm{})
+	db.AutoMigrate(&persistence.TaskNameGorm{})
+	db.AutoMigrate(&persistence.TaskExecutionGorm{})
+	db.AutoMigrate(&persistence.TaskAttemptGorm{})
+	db.AutoMigrate(&persistence.AdminAuditGorm{})
+	db.AutoMigrate(&persistence.SwapRequestGorm{})
+	db.AutoMigrate(&persistence.RoomInviteGorm{})
+	db.AutoMigrate(&persistence.JoinAttemptGorm{})
+
+	// diary search falls back to scanning, so the server can start without FULLTEXT index.
+	if err := persistence.CreateDiaryFullTextIndex(db); err != nil {
+		logger.Error("failed to create diary fulltext index: " + err.Error())
+	}
+	if err := persistence.HashRoomCodes(db); err != nil {
+		panic(err)
+	}
 }
@@ -0,0 +1,75 @@
+package persistence
+
+import (
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"github.com/jinzhu/copier"
+	"gorm.io/gorm"
+)
+
+// AdminAuditGorm is a db representation of entity.AdminAudit
+// It does not refer rooms table, because audits remain after the room is deleted.
+type AdminAuditGorm struct {
+	ID      uint   `gorm:"primaryKey"`
+	AdminID uint   `gorm:"column:admin_id;not null"`
+	Action  string `gorm:"column:action;type:varchar(32);not null"`
+	RoomID  uint   `gorm:"column:room_id;index"`
+	TaskID  string `gorm:"column:task_id;type:varchar(191)"`
+	Detail  string `gorm:"column:detail;type:text"`
+	Error   string `gorm:"column:error;type:text"`
+	BaseGormModel
+}
+
+// TableName define gorm table name
+func (AdminAuditGorm) TableName() string {
+	return "admin_audits"
+}
+
+// AdminAuditRepository is a impl of domain/repository/adminAuditRepository.go AdminAuditRepository interface
+type AdminAuditRepository struct {
+	db *gorm.DB
+}
+
+// NewAdminAuditRepository ...
+func NewAdminAuditRepository(db *gorm.DB) repository.AdminAuditRepository {
+	return &AdminAuditRepository{db: db}
+}
+
+// ToAdminAuditEntity : AdminAuditGorm -> entity.AdminAudit
+func ToAdminAuditEntity(dto *AdminAuditGorm) *entity.AdminAudit {
+	audit := new(entity.AdminAudit)
+	copier.Copy(&audit, &dto)
+	return audit
+}
+
+// ToAdminAuditDTO : entity.AdminAudit -> AdminAuditGorm
+func ToAdminAuditDTO(audit *entity.AdminAudit) *AdminAuditGorm {
+	dto := new(AdminAuditGorm)
+	copier.Copy(&dto, &audit)
+	return dto
+}
+
+// Create ...
+func (aar *AdminAuditRepository) Create(audit *entity.AdminAudit) (*entity.AdminAudit, error) {
+	dto := ToAdminAuditDTO(audit)
+	if err := aar.db.Create(&dto).Error; err != nil {
+		return nil, err
+	}
+	return ToAdminAuditEntity(dto), nil
+}
+
+// GetAllByRoomID returns audits of the room in latest order
+func (aar *AdminAuditRepository) GetAllByRoomID(roomID, limit, offset uint) ([]entity.AdminAudit, error) {
+	var dtos []AdminAuditGorm
+	if err := aar.db.Where("room_id = ?", roomID).
+		Order(" id desc ").
+		Scopes(paginate(limit, offset)).
+		Find(&dtos).Error; err != nil {
+		return nil, err
+	}
+	audits := []entity.AdminAudit{}
+	for i := range dtos {
+		audits = append(audits, *ToAdminAuditEntity(&dtos[i]))
+	}
+	return audits, nil
+}
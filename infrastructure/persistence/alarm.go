@@ -91,6 +91,18 @@ func (ar *AlarmRepository) deleteIfExist(roomID, memberID uint, code vo.TaskCode
 	return nil
 }
 
+// GetByID ...
+func (ar *AlarmRepository) GetByID(id uint) (*entity.Alarm, error) {
+	dto := AlarmGorm{}
+	if err := ar.db.First(&dto, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrAlarmNotFound
+		}
+		return nil, err
+	}
+	return dto.toEntity(), nil
+}
+
 // GetAll ...
 func (ar *AlarmRepository) GetAll(accountID uint) (*entity.Alarms, error) {
 	dto := AlarmsGorm{}
@@ -0,0 +1,129 @@
+package persistence
+
+import (
+	"errors"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"gorm.io/gorm"
+)
+
+// CommentGorm is a db representation of entity.Comment
+type CommentGorm struct {
+	ID       uint         `gorm:"primaryKey"`
+	DiaryID  uint         `gorm:"column:diary_id;index:idx_diary_parent"`
+	Diary    DiaryGorm    `gorm:"column:diary_id;constraint:OnDelete:CASCADE;"`
+	AuthorID uint         `gorm:"column:author_id"`
+	Author   MemberGorm   `gorm:"column:author_id;constraint:OnDelete:CASCADE;"`
+	ParentID *uint        `gorm:"column:parent_id;index:idx_diary_parent"`
+	Parent   *CommentGorm `gorm:"column:parent_id;constraint:OnDelete:CASCADE;"`
+	Body     string       `gorm:"column:body;type:text"`
+	BaseGormModel
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index"`
+}
+
+// TableName define gorm table name
+func (CommentGorm) TableName() string {
+	return "comments"
+}
+
+// CommentsGorm is a type that represents list of CommentGorm
+type CommentsGorm []CommentGorm
+
+// CommentRepository is a impl of domain/repository/commentRepository.go CommentRepository interface
+type CommentRepository struct {
+	db *gorm.DB
+}
+
+// NewCommentRepository ...
+func NewCommentRepository(db *gorm.DB) repository.CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+// ToCommentEntity : CommentGorm -> entity.Comment
+func ToCommentEntity(dto *CommentGorm) *entity.Comment {
+	comment := &entity.Comment{
+		ID:        dto.ID,
+		DiaryID:   dto.DiaryID,
+		AuthorID:  dto.AuthorID,
+		ParentID:  dto.ParentID,
+		Body:      dto.Body,
+		CreatedAt: &dto.CreatedAt,
+	}
+	if dto.DeletedAt.Valid {
+		comment.DeletedAt = &dto.DeletedAt.Time
+	}
+	return comment
+}
+
+// ToCommentDTO : entity.Comment -> CommentGorm
+func ToCommentDTO(comment *entity.Comment) *CommentGorm {
+	return &CommentGorm{
+		ID:       comment.ID,
+		DiaryID:  comment.DiaryID,
+		AuthorID: comment.AuthorID,
+		ParentID: comment.ParentID,
+		Body:     comment.Body,
+	}
+}
+
+func toCommentEntities(dtos CommentsGorm) *entity.Comments {
+	comments := entity.Comments{}
+	for i := range dtos {
+		comments = append(comments, *ToCommentEntity(&dtos[i]))
+	}
+	return &comments
+}
+
+// Create ...
+func (cr *CommentRepository) Create(comment *entity.Comment) (*entity.Comment, error) {
+	dto := ToCommentDTO(comment)
+	if err := cr.db.Omit("Diary", "Author", "Parent").Create(&dto).Error; err != nil {
+		return nil, err
+	}
+	return ToCommentEntity(dto), nil
+}
+
+// GetByID returns a comment which is not deleted.
+func (cr *CommentRepository) GetByID(id uint) (*entity.Comment, error) {
+	dto := CommentGorm{}
+	if err := cr.db.First(&dto, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return ToCommentEntity(&dto), nil
+}
+
+// GetAll returns top-level comments of the diary in posted order.
+// A deleted comment is also returned if it has any live reply, so that the thread is kept.
+func (cr *CommentRepository) GetAll(diaryID, limit, offset uint) (*entity.Comments, error) {
+	dtos := CommentsGorm{}
+	if err := cr.db.Unscoped().
+		Where("diary_id = ? AND parent_id IS NULL", diaryID).
+		Where("deleted_at IS NULL OR EXISTS (SELECT 1 FROM comments AS r WHERE r.parent_id = comments.id AND r.deleted_at IS NULL)").
+		Scopes(paginate(limit, offset)).
+		Order("id asc").
+		Find(&dtos).Error; err != nil {
+		return nil, err
+	}
+	return toCommentEntities(dtos), nil
+}
+
+// GetAllReplies returns replies of every parent comment at once.
+func (cr *CommentRepository) GetAllReplies(parentIDs []uint) (*entity.Comments, error) {
+	dtos := CommentsGorm{}
+	if len(parentIDs) == 0 {
+		return toCommentEntities(dtos), nil
+	}
+	if err := cr.db.Where("parent_id IN (?)", parentIDs).Order("id asc").Find(&dtos).Error; err != nil {
+		return nil, err
+	}
+	return toCommentEntities(dtos), nil
+}
+
+// Delete soft deletes a comment
+func (cr *CommentRepository) Delete(comment *entity.Comment) error {
+	return cr.db.Delete(&CommentGorm{ID: comment.ID}).Error
+}
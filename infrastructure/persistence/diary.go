@@ -0,0 +1,318 @@
+package persistence
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"github.com/ExchangeDiary/exchange-diary/domain/vo"
+	"github.com/jinzhu/copier"
+	"gorm.io/gorm"
+)
+
+// diaryFullTextIndex is a FULLTEXT index on diaries(title, body).
+// ngram parser is used, because the default parser cannot tokenize korean words.
+const diaryFullTextIndex = "ftx_diaries_title_body"
+
+// booleanModeOperators are stripped from search terms of MATCH ... AGAINST
+const booleanModeOperators = `+-<>()~*"@`
+
+// ngramTokenSize is innodb_ft_ngram_token_size of mysql (default). Shorter terms never match the FULLTEXT index.
+const ngramTokenSize = 2
+
+// DiaryGorm is a db representation of entity.Diary
+// "idx_room_turn" guarantees only one diary is posted per room's turn.
+type DiaryGorm struct {
+	ID       uint       `gorm:"primaryKey"`
+	RoomID   uint       `gorm:"column:room_id;uniqueIndex:idx_room_turn"`
+	Room     RoomGorm   `gorm:"column:room_id;constraint:OnDelete:CASCADE;"`
+	AuthorID uint       `gorm:"column:author_id;index"`
+	Author   MemberGorm `gorm:"column:author_id;constraint:OnDelete:CASCADE;"`
+	Turn     uint       `gorm:"column:turn;uniqueIndex:idx_room_turn"`
+
+	Title string `gorm:"column:title;not null"`
+	Body  string `gorm:"column:body;type:text"`
+
+	PhotoUUID  string `gorm:"column:photo_uuid"`
+	PhotoURL   string `gorm:"column:photo_url"`
+	PhotoHash  string `gorm:"column:photo_hash;type:char(32)"`
+	AudioUUID  string `gorm:"column:audio_uuid"`
+	AudioURL   string `gorm:"column:audio_url"`
+	AudioHash  string `gorm:"column:audio_hash;type:char(32)"`
+	AudioTitle string `gorm:"column:audio_title"`
+	AudioPitch string `gorm:"column:audio_pitch"`
+
+	BaseGormModel
+}
+
+// TableName define gorm table name
+func (DiaryGorm) TableName() string {
+	return "diaries"
+}
+
+// BeforeUpdate prevents a posted diary from being updated.
+func (DiaryGorm) BeforeUpdate(tx *gorm.DB) error {
+	return entity.ErrImmutableDiary
+}
+
+// BeforeDelete prevents a posted diary from being deleted.
+// diaries are only removed by db cascade when its room or author is removed.
+func (DiaryGorm) BeforeDelete(tx *gorm.DB) error {
+	return entity.ErrImmutableDiary
+}
+
+// CreateDiaryFullTextIndex creates FULLTEXT index for diary search, if it does not exist.
+// It does nothing except mysql. (e.g. sqlite for tests)
+func CreateDiaryFullTextIndex(db *gorm.DB) error {
+	if !isMySQL(db) || db.Migrator().HasIndex(&DiaryGorm{}, diaryFullTextIndex) {
+		return nil
+	}
+	return db.Exec(fmt.Sprintf("CREATE FULLTEXT INDEX %s ON diaries (title, body) WITH PARSER ngram", diaryFullTextIndex)).Error
+}
+
+func isMySQL(db *gorm.DB) bool {
+	return db.Dialector.Name() == "mysql"
+}
+
+// DiaryGorms define list of DiaryGorm
+type DiaryGorms []DiaryGorm
+
+// DiaryRepository is a impl of domain/repository/diaryRepository.go DiaryRepository interface
+type DiaryRepository struct {
+	db *gorm.DB
+}
+
+// NewDiaryRepository ...
+func NewDiaryRepository(db *gorm.DB) repository.DiaryRepository {
+	return &DiaryRepository{db: db}
+}
+
+func paginate(limit, offset uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Offset(int(offset)).Limit(int(limit))
+	}
+}
+
+// before returns diaries which are posted earlier than the cursor diary.
+// 0 means there is no cursor.
+func before(cursor uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if cursor == 0 {
+			return db
+		}
+		return db.Where("id < ?", cursor)
+	}
+}
+
+// ToDiaryEntity : DiaryGorm -> entity.Diary
+func ToDiaryEntity(dto *DiaryGorm) *entity.Diary {
+	diary := new(entity.Diary)
+	copier.Copy(&diary, &dto)
+	diary.Author = nil
+	return diary
+}
+
+// ToDiaryDTO : entity.Diary -> DiaryGorm
+func ToDiaryDTO(diary *entity.Diary) *DiaryGorm {
+	dto := new(DiaryGorm)
+	copier.Copy(&dto, &diary)
+	dto.Room = RoomGorm{}
+	dto.Author = MemberGorm{}
+	return dto
+}
+
+// Create ...
+func (dr *DiaryRepository) Create(diary *entity.Diary) (*entity.Diary, error) {
+	dto := ToDiaryDTO(diary)
+	if err := dr.db.Omit("Room", "Author").Create(&dto).Error; err != nil {
+		return nil, err
+	}
+	return ToDiaryEntity(dto), nil
+}
+
+// GetByID ...
+func (dr *DiaryRepository) GetByID(id uint) (*entity.Diary, error) {
+	dto := DiaryGorm{ID: id}
+	if err := dr.db.First(&dto).Error; err != nil {
+		return nil, err
+	}
+	return ToDiaryEntity(&dto), nil
+}
+
+// GetLatest returns the most recently posted diary of a room.
+func (dr *DiaryRepository) GetLatest(roomID uint) (*entity.Diary, error) {
+	dto := DiaryGorm{}
+	if err := dr.db.Where("room_id = ?", roomID).Order(" turn desc ").First(&dto).Error; err != nil {
+		return nil, err
+	}
+	return ToDiaryEntity(&dto), nil
+}
+
+type lastCreatedAtRow struct {
+	RoomID        uint
+	LastCreatedAt time.Time
+}
+
+// LastCreatedAts finds the latest diary of every room with a single query.
+func (dr *DiaryRepository) LastCreatedAts(roomIDs []uint) (map[uint]time.Time, error) {
+	lastCreatedAts := map[uint]time.Time{}
+	if len(roomIDs) == 0 {
+		return lastCreatedAts, nil
+	}
+
+	rows := []lastCreatedAtRow{}
+	if err := dr.db.Model(&DiaryGorm{}).
+		Select("room_id, MAX(created_at) AS last_created_at").
+		Where("room_id IN (?)", roomIDs).
+		Group("room_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		lastCreatedAts[row.RoomID] = row.LastCreatedAt
+	}
+	return lastCreatedAts, nil
+}
+
+// GetAll returns room's diaries ordered by latest. It supports both offset and cursor pagination.
+func (dr *DiaryRepository) GetAll(roomID, limit, offset, cursor uint) (*entity.Diaries, error) {
+	dto := DiaryGorms{}
+	if err := dr.db.Where("room_id = ?", roomID).
+		Scopes(before(cursor), paginate(limit, offset)).
+		Order(" id desc ").
+		Find(&dto).Error; err != nil {
+		return nil, err
+	}
+
+	diaries := entity.Diaries{}
+	for _, diaryGorm := range dto {
+		diaries = append(diaries, *ToDiaryEntity(&diaryGorm))
+	}
+	return &diaries, nil
+}
+
+// Update always fails, because posted diary is immutable.
+func (dr *DiaryRepository) Update(diary *entity.Diary) (*entity.Diary, error) {
+	return nil, entity.ErrImmutableDiary
+}
+
+// Delete always fails, because posted diary is immutable.
+func (dr *DiaryRepository) Delete(diary *entity.Diary) error {
+	return entity.ErrImmutableDiary
+}
+
+// Search finds diaries whose title or body contains every term of keyword, ordered by relevance.
+// mysql uses FULLTEXT index, and the others fall back to scan matching in go.
+func (dr *DiaryRepository) Search(cond vo.DiarySearchVO) (*entity.Diaries, error) {
+	terms := cond.Terms()
+	if len(terms) == 0 || len(cond.RoomIDs) == 0 {
+		return &entity.Diaries{}, nil
+	}
+
+	query := dr.db.Scopes(searchFilter(cond))
+	// terms shorter than ngram token size are found only by scanning. (ex. a single korean character)
+	if isMySQL(dr.db) && !hasShortTerm(terms) {
+		return dr.fullTextSearch(query, terms, cond)
+	}
+	return dr.scanSearch(query, terms, cond)
+}
+
+func searchFilter(cond vo.DiarySearchVO) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("room_id IN (?)", cond.RoomIDs)
+		if cond.AuthorID != 0 {
+			db = db.Where("author_id = ?", cond.AuthorID)
+		}
+		if cond.From != nil {
+			db = db.Where("created_at >= ?", *cond.From)
+		}
+		if cond.To != nil {
+			db = db.Where("created_at < ?", *cond.To)
+		}
+		return db
+	}
+}
+
+func (dr *DiaryRepository) fullTextSearch(query *gorm.DB, terms []string, cond vo.DiarySearchVO) (*entity.Diaries, error) {
+	against := toBooleanModeQuery(terms)
+	if against == "" {
+		return &entity.Diaries{}, nil
+	}
+
+	dto := DiaryGorms{}
+	if err := query.
+		Select("*, MATCH (title, body) AGAINST (? IN BOOLEAN MODE) AS score", against).
+		Where("MATCH (title, body) AGAINST (? IN BOOLEAN MODE)", against).
+		Scopes(paginate(cond.Limit, cond.Offset)).
+		Order(" score desc, id desc ").
+		Find(&dto).Error; err != nil {
+		return nil, err
+	}
+
+	diaries := entity.Diaries{}
+	for _, diaryGorm := range dto {
+		diaries = append(diaries, *ToDiaryEntity(&diaryGorm))
+	}
+	return &diaries, nil
+}
+
+// toBooleanModeQuery requires every term as a phrase. e.g) +"교환" +"일기"
+func stripBooleanModeOperators(term string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(booleanModeOperators, r) {
+			return -1
+		}
+		return r
+	}, term)
+}
+
+// hasShortTerm reports whether any term is shorter than ngramTokenSize, after boolean mode operators are stripped.
+func hasShortTerm(terms []string) bool {
+	for _, term := range terms {
+		if n := utf8.RuneCountInString(stripBooleanModeOperators(term)); n > 0 && n < ngramTokenSize {
+			return true
+		}
+	}
+	return false
+}
+
+func toBooleanModeQuery(terms []string) string {
+	phrases := []string{}
+	for _, term := range terms {
+		term = stripBooleanModeOperators(term)
+		if term != "" {
+			phrases = append(phrases, fmt.Sprintf(`+"%s"`, term))
+		}
+	}
+	return strings.Join(phrases, " ")
+}
+
+// scanSearch is a pure go fallback of fullTextSearch, which is used for databases without FULLTEXT index.
+// Like paginate, limit 0 means unlimited.
+func (dr *DiaryRepository) scanSearch(query *gorm.DB, terms []string, cond vo.DiarySearchVO) (*entity.Diaries, error) {
+	dto := DiaryGorms{}
+	if err := query.Order(" id desc ").Find(&dto).Error; err != nil {
+		return nil, err
+	}
+
+	diaries := entity.Diaries{}
+	skipped := uint(0)
+	for _, diaryGorm := range dto {
+		diary := ToDiaryEntity(&diaryGorm)
+		if !diary.Matches(terms) {
+			continue
+		}
+		if skipped < cond.Offset {
+			skipped++
+			continue
+		}
+		if cond.Limit > 0 && uint(len(diaries)) >= cond.Limit {
+			break
+		}
+		diaries = append(diaries, *diary)
+	}
+	return &diaries, nil
+}
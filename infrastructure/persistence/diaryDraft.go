@@ -0,0 +1,143 @@
+package persistence
+
+import (
+	"errors"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"github.com/jinzhu/copier"
+	"gorm.io/gorm"
+)
+
+// DiaryDraftGorm is a db representation of entity.DiaryDraft
+// "idx_room_member_turn" is a unique key combined with (RoomID, MemberID, Turn)
+type DiaryDraftGorm struct {
+	ID       uint       `gorm:"primaryKey"`
+	RoomID   uint       `gorm:"column:room_id;uniqueIndex:idx_room_member_turn"`
+	Room     RoomGorm   `gorm:"column:room_id;constraint:OnDelete:CASCADE;"`
+	MemberID uint       `gorm:"column:member_id;uniqueIndex:idx_room_member_turn"`
+	Member   MemberGorm `gorm:"column:member_id;constraint:OnDelete:CASCADE;"`
+	Turn     uint       `gorm:"column:turn;uniqueIndex:idx_room_member_turn"`
+	Version  uint       `gorm:"column:version;not null"`
+
+	Title string `gorm:"column:title"`
+	Body  string `gorm:"column:body;type:text"`
+
+	PhotoUUID  string `gorm:"column:photo_uuid"`
+	PhotoURL   string `gorm:"column:photo_url"`
+	AudioUUID  string `gorm:"column:audio_uuid"`
+	AudioURL   string `gorm:"column:audio_url"`
+	AudioTitle string `gorm:"column:audio_title"`
+	AudioPitch string `gorm:"column:audio_pitch"`
+
+	BaseGormModel
+}
+
+// TableName define gorm table name
+func (DiaryDraftGorm) TableName() string {
+	return "diary_drafts"
+}
+
+// DiaryDraftRepository is a impl of domain/repository/diaryDraftRepository.go DiaryDraftRepository interface
+type DiaryDraftRepository struct {
+	db *gorm.DB
+}
+
+// NewDiaryDraftRepository ...
+func NewDiaryDraftRepository(db *gorm.DB) repository.DiaryDraftRepository {
+	return &DiaryDraftRepository{db: db}
+}
+
+// ToDiaryDraftEntity : DiaryDraftGorm -> entity.DiaryDraft
+func ToDiaryDraftEntity(dto *DiaryDraftGorm) *entity.DiaryDraft {
+	draft := new(entity.DiaryDraft)
+	copier.Copy(&draft, &dto)
+	return draft
+}
+
+// ToDiaryDraftDTO : entity.DiaryDraft -> DiaryDraftGorm
+func ToDiaryDraftDTO(draft *entity.DiaryDraft) *DiaryDraftGorm {
+	dto := new(DiaryDraftGorm)
+	copier.Copy(&dto, &draft)
+	return dto
+}
+
+// GetByUnq func gets DiaryDraft row by unique_key(RoomID, MemberID, Turn)
+func (ddr *DiaryDraftRepository) GetByUnq(roomID, memberID, turn uint) (*entity.DiaryDraft, error) {
+	dto := DiaryDraftGorm{}
+	if err := ddr.db.Where("room_id = ? AND member_id = ? AND turn = ?", roomID, memberID, turn).First(&dto).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrDraftNotFound
+		}
+		return nil, err
+	}
+	return ToDiaryDraftEntity(&dto), nil
+}
+
+// Save inserts or updates a draft. draft.Version must be the version which client has read.
+// If the row is already changed, it returns entity.ErrDraftVersionConflict.
+func (ddr *DiaryDraftRepository) Save(draft *entity.DiaryDraft) (*entity.DiaryDraft, error) {
+	dto := ToDiaryDraftDTO(draft)
+	if draft.ID == 0 {
+		dto.Version = 1
+		if err := ddr.db.Omit("Room", "Member").Create(&dto).Error; err != nil {
+			return nil, err
+		}
+		return ToDiaryDraftEntity(dto), nil
+	}
+
+	result := ddr.db.Model(&DiaryDraftGorm{}).
+		Where("id = ? AND version = ?", draft.ID, draft.Version).
+		Updates(map[string]interface{}{
+			"title":       draft.Title,
+			"body":        draft.Body,
+			"photo_uuid":  draft.PhotoUUID,
+			"photo_url":   draft.PhotoURL,
+			"audio_uuid":  draft.AudioUUID,
+			"audio_url":   draft.AudioURL,
+			"audio_title": draft.AudioTitle,
+			"audio_pitch": draft.AudioPitch,
+			"version":     gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, entity.ErrDraftVersionConflict
+	}
+
+	savedDto := DiaryDraftGorm{ID: draft.ID}
+	if err := ddr.db.First(&savedDto).Error; err != nil {
+		return nil, err
+	}
+	return ToDiaryDraftEntity(&savedDto), nil
+}
+
+// Promote posts a diary and removes its draft in a transaction.
+func (ddr *DiaryDraftRepository) Promote(draft *entity.DiaryDraft, diary *entity.Diary) (*entity.Diary, error) {
+	diaryDto := ToDiaryDTO(diary)
+	err := ddr.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND version = ?", draft.ID, draft.Version).Delete(&DiaryDraftGorm{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return entity.ErrDraftVersionConflict
+		}
+		return tx.Omit("Room", "Author").Create(&diaryDto).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ToDiaryEntity(diaryDto), nil
+}
+
+// Delete ...
+func (ddr *DiaryDraftRepository) Delete(draft *entity.DiaryDraft) error {
+	return ddr.db.Delete(&DiaryDraftGorm{ID: draft.ID}).Error
+}
+
+// DeleteAllBefore discards every draft of room which was written before the turn.
+func (ddr *DiaryDraftRepository) DeleteAllBefore(roomID, turn uint) error {
+	return ddr.db.Where("room_id = ? AND turn < ?", roomID, turn).Delete(&DiaryDraftGorm{}).Error
+}
@@ -0,0 +1,105 @@
+package persistence
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/vo"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// newSearchTestDB returns an in-memory sqlite db, which uses scanSearch for diary search.
+func newSearchTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&DiaryGorm{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateDiaryFullTextIndex(db); err != nil {
+		t.Fatal(err)
+	}
+
+	diaries := []DiaryGorm{
+		{ID: 1, RoomID: 1, AuthorID: 1, Turn: 1, Title: "교환 일기", Body: "first day"},
+		{ID: 2, RoomID: 1, AuthorID: 2, Turn: 2, Title: "Second", Body: "교환 일기 is fun"},
+		{ID: 3, RoomID: 2, AuthorID: 1, Turn: 1, Title: "other room", Body: "교환 일기"},
+		{ID: 4, RoomID: 1, AuthorID: 1, Turn: 3, Title: "no match", Body: "nothing here"},
+		{ID: 5, RoomID: 3, AuthorID: 1, Turn: 1, Title: "not joined", Body: "교환 일기"},
+	}
+	for i, diary := range diaries {
+		if err := db.Create(&diary).Error; err != nil {
+			t.Fatal(err)
+		}
+		createdAt := time.Date(2022, 3, i+1, 12, 0, 0, 0, time.UTC)
+		if err := db.Exec("UPDATE diaries SET created_at = ? WHERE id = ?", createdAt, diary.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func TestDiaryRepositoryScanSearch(t *testing.T) {
+	dr := NewDiaryRepository(newSearchTestDB(t))
+	from := time.Date(2022, 3, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 3, 3, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		cond vo.DiarySearchVO
+		want []uint
+	}{
+		{"every term matches title or body", vo.DiarySearchVO{Keyword: "교환 일기", RoomIDs: []uint{1, 2}, Limit: 10}, []uint{3, 2, 1}},
+		{"case insensitive", vo.DiarySearchVO{Keyword: "FIRST", RoomIDs: []uint{1}, Limit: 10}, []uint{1}},
+		{"every term is required", vo.DiarySearchVO{Keyword: "교환 fun", RoomIDs: []uint{1, 2}, Limit: 10}, []uint{2}},
+		{"only accessible rooms", vo.DiarySearchVO{Keyword: "교환", RoomIDs: []uint{2}, Limit: 10}, []uint{3}},
+		{"no accessible rooms", vo.DiarySearchVO{Keyword: "교환", Limit: 10}, []uint{}},
+		{"empty keyword", vo.DiarySearchVO{Keyword: "  ", RoomIDs: []uint{1}, Limit: 10}, []uint{}},
+		{"author", vo.DiarySearchVO{Keyword: "교환", RoomIDs: []uint{1, 2}, AuthorID: 1, Limit: 10}, []uint{3, 1}},
+		{"created from, to", vo.DiarySearchVO{Keyword: "교환", RoomIDs: []uint{1, 2}, From: &from, To: &to, Limit: 10}, []uint{2}},
+		{"limit", vo.DiarySearchVO{Keyword: "교환", RoomIDs: []uint{1, 2}, Limit: 2}, []uint{3, 2}},
+		{"offset", vo.DiarySearchVO{Keyword: "교환", RoomIDs: []uint{1, 2}, Limit: 2, Offset: 2}, []uint{1}},
+		{"offset over results", vo.DiarySearchVO{Keyword: "교환", RoomIDs: []uint{1, 2}, Limit: 2, Offset: 3}, []uint{}},
+		{"limit 0 is unlimited as paginate", vo.DiarySearchVO{Keyword: "교환", RoomIDs: []uint{1, 2}}, []uint{3, 2, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diaries, err := dr.Search(tt.cond)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []uint{}
+			for _, diary := range *diaries {
+				got = append(got, diary.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasShortTerm(t *testing.T) {
+	tests := []struct {
+		name  string
+		terms []string
+		want  bool
+	}{
+		{"long terms", []string{"교환", "diary"}, false},
+		{"a korean character", []string{"교환", "꿈"}, true},
+		{"a latin character", []string{"a"}, true},
+		{"short after operators are stripped", []string{"+a*"}, true},
+		{"only operators", []string{"+"}, false},
+		{"no terms", []string{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasShortTerm(tt.terms); got != tt.want {
+				t.Errorf("hasShortTerm(%q) = %t, want %t", tt.terms, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package persistence
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"github.com/jinzhu/copier"
+	"gorm.io/gorm"
+)
+
+// ExportGorm is a db representation of entity.Export
+type ExportGorm struct {
+	ID          uint       `gorm:"primaryKey"`
+	RoomID      uint       `gorm:"column:room_id;index:idx_room_member"`
+	Room        RoomGorm   `gorm:"column:room_id;constraint:OnDelete:CASCADE;"`
+	MemberID    uint       `gorm:"column:member_id;index:idx_room_member"`
+	Member      MemberGorm `gorm:"column:member_id;constraint:OnDelete:CASCADE;"`
+	Status      string     `gorm:"column:status;type:varchar(16);not null"`
+	URL         string     `gorm:"column:url"`
+	DownloadURL string     `gorm:"column:download_url;type:text"`
+	Error       string     `gorm:"column:error;type:text"`
+	StartedAt   *time.Time `gorm:"column:started_at"`
+	FinishedAt  *time.Time `gorm:"column:finished_at"`
+	BaseGormModel
+}
+
+// TableName define gorm table name
+func (ExportGorm) TableName() string {
+	return "exports"
+}
+
+// ExportRepository is a impl of domain/repository/exportRepository.go ExportRepository interface
+type ExportRepository struct {
+	db *gorm.DB
+}
+
+// NewExportRepository ...
+func NewExportRepository(db *gorm.DB) repository.ExportRepository {
+	return &ExportRepository{db: db}
+}
+
+// ToExportEntity : ExportGorm -> entity.Export
+func ToExportEntity(dto *ExportGorm) *entity.Export {
+	export := new(entity.Export)
+	copier.Copy(&export, &dto)
+	return export
+}
+
+// ToExportDTO : entity.Export -> ExportGorm
+func ToExportDTO(export *entity.Export) *ExportGorm {
+	dto := new(ExportGorm)
+	copier.Copy(&dto, &export)
+	return dto
+}
+
+// Create ...
+func (er *ExportRepository) Create(export *entity.Export) (*entity.Export, error) {
+	dto := ToExportDTO(export)
+	if err := er.db.Omit("Room", "Member").Create(&dto).Error; err != nil {
+		return nil, err
+	}
+	return ToExportEntity(dto), nil
+}
+
+// GetByID ...
+func (er *ExportRepository) GetByID(id uint) (*entity.Export, error) {
+	dto := ExportGorm{}
+	if err := er.db.First(&dto, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrExportNotFound
+		}
+		return nil, err
+	}
+	return ToExportEntity(&dto), nil
+}
+
+// GetUnfinished returns member's export of the room which is pending or running.
+func (er *ExportRepository) GetUnfinished(roomID, memberID uint) (*entity.Export, error) {
+	dto := ExportGorm{}
+	if err := er.db.Where("room_id = ? AND member_id = ? AND status IN (?)",
+		roomID, memberID, []string{string(entity.ExportPending), string(entity.ExportRunning)}).
+		Order(" id desc ").
+		First(&dto).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrExportNotFound
+		}
+		return nil, err
+	}
+	return ToExportEntity(&dto), nil
+}
+
+// Update ...
+func (er *ExportRepository) Update(export *entity.Export) (*entity.Export, error) {
+	dto := ToExportDTO(export)
+	if err := er.db.Model(dto).Select("status", "url", "download_url", "error", "started_at", "finished_at").Updates(dto).Error; err != nil {
+		return nil, err
+	}
+	return ToExportEntity(dto), nil
+}
@@ -0,0 +1,75 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"github.com/jinzhu/copier"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// JoinAttemptGorm is a db representation of entity.JoinAttempt
+type JoinAttemptGorm struct {
+	ID           uint       `gorm:"primaryKey"`
+	Key          string     `gorm:"column:key;type:varchar(64);not null;uniqueIndex"`
+	Failures     uint       `gorm:"column:failures;not null;default:0"`
+	LockedUntil  *time.Time `gorm:"column:locked_until"`
+	LastFailedAt *time.Time `gorm:"column:last_failed_at"`
+	BaseGormModel
+}
+
+// TableName define gorm table name
+func (JoinAttemptGorm) TableName() string {
+	return "join_attempts"
+}
+
+// JoinAttemptRepository is a impl of domain/repository/joinAttemptRepository.go JoinAttemptRepository interface
+type JoinAttemptRepository struct {
+	db *gorm.DB
+}
+
+// NewJoinAttemptRepository ...
+func NewJoinAttemptRepository(db *gorm.DB) repository.JoinAttemptRepository {
+	return &JoinAttemptRepository{db: db}
+}
+
+// ToJoinAttemptEntity : JoinAttemptGorm -> entity.JoinAttempt
+func ToJoinAttemptEntity(dto *JoinAttemptGorm) *entity.JoinAttempt {
+	attempt := new(entity.JoinAttempt)
+	copier.Copy(&attempt, &dto)
+	return attempt
+}
+
+// Update locks the row of key, so that concurrent failures are all counted.
+func (jar *JoinAttemptRepository) Update(key string, fn func(attempt *entity.JoinAttempt) error) (*entity.JoinAttempt, error) {
+	var attempt *entity.JoinAttempt
+	err := jar.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&JoinAttemptGorm{Key: key}).Error; err != nil {
+			return err
+		}
+		dto := JoinAttemptGorm{}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("`key` = ?", key).First(&dto).Error; err != nil {
+			return err
+		}
+		attempt = ToJoinAttemptEntity(&dto)
+		if err := fn(attempt); err != nil {
+			return err
+		}
+		return tx.Model(&dto).Updates(map[string]interface{}{
+			"failures":       attempt.Failures,
+			"locked_until":   attempt.LockedUntil,
+			"last_failed_at": attempt.LastFailedAt,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return attempt, nil
+}
+
+// Delete ...
+func (jar *JoinAttemptRepository) Delete(key string) error {
+	return jar.db.Where("`key` = ?", key).Delete(&JoinAttemptGorm{}).Error
+}
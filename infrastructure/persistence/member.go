@@ -1,11 +1,15 @@
 package persistence
 
 import (
+	"encoding/json"
 	"errors"
 
 	"github.com/ExchangeDiary/exchange-diary/domain/entity"
 	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"github.com/ExchangeDiary/exchange-diary/domain/vo"
+	"github.com/ExchangeDiary/exchange-diary/infrastructure/logger"
 	"github.com/jinzhu/copier"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -18,6 +22,12 @@ type MemberGorm struct {
 	ProfileURL string `gorm:"column:profile_url"`
 	AuthType   string `gorm:"column:auth_type"`
 	AlarmFlag  bool   `gorm:"column:alarm_flag"`
+	// minutes json
+	ReminderOptOuts datatypes.JSON `gorm:"column:reminder_opt_outs"`
+	TimeZone        string         `gorm:"column:time_zone;type:varchar(64)"`
+	// minutes from midnight. quiet hours are disabled if start equals end
+	QuietHoursStart uint16 `gorm:"column:quiet_hours_start;not null;default:0"`
+	QuietHoursEnd   uint16 `gorm:"column:quiet_hours_end;not null;default:0"`
 	BaseGormModel
 }
 
@@ -43,6 +53,14 @@ func NewMemberRepository(db *gorm.DB) repository.MemberRepository {
 func ToMemberEntity(memberDto *MemberGorm) *entity.Member {
 	member := new(entity.Member)
 	copier.Copy(&member, &memberDto)
+	var minutes []uint
+	if len(memberDto.ReminderOptOuts) > 0 {
+		if err := json.Unmarshal([]byte(memberDto.ReminderOptOuts), &minutes); err != nil {
+			logger.Error(err.Error())
+		}
+	}
+	member.ReminderOptOuts = entity.MinutesToDurations(minutes)
+	member.QuietHours = vo.QuietHours{Start: memberDto.QuietHoursStart, End: memberDto.QuietHoursEnd}
 	return member
 }
 
@@ -50,6 +68,10 @@ func ToMemberEntity(memberDto *MemberGorm) *entity.Member {
 func ToMemberDTO(member *entity.Member) *MemberGorm {
 	memberDto := new(MemberGorm)
 	copier.Copy(&memberDto, &member)
+	optOutsJSON, _ := json.Marshal(entity.DurationsToMinutes(member.ReminderOptOuts))
+	memberDto.ReminderOptOuts = datatypes.JSON(optOutsJSON)
+	memberDto.QuietHoursStart = member.QuietHours.Start
+	memberDto.QuietHoursEnd = member.QuietHours.End
 	return memberDto
 }
 
@@ -66,6 +88,9 @@ func (r *MemberRepository) Create(member *entity.Member) (*entity.Member, error)
 func (r *MemberRepository) Get(id uint) (*entity.Member, error) {
 	dto := MemberGorm{ID: id}
 	if err := r.db.First(&dto).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrMemberNotFound
+		}
 		return nil, err
 	}
 	return ToMemberEntity(&dto), nil
@@ -75,6 +100,9 @@ func (r *MemberRepository) Get(id uint) (*entity.Member, error) {
 func (r *MemberRepository) GetByEmail(email string) (*entity.Member, error) {
 	dto := MemberGorm{}
 	if err := r.db.Where("email = ?", email).First(&dto).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrMemberNotFound
+		}
 		return nil, err
 	}
 	return ToMemberEntity(&dto), nil
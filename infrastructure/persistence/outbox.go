@@ -0,0 +1,168 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"github.com/ExchangeDiary/exchange-diary/domain/vo"
+	"gorm.io/gorm"
+)
+
+// OutboxGorm is a db representation of entity.OutboxMessage
+type OutboxGorm struct {
+	ID            uint       `gorm:"primaryKey"`
+	Action        string     `gorm:"column:action;type:varchar(16);not null"`
+	TaskID        string     `gorm:"column:task_id;type:varchar(191);index:idx_task_id;not null"`
+	Instance      string     `gorm:"column:instance;type:varchar(32)"`
+	RoomID        uint       `gorm:"column:room_id"`
+	Email         string     `gorm:"column:email"`
+	Code          string     `gorm:"column:code;type:varchar(32)"`
+	Turn          uint       `gorm:"column:turn"`
+	DueAt         *time.Time `gorm:"column:due_at"`
+	AlarmID       uint       `gorm:"column:alarm_id"`
+	SwapRequestID uint       `gorm:"column:swap_request_id"`
+	CallbackURL   string     `gorm:"column:callback_url;type:text"`
+	ScheduledAt   time.Time  `gorm:"column:scheduled_at"`
+	Attempts      uint       `gorm:"column:attempts;not null"`
+	NextAttemptAt time.Time  `gorm:"column:next_attempt_at;index:idx_pending"`
+	LockedUntil   *time.Time `gorm:"column:locked_until"`
+	LastError     string     `gorm:"column:last_error;type:text"`
+	DeliveredAt   *time.Time `gorm:"column:delivered_at;index:idx_pending"`
+	FailedAt      *time.Time `gorm:"column:failed_at"`
+	BaseGormModel
+}
+
+// TableName define gorm table name
+func (OutboxGorm) TableName() string {
+	return "outbox_messages"
+}
+
+// OutboxRepository is a impl of domain/repository/outboxRepository.go OutboxRepository interface
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository ...
+func NewOutboxRepository(db *gorm.DB) repository.OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// ToOutboxEntity : OutboxGorm -> entity.OutboxMessage
+func ToOutboxEntity(dto *OutboxGorm) *entity.OutboxMessage {
+	createdAt := dto.CreatedAt
+	task := entity.NewScheduledTask(
+		dto.TaskID,
+		vo.NewTaskVO(dto.RoomID, dto.Email, vo.TaskCode(dto.Code)).OnTurn(dto.Turn, dto.DueAt),
+		dto.CallbackURL,
+		dto.ScheduledAt,
+	)
+	task.Instance = dto.Instance
+	task.Task.AlarmID = dto.AlarmID
+	task.Task.SwapRequestID = dto.SwapRequestID
+	return &entity.OutboxMessage{
+		ID:            dto.ID,
+		Action:        entity.OutboxAction(dto.Action),
+		Task:          *task,
+		Attempts:      dto.Attempts,
+		NextAttemptAt: dto.NextAttemptAt,
+		LockedUntil:   dto.LockedUntil,
+		LastError:     dto.LastError,
+		CreatedAt:     &createdAt,
+		DeliveredAt:   dto.DeliveredAt,
+		FailedAt:      dto.FailedAt,
+	}
+}
+
+// ToOutboxDTO : entity.OutboxMessage -> OutboxGorm
+func ToOutboxDTO(message *entity.OutboxMessage) *OutboxGorm {
+	return &OutboxGorm{
+		ID:            message.ID,
+		Action:        string(message.Action),
+		TaskID:        message.Task.ID,
+		Instance:      message.Task.Instance,
+		RoomID:        message.Task.Task.RoomID,
+		Email:         message.Task.Task.Email,
+		Code:          string(message.Task.Task.Code),
+		Turn:          message.Task.Task.Turn,
+		DueAt:         message.Task.Task.DueAt,
+		AlarmID:       message.Task.Task.AlarmID,
+		SwapRequestID: message.Task.Task.SwapRequestID,
+		CallbackURL:   message.Task.CallbackURL,
+		ScheduledAt:   message.Task.ScheduledAt,
+		Attempts:      message.Attempts,
+		NextAttemptAt: message.NextAttemptAt,
+		LockedUntil:   message.LockedUntil,
+		LastError:     message.LastError,
+		DeliveredAt:   message.DeliveredAt,
+		FailedAt:      message.FailedAt,
+	}
+}
+
+// Create inserts messages in given order.
+func (or *OutboxRepository) Create(messages ...*entity.OutboxMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	dtos := make([]*OutboxGorm, 0, len(messages))
+	for _, message := range messages {
+		dtos = append(dtos, ToOutboxDTO(message))
+	}
+	if err := or.db.Create(&dtos).Error; err != nil {
+		return err
+	}
+	for i := range dtos {
+		messages[i].ID = dtos[i].ID
+	}
+	return nil
+}
+
+// Claim locks pending messages for lease duration and returns them ordered by id.
+// A message is skipped while an earlier message of the same task is not finished,
+// so intents of a task (ex. cancel -> schedule) are always delivered in order.
+func (or *OutboxRepository) Claim(now time.Time, lease time.Duration, limit int) ([]entity.OutboxMessage, error) {
+	candidates := []OutboxGorm{}
+	if err := or.db.Table("outbox_messages AS o").
+		Where("o.delivered_at IS NULL AND o.failed_at IS NULL AND o.next_attempt_at <= ?", now).
+		Where("o.locked_until IS NULL OR o.locked_until < ?", now).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM outbox_messages AS p
+			WHERE p.task_id = o.task_id AND p.id < o.id AND p.delivered_at IS NULL AND p.failed_at IS NULL
+		)`).
+		Order("o.id ASC").
+		Limit(limit).
+		Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	lockedUntil := now.Add(lease)
+	claimed := []entity.OutboxMessage{}
+	for i := range candidates {
+		dto := &candidates[i]
+		result := or.db.Model(&OutboxGorm{}).
+			Where("id = ? AND (locked_until IS NULL OR locked_until < ?)", dto.ID, now).
+			Update("locked_until", lockedUntil)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		// claimed by another relay
+		if result.RowsAffected == 0 {
+			continue
+		}
+		dto.LockedUntil = &lockedUntil
+		claimed = append(claimed, *ToOutboxEntity(dto))
+	}
+	return claimed, nil
+}
+
+// Update saves delivery result and unlocks the message.
+func (or *OutboxRepository) Update(message *entity.OutboxMessage) error {
+	return or.db.Model(&OutboxGorm{ID: message.ID}).Updates(map[string]interface{}{
+		"attempts":        message.Attempts,
+		"next_attempt_at": message.NextAttemptAt,
+		"locked_until":    nil,
+		"last_error":      message.LastError,
+		"delivered_at":    message.DeliveredAt,
+		"failed_at":       message.FailedAt,
+	}).Error
+}
@@ -0,0 +1,41 @@
+package persistence
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/vo"
+)
+
+func TestOutboxMessageRoundTrip(t *testing.T) {
+	dueAt := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	deferred := vo.NewTaskVO(1, "a@voda.com", vo.DeferredPushCode)
+	deferred.AlarmID = 7
+	expire := vo.NewTaskVO(1, "a@voda.com", vo.SwapRequestExpireCode)
+	expire.SwapRequestID = 9
+
+	tests := []struct {
+		name string
+		task vo.TaskVO
+	}{
+		{"turn task", vo.NewTaskVO(1, "a@voda.com", vo.RoomPeriodFinCode).OnTurn(3, &dueAt)},
+		{"deferred push", deferred},
+		{"swap request expire", expire},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := entity.NewScheduleMessage(entity.NewScheduledTask("task", tt.task, "https://voda.com", dueAt))
+
+			outboxed := ToOutboxEntity(ToOutboxDTO(message)).Task
+			if !reflect.DeepEqual(outboxed.Task, tt.task) || outboxed.Instance != message.Task.Instance {
+				t.Fatalf("outbox lost task fields: got %+v, want %+v", outboxed, message.Task)
+			}
+			scheduled := ToScheduledTaskEntity(ToScheduledTaskDTO(&message.Task)).Task
+			if !reflect.DeepEqual(scheduled, tt.task) {
+				t.Fatalf("scheduled task lost task fields: got %+v, want %+v", scheduled, tt.task)
+			}
+		})
+	}
+}
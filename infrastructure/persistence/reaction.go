@@ -0,0 +1,116 @@
+package persistence
+
+import (
+	"errors"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"github.com/jinzhu/copier"
+	"gorm.io/gorm"
+)
+
+// ReactionGorm is a db representation of entity.Reaction
+// "idx_diary_member" is a unique key combined with (DiaryID, MemberID)
+type ReactionGorm struct {
+	ID       uint       `gorm:"primaryKey"`
+	DiaryID  uint       `gorm:"column:diary_id;uniqueIndex:idx_diary_member"`
+	Diary    DiaryGorm  `gorm:"column:diary_id;constraint:OnDelete:CASCADE;"`
+	MemberID uint       `gorm:"column:member_id;uniqueIndex:idx_diary_member"`
+	Member   MemberGorm `gorm:"column:member_id;constraint:OnDelete:CASCADE;"`
+	Emoji    string     `gorm:"column:emoji;type:varchar(32);not null"`
+	BaseGormModel
+}
+
+// TableName define gorm table name
+func (ReactionGorm) TableName() string {
+	return "reactions"
+}
+
+// ReactionRepository is a impl of domain/repository/reactionRepository.go ReactionRepository interface
+type ReactionRepository struct {
+	db *gorm.DB
+}
+
+// NewReactionRepository ...
+func NewReactionRepository(db *gorm.DB) repository.ReactionRepository {
+	return &ReactionRepository{db: db}
+}
+
+// ToReactionEntity : ReactionGorm -> entity.Reaction
+func ToReactionEntity(dto *ReactionGorm) *entity.Reaction {
+	reaction := new(entity.Reaction)
+	copier.Copy(&reaction, &dto)
+	return reaction
+}
+
+// ToReactionDTO : entity.Reaction -> ReactionGorm
+func ToReactionDTO(reaction *entity.Reaction) *ReactionGorm {
+	dto := new(ReactionGorm)
+	copier.Copy(&dto, &reaction)
+	return dto
+}
+
+// GetByUnq func gets Reaction row by unique_key(DiaryID, MemberID)
+func (rr *ReactionRepository) GetByUnq(diaryID, memberID uint) (*entity.Reaction, error) {
+	dto := ReactionGorm{}
+	if err := rr.db.Where("diary_id = ? AND member_id = ?", diaryID, memberID).First(&dto).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrReactionNotFound
+		}
+		return nil, err
+	}
+	return ToReactionEntity(&dto), nil
+}
+
+// Create ...
+func (rr *ReactionRepository) Create(reaction *entity.Reaction) (*entity.Reaction, error) {
+	dto := ToReactionDTO(reaction)
+	if err := rr.db.Omit("Diary", "Member").Create(&dto).Error; err != nil {
+		return nil, err
+	}
+	return ToReactionEntity(dto), nil
+}
+
+// Update changes emoji of the reaction
+func (rr *ReactionRepository) Update(reaction *entity.Reaction) (*entity.Reaction, error) {
+	dto := ToReactionDTO(reaction)
+	if err := rr.db.Model(&dto).Update("emoji", dto.Emoji).Error; err != nil {
+		return nil, err
+	}
+	return ToReactionEntity(dto), nil
+}
+
+// Delete ...
+func (rr *ReactionRepository) Delete(reaction *entity.Reaction) error {
+	return rr.db.Delete(&ReactionGorm{ID: reaction.ID}).Error
+}
+
+type reactionCountRow struct {
+	DiaryID uint
+	Emoji   string
+	Count   uint
+}
+
+// CountAll aggregates reactions of diaries per emoji with a single query.
+func (rr *ReactionRepository) CountAll(diaryIDs []uint) (map[uint]entity.ReactionCounts, error) {
+	counts := map[uint]entity.ReactionCounts{}
+	if len(diaryIDs) == 0 {
+		return counts, nil
+	}
+
+	rows := []reactionCountRow{}
+	if err := rr.db.Model(&ReactionGorm{}).
+		Select("diary_id, emoji, COUNT(*) AS count").
+		Where("diary_id IN (?)", diaryIDs).
+		Group("diary_id, emoji").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if _, ok := counts[row.DiaryID]; !ok {
+			counts[row.DiaryID] = entity.ReactionCounts{}
+		}
+		counts[row.DiaryID][entity.Emoji(row.Emoji)] = row.Count
+	}
+	return counts, nil
+}
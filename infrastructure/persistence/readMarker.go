@@ -0,0 +1,79 @@
+package persistence
+
+import (
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReadMarkerGorm is a db representation of entity.ReadMarker
+// "idx_member_room" is a unique key combined with (MemberID, RoomID)
+type ReadMarkerGorm struct {
+	ID              uint       `gorm:"primaryKey"`
+	MemberID        uint       `gorm:"column:member_id;uniqueIndex:idx_member_room"`
+	Member          MemberGorm `gorm:"column:member_id;constraint:OnDelete:CASCADE;"`
+	RoomID          uint       `gorm:"column:room_id;uniqueIndex:idx_member_room"`
+	Room            RoomGorm   `gorm:"column:room_id;constraint:OnDelete:CASCADE;"`
+	LastReadDiaryID uint       `gorm:"column:last_read_diary_id;not null"`
+	BaseGormModel
+}
+
+// TableName define gorm table name
+func (ReadMarkerGorm) TableName() string {
+	return "read_markers"
+}
+
+// ReadMarkerRepository is a impl of domain/repository/readMarkerRepository.go ReadMarkerRepository interface
+type ReadMarkerRepository struct {
+	db *gorm.DB
+}
+
+// NewReadMarkerRepository ...
+func NewReadMarkerRepository(db *gorm.DB) repository.ReadMarkerRepository {
+	return &ReadMarkerRepository{db: db}
+}
+
+// Save upserts a read marker. The marker never moves backward.
+func (rmr *ReadMarkerRepository) Save(marker *entity.ReadMarker) error {
+	dto := ReadMarkerGorm{
+		MemberID:        marker.MemberID,
+		RoomID:          marker.RoomID,
+		LastReadDiaryID: marker.LastReadDiaryID,
+	}
+	return rmr.db.Omit("Member", "Room").Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"last_read_diary_id": gorm.Expr("GREATEST(last_read_diary_id, VALUES(last_read_diary_id))"),
+			"updated_at":         gorm.Expr("VALUES(updated_at)"),
+		}),
+	}).Create(&dto).Error
+}
+
+type unreadCountRow struct {
+	RoomID      uint
+	UnreadCount uint
+}
+
+// UnreadCounts returns map of (roomID -> number of unread diaries) with a single query.
+// Member's own diaries are always considered as read.
+func (rmr *ReadMarkerRepository) UnreadCounts(memberID uint, roomIDs []uint) (map[uint]uint, error) {
+	counts := map[uint]uint{}
+	if len(roomIDs) == 0 {
+		return counts, nil
+	}
+
+	rows := []unreadCountRow{}
+	if err := rmr.db.Table("diaries AS d").
+		Select("d.room_id AS room_id, COUNT(*) AS unread_count").
+		Joins("LEFT JOIN read_markers AS rm ON rm.room_id = d.room_id AND rm.member_id = ?", memberID).
+		Where("d.room_id IN (?) AND d.author_id <> ?", roomIDs, memberID).
+		Where("d.id > COALESCE(rm.last_read_diary_id, 0)").
+		Group("d.room_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.RoomID] = row.UnreadCount
+	}
+	return counts, nil
+}
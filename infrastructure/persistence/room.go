@@ -2,6 +2,7 @@ package persistence
 
 import (
 	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/ExchangeDiary/exchange-diary/domain/entity"
@@ -10,6 +11,7 @@ import (
 	"github.com/jinzhu/copier"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // RoomGorm is a db representation of entity.Room
@@ -24,14 +26,44 @@ type RoomGorm struct {
 	MasterID      uint       `gorm:"column:master_id"`
 	Master        MemberGorm `gorm:"column:master_id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
 	TurnAccountID uint       `gorm:"column:turn_account_id"`
+	Turn          uint       `gorm:"column:turn;not null;default:1"`
 	DueAt         time.Time  `gorm:"column:due_at"`
+	EndAt         *time.Time `gorm:"column:end_at"`
+	ArchivedAt    *time.Time `gorm:"column:archived_at;index"`
 	TurnAccount   MemberGorm `gorm:"column:turn_account_id;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
 
 	Orders datatypes.JSON `gorm:"column:orders"`
+	// orders json of current cycle with accepted swaps. NULL means there is no swap
+	SwappedOrders datatypes.JSON `gorm:"column:swapped_orders"`
+	// minutes json. NULL means entity.DefaultReminderOffsets
+	ReminderOffsets datatypes.JSON `gorm:"column:reminder_offsets"`
+	// {"accountID": awayUntil} json
+	AwayUntil datatypes.JSON `gorm:"column:away_until"`
 
 	BaseGormModel
 }
 
+// HashRoomCodes re-hashes plaintext codes of rooms created before code hashing.
+// Already hashed codes are skipped, so it is safe to run on every start.
+func HashRoomCodes(db *gorm.DB) error {
+	var dtos []RoomGorm
+	return db.Model(&RoomGorm{}).Select("id", "code").Where("code NOT LIKE ?", "$2_$%").FindInBatches(&dtos, 100, func(tx *gorm.DB, batch int) error {
+		for _, dto := range dtos {
+			if entity.IsHashedRoomCode(dto.Code) {
+				continue
+			}
+			hashedCode, err := entity.HashRoomCode(dto.Code)
+			if err != nil {
+				return err
+			}
+			if err := db.Model(&RoomGorm{}).Where("id = ?", dto.ID).UpdateColumn("code", hashedCode).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
 // RoomGorms define list of RoomGorm
 type RoomGorms []RoomGorm
 
@@ -61,18 +93,20 @@ func masterOrMember(masterID uint, memberRoomIDs []uint) func(db *gorm.DB) *gorm
 	}
 }
 
-// TODO: move to diary repository
-func paginate(limit, offset uint) func(db *gorm.DB) *gorm.DB {
-	return func(db *gorm.DB) *gorm.DB {
-		return db.Offset(int(offset)).Limit(int(limit))
-	}
-}
-
 // ToDTO : entity.Room -> RoomGorm
 func ToDTO(dto *RoomGorm, room *entity.Room) *RoomGorm {
 	copier.Copy(&dto, &room)
 	ordersJSON, _ := room.OrdersToJSON()
 	dto.Orders = datatypes.JSON(ordersJSON)
+	dto.SwappedOrders = nil
+	if room.SwappedOrders != nil {
+		swappedJSON, _ := json.Marshal(room.SwappedOrders)
+		dto.SwappedOrders = datatypes.JSON(swappedJSON)
+	}
+	offsetsJSON, _ := room.ReminderOffsetsToJSON()
+	dto.ReminderOffsets = datatypes.JSON(offsetsJSON)
+	awayJSON, _ := room.AwayUntilToJSON()
+	dto.AwayUntil = datatypes.JSON(awayJSON)
 	return dto
 }
 
@@ -88,6 +122,26 @@ func ToEntity(dto *RoomGorm) *entity.Room {
 		logger.Error(err.Error())
 	}
 	room.Orders = orders
+	room.SwappedOrders = nil
+	if len(dto.SwappedOrders) > 0 {
+		if err := json.Unmarshal([]byte(dto.SwappedOrders), &room.SwappedOrders); err != nil {
+			logger.Error(err.Error())
+		}
+	}
+	room.ReminderOffsets = append([]time.Duration{}, entity.DefaultReminderOffsets...)
+	if len(dto.ReminderOffsets) > 0 {
+		var minutes []uint
+		if err := json.Unmarshal([]byte(dto.ReminderOffsets), &minutes); err != nil {
+			logger.Error(err.Error())
+		}
+		room.ReminderOffsets = entity.MinutesToDurations(minutes)
+	}
+	room.AwayUntil = map[uint]time.Time{}
+	if len(dto.AwayUntil) > 0 {
+		if err := json.Unmarshal([]byte(dto.AwayUntil), &room.AwayUntil); err != nil {
+			logger.Error(err.Error())
+		}
+	}
 	return room
 }
 
@@ -104,6 +158,21 @@ func (rr *RoomRepository) Create(room *entity.Room) (*entity.Room, error) {
 func (rr *RoomRepository) GetByID(id uint) (*entity.Room, error) {
 	dto := RoomGorm{ID: id}
 	if err := rr.db.First(&dto).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrRoomNotFound
+		}
+		return nil, err
+	}
+	return ToEntity(&dto), nil
+}
+
+// GetByIDForUpdate finds a row by ID, and locks it until the transaction ends.
+func (rr *RoomRepository) GetByIDForUpdate(id uint) (*entity.Room, error) {
+	dto := RoomGorm{ID: id}
+	if err := rr.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&dto).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrRoomNotFound
+		}
 		return nil, err
 	}
 	return ToEntity(&dto), nil
@@ -120,6 +189,19 @@ func (rr *RoomRepository) GetAll(accountID uint, roomIDs []uint) (*entity.Rooms,
 	return &rooms, nil
 }
 
+// GetPage returns rooms whose id is greater than afterID ordered by id. (used for full scan)
+func (rr *RoomRepository) GetPage(afterID uint, limit int) (*entity.Rooms, error) {
+	dto := RoomGorms{}
+	if err := rr.db.Where("id > ?", afterID).Order("id ASC").Limit(limit).Find(&dto).Error; err != nil {
+		return nil, err
+	}
+	rooms := entity.Rooms{}
+	for _, roomGorm := range dto {
+		rooms = append(rooms, *ToEntity(&roomGorm))
+	}
+	return &rooms, nil
+}
+
 // Update func update a room fields
 func (rr *RoomRepository) Update(room *entity.Room) (*entity.Room, error) {
 	dto := ToDTO(&RoomGorm{}, room)
@@ -129,6 +211,12 @@ func (rr *RoomRepository) Update(room *entity.Room) (*entity.Room, error) {
 	return ToEntity(dto), nil
 }
 
+// UpdateAwayUntil updates away_until column only.
+func (rr *RoomRepository) UpdateAwayUntil(room *entity.Room) error {
+	dto := ToDTO(&RoomGorm{}, room)
+	return rr.db.Model(&RoomGorm{ID: room.ID}).Update("away_until", dto.AwayUntil).Error
+}
+
 // Delete func delete a room
 func (rr *RoomRepository) Delete(room *entity.Room) error {
 	dto := ToDTO(&RoomGorm{}, room)
@@ -0,0 +1,110 @@
+package persistence
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"github.com/jinzhu/copier"
+	"gorm.io/gorm"
+)
+
+// RoomInviteGorm is a db representation of entity.RoomInvite
+type RoomInviteGorm struct {
+	ID        uint       `gorm:"primaryKey"`
+	RoomID    uint       `gorm:"column:room_id;index"`
+	Room      RoomGorm   `gorm:"column:room_id;constraint:OnDelete:CASCADE;"`
+	IssuerID  uint       `gorm:"column:issuer_id"`
+	Issuer    MemberGorm `gorm:"column:issuer_id;constraint:OnDelete:CASCADE;"`
+	Nonce     string     `gorm:"column:nonce;type:char(32)"`
+	MaxUses   uint       `gorm:"column:max_uses;not null"`
+	UseCount  uint       `gorm:"column:use_count;not null;default:0"`
+	ExpiresAt time.Time  `gorm:"column:expires_at;not null"`
+	RevokedAt *time.Time `gorm:"column:revoked_at"`
+	BaseGormModel
+}
+
+// TableName define gorm table name
+func (RoomInviteGorm) TableName() string {
+	return "room_invites"
+}
+
+// RoomInviteRepository is a impl of domain/repository/roomInviteRepository.go RoomInviteRepository interface
+type RoomInviteRepository struct {
+	db *gorm.DB
+}
+
+// NewRoomInviteRepository ...
+func NewRoomInviteRepository(db *gorm.DB) repository.RoomInviteRepository {
+	return &RoomInviteRepository{db: db}
+}
+
+// ToRoomInviteEntity : RoomInviteGorm -> entity.RoomInvite
+func ToRoomInviteEntity(dto *RoomInviteGorm) *entity.RoomInvite {
+	invite := new(entity.RoomInvite)
+	copier.Copy(&invite, &dto)
+	return invite
+}
+
+// ToRoomInviteDTO : entity.RoomInvite -> RoomInviteGorm
+func ToRoomInviteDTO(invite *entity.RoomInvite) *RoomInviteGorm {
+	dto := new(RoomInviteGorm)
+	copier.Copy(&dto, &invite)
+	return dto
+}
+
+// Create ...
+func (rir *RoomInviteRepository) Create(invite *entity.RoomInvite) (*entity.RoomInvite, error) {
+	dto := ToRoomInviteDTO(invite)
+	if err := rir.db.Omit("Room", "Issuer").Create(&dto).Error; err != nil {
+		return nil, err
+	}
+	return ToRoomInviteEntity(dto), nil
+}
+
+// GetByID ...
+func (rir *RoomInviteRepository) GetByID(id uint) (*entity.RoomInvite, error) {
+	dto := RoomInviteGorm{}
+	if err := rir.db.First(&dto, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrRoomInviteNotFound
+		}
+		return nil, err
+	}
+	return ToRoomInviteEntity(&dto), nil
+}
+
+// GetAllByRoomID returns every invite of the room (latest first)
+func (rir *RoomInviteRepository) GetAllByRoomID(roomID uint) ([]entity.RoomInvite, error) {
+	var dtos []RoomInviteGorm
+	if err := rir.db.Where("room_id = ?", roomID).Order(" id desc ").Find(&dtos).Error; err != nil {
+		return nil, err
+	}
+	invites := []entity.RoomInvite{}
+	for i := range dtos {
+		invites = append(invites, *ToRoomInviteEntity(&dtos[i]))
+	}
+	return invites, nil
+}
+
+// Revoke ...
+func (rir *RoomInviteRepository) Revoke(invite *entity.RoomInvite) error {
+	return rir.db.Model(&RoomInviteGorm{}).
+		Where("id = ?", invite.ID).
+		Update("revoked_at", invite.RevokedAt).Error
+}
+
+// Use increases use count only if the invite is still valid, so that concurrent joins cannot exceed max uses.
+func (rir *RoomInviteRepository) Use(id uint) error {
+	result := rir.db.Model(&RoomInviteGorm{}).
+		Where("id = ? AND revoked_at IS NULL AND expires_at > ? AND use_count < max_uses", id, time.Now()).
+		Update("use_count", gorm.Expr("use_count + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity.ErrRoomInviteExhausted
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package persistence
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+)
+
+func TestRoomOrdersRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		orders  []uint
+		swapped []uint
+	}{
+		{"no swap", []uint{1, 2, 3}, nil},
+		{"swapped", []uint{1, 2, 3}, []uint{1, 3, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room := &entity.Room{ID: 1, Orders: tt.orders, SwappedOrders: tt.swapped}
+
+			got := ToEntity(ToDTO(&RoomGorm{}, room))
+			if !reflect.DeepEqual(got.Orders, tt.orders) {
+				t.Errorf("Orders = %v, want %v", got.Orders, tt.orders)
+			}
+			if !reflect.DeepEqual(got.SwappedOrders, tt.swapped) {
+				t.Errorf("SwappedOrders = %v, want %v", got.SwappedOrders, tt.swapped)
+			}
+		})
+	}
+}
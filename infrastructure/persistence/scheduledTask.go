@@ -0,0 +1,178 @@
+package persistence
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"github.com/ExchangeDiary/exchange-diary/domain/vo"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ScheduledTaskGorm is a db representation of entity.ScheduledTask (used by local scheduler)
+type ScheduledTaskGorm struct {
+	ID            string     `gorm:"primaryKey;type:varchar(191)"`
+	RoomID        uint       `gorm:"column:room_id;not null"`
+	Email         string     `gorm:"column:email"`
+	Code          string     `gorm:"column:code;type:varchar(32);not null"`
+	Turn          uint       `gorm:"column:turn"`
+	DueAt         *time.Time `gorm:"column:due_at"`
+	AlarmID       uint       `gorm:"column:alarm_id"`
+	SwapRequestID uint       `gorm:"column:swap_request_id"`
+	CallbackURL   string     `gorm:"column:callback_url;type:text"`
+	ScheduledAt   time.Time  `gorm:"column:scheduled_at;index:idx_scheduled_at"`
+	Attempts      uint       `gorm:"column:attempts;not null"`
+	LockedUntil   *time.Time `gorm:"column:locked_until"`
+	BaseGormModel
+}
+
+// TableName define gorm table name
+func (ScheduledTaskGorm) TableName() string {
+	return "scheduled_tasks"
+}
+
+// ScheduledTaskRepository is a impl of domain/repository/scheduledTaskRepository.go ScheduledTaskRepository interface
+type ScheduledTaskRepository struct {
+	db *gorm.DB
+}
+
+// NewScheduledTaskRepository ...
+func NewScheduledTaskRepository(db *gorm.DB) repository.ScheduledTaskRepository {
+	return &ScheduledTaskRepository{db: db}
+}
+
+// ToScheduledTaskEntity : ScheduledTaskGorm -> entity.ScheduledTask
+func ToScheduledTaskEntity(dto *ScheduledTaskGorm) *entity.ScheduledTask {
+	task := entity.NewScheduledTask(
+		dto.ID,
+		vo.NewTaskVO(dto.RoomID, dto.Email, vo.TaskCode(dto.Code)).OnTurn(dto.Turn, dto.DueAt),
+		dto.CallbackURL,
+		dto.ScheduledAt,
+	)
+	task.Task.AlarmID = dto.AlarmID
+	task.Task.SwapRequestID = dto.SwapRequestID
+	task.Attempts = dto.Attempts
+	task.LockedUntil = dto.LockedUntil
+	return task
+}
+
+// ToScheduledTaskDTO : entity.ScheduledTask -> ScheduledTaskGorm
+func ToScheduledTaskDTO(task *entity.ScheduledTask) *ScheduledTaskGorm {
+	return &ScheduledTaskGorm{
+		ID:            task.ID,
+		RoomID:        task.Task.RoomID,
+		Email:         task.Task.Email,
+		Code:          string(task.Task.Code),
+		Turn:          task.Task.Turn,
+		DueAt:         task.Task.DueAt,
+		AlarmID:       task.Task.AlarmID,
+		SwapRequestID: task.Task.SwapRequestID,
+		CallbackURL:   task.CallbackURL,
+		ScheduledAt:   task.ScheduledAt,
+		Attempts:      task.Attempts,
+		LockedUntil:   task.LockedUntil,
+	}
+}
+
+// Save inserts or replaces a task. A replaced task is unlocked, so it runs again at new ScheduledAt.
+func (str *ScheduledTaskRepository) Save(task *entity.ScheduledTask) (*entity.ScheduledTask, error) {
+	dto := ToScheduledTaskDTO(task)
+	dto.Attempts = 0
+	dto.LockedUntil = nil
+	if err := str.db.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"room_id", "email", "code", "turn", "due_at", "alarm_id", "swap_request_id", "callback_url", "scheduled_at", "attempts", "locked_until", "updated_at"}),
+	}).Create(&dto).Error; err != nil {
+		return nil, err
+	}
+	return ToScheduledTaskEntity(dto), nil
+}
+
+// GetByID ...
+func (str *ScheduledTaskRepository) GetByID(id string) (*entity.ScheduledTask, error) {
+	dto := ScheduledTaskGorm{}
+	if err := str.db.Where("id = ?", id).First(&dto).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrScheduledTaskNotFound
+		}
+		return nil, err
+	}
+	return ToScheduledTaskEntity(&dto), nil
+}
+
+// GetAll returns every pending task ordered by scheduled time.
+func (str *ScheduledTaskRepository) GetAll() ([]entity.ScheduledTask, error) {
+	dtos := []ScheduledTaskGorm{}
+	if err := str.db.Order("scheduled_at ASC").Find(&dtos).Error; err != nil {
+		return nil, err
+	}
+	tasks := make([]entity.ScheduledTask, 0, len(dtos))
+	for i := range dtos {
+		tasks = append(tasks, *ToScheduledTaskEntity(&dtos[i]))
+	}
+	return tasks, nil
+}
+
+// Delete ...
+func (str *ScheduledTaskRepository) Delete(id string) error {
+	result := str.db.Where("id = ?", id).Delete(&ScheduledTaskGorm{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity.ErrScheduledTaskNotFound
+	}
+	return nil
+}
+
+// Claim locks due tasks for lease duration and returns them.
+// A task is claimed only if its lock is empty or expired, so several server instances can poll the same table.
+func (str *ScheduledTaskRepository) Claim(now time.Time, lease time.Duration, limit int) ([]entity.ScheduledTask, error) {
+	candidates := []ScheduledTaskGorm{}
+	if err := str.db.
+		Where("scheduled_at <= ? AND (locked_until IS NULL OR locked_until < ?)", now, now).
+		Order("scheduled_at ASC").
+		Limit(limit).
+		Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	// truncated to be compared exactly with the stored datetime on Release
+	lockedUntil := now.Add(lease).Truncate(time.Second)
+	claimed := []entity.ScheduledTask{}
+	for i := range candidates {
+		dto := &candidates[i]
+		result := str.db.Model(&ScheduledTaskGorm{}).
+			Where("id = ? AND scheduled_at = ? AND (locked_until IS NULL OR locked_until < ?)", dto.ID, dto.ScheduledAt, now).
+			Update("locked_until", lockedUntil)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		// claimed by another worker or rescheduled
+		if result.RowsAffected == 0 {
+			continue
+		}
+		dto.LockedUntil = &lockedUntil
+		claimed = append(claimed, *ToScheduledTaskEntity(dto))
+	}
+	return claimed, nil
+}
+
+// Release removes a finished task only if it is not rescheduled while running.
+func (str *ScheduledTaskRepository) Release(task *entity.ScheduledTask) error {
+	return str.db.
+		Where("id = ? AND locked_until = ?", task.ID, task.LockedUntil).
+		Delete(&ScheduledTaskGorm{}).Error
+}
+
+// Retry reschedules a failed task at given time only if it is not rescheduled while running.
+func (str *ScheduledTaskRepository) Retry(task *entity.ScheduledTask, at time.Time) error {
+	return str.db.Model(&ScheduledTaskGorm{}).
+		Where("id = ? AND locked_until = ?", task.ID, task.LockedUntil).
+		Updates(map[string]interface{}{
+			"scheduled_at": at,
+			"attempts":     gorm.Expr("attempts + 1"),
+			"locked_until": nil,
+		}).Error
+}
@@ -0,0 +1,122 @@
+package persistence
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"github.com/jinzhu/copier"
+	"gorm.io/gorm"
+)
+
+// SwapRequestGorm is a db representation of entity.SwapRequest
+type SwapRequestGorm struct {
+	ID          uint       `gorm:"primaryKey"`
+	RoomID      uint       `gorm:"column:room_id;index:idx_room_status"`
+	Room        RoomGorm   `gorm:"column:room_id;constraint:OnDelete:CASCADE;"`
+	RequesterID uint       `gorm:"column:requester_id"`
+	Requester   MemberGorm `gorm:"column:requester_id;constraint:OnDelete:CASCADE;"`
+	ResponderID uint       `gorm:"column:responder_id"`
+	Responder   MemberGorm `gorm:"column:responder_id;constraint:OnDelete:CASCADE;"`
+	Status      string     `gorm:"column:status;type:varchar(16);not null;index:idx_room_status"`
+	ExpiresAt   time.Time  `gorm:"column:expires_at;not null"`
+	RespondedAt *time.Time `gorm:"column:responded_at"`
+	BaseGormModel
+}
+
+// TableName define gorm table name
+func (SwapRequestGorm) TableName() string {
+	return "swap_requests"
+}
+
+// SwapRequestRepository is a impl of domain/repository/swapRequestRepository.go SwapRequestRepository interface
+type SwapRequestRepository struct {
+	db *gorm.DB
+}
+
+// NewSwapRequestRepository ...
+func NewSwapRequestRepository(db *gorm.DB) repository.SwapRequestRepository {
+	return &SwapRequestRepository{db: db}
+}
+
+// ToSwapRequestEntity : SwapRequestGorm -> entity.SwapRequest
+func ToSwapRequestEntity(dto *SwapRequestGorm) *entity.SwapRequest {
+	request := new(entity.SwapRequest)
+	copier.Copy(&request, &dto)
+	return request
+}
+
+// ToSwapRequestDTO : entity.SwapRequest -> SwapRequestGorm
+func ToSwapRequestDTO(request *entity.SwapRequest) *SwapRequestGorm {
+	dto := new(SwapRequestGorm)
+	copier.Copy(&dto, &request)
+	return dto
+}
+
+// Create ...
+func (srr *SwapRequestRepository) Create(request *entity.SwapRequest) (*entity.SwapRequest, error) {
+	dto := ToSwapRequestDTO(request)
+	if err := srr.db.Omit("Room", "Requester", "Responder").Create(&dto).Error; err != nil {
+		return nil, err
+	}
+	return ToSwapRequestEntity(dto), nil
+}
+
+// GetByID ...
+func (srr *SwapRequestRepository) GetByID(id uint) (*entity.SwapRequest, error) {
+	dto := SwapRequestGorm{}
+	if err := srr.db.First(&dto, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrSwapRequestNotFound
+		}
+		return nil, err
+	}
+	return ToSwapRequestEntity(&dto), nil
+}
+
+// GetPending ...
+func (srr *SwapRequestRepository) GetPending(roomID, a, b uint) (*entity.SwapRequest, error) {
+	dto := SwapRequestGorm{}
+	if err := srr.db.Where("room_id = ? AND status = ?", roomID, string(entity.SwapPending)).
+		Where("(requester_id = ? AND responder_id = ?) OR (requester_id = ? AND responder_id = ?)", a, b, b, a).
+		First(&dto).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrSwapRequestNotFound
+		}
+		return nil, err
+	}
+	return ToSwapRequestEntity(&dto), nil
+}
+
+// GetAllPending ...
+func (srr *SwapRequestRepository) GetAllPending(roomID, memberID uint) ([]entity.SwapRequest, error) {
+	var dtos []SwapRequestGorm
+	if err := srr.db.Where("room_id = ? AND status = ?", roomID, string(entity.SwapPending)).
+		Where("requester_id = ? OR responder_id = ?", memberID, memberID).
+		Order(" id desc ").
+		Find(&dtos).Error; err != nil {
+		return nil, err
+	}
+	requests := []entity.SwapRequest{}
+	for i := range dtos {
+		requests = append(requests, *ToSwapRequestEntity(&dtos[i]))
+	}
+	return requests, nil
+}
+
+// Update saves the answer of request. Only pending request is updated, so that a request is not answered twice.
+func (srr *SwapRequestRepository) Update(request *entity.SwapRequest) (*entity.SwapRequest, error) {
+	dto := ToSwapRequestDTO(request)
+	result := srr.db.Model(dto).
+		Where("status = ?", string(entity.SwapPending)).
+		Select("status", "responded_at").
+		Updates(dto)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, entity.ErrSwapRequestNotPending
+	}
+	return ToSwapRequestEntity(dto), nil
+}
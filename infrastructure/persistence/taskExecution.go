@@ -0,0 +1,155 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"github.com/jinzhu/copier"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TaskExecutionGorm is a db representation of entity.TaskExecution
+// "idx_task_fingerprint" is a unique key combined with (TaskID, Fingerprint)
+type TaskExecutionGorm struct {
+	ID          uint       `gorm:"primaryKey"`
+	TaskID      string     `gorm:"column:task_id;type:varchar(191);uniqueIndex:idx_task_fingerprint"`
+	Fingerprint string     `gorm:"column:fingerprint;type:varchar(64);uniqueIndex:idx_task_fingerprint"`
+	RoomID      uint       `gorm:"column:room_id;index"`
+	Code        string     `gorm:"column:code;type:varchar(32)"`
+	Status      string     `gorm:"column:status;type:varchar(16);not null"`
+	Attempts    uint       `gorm:"column:attempts;not null"`
+	LastError   string     `gorm:"column:last_error;type:text"`
+	LockedUntil *time.Time `gorm:"column:locked_until"`
+	FinishedAt  *time.Time `gorm:"column:finished_at"`
+	BaseGormModel
+}
+
+// TableName define gorm table name
+func (TaskExecutionGorm) TableName() string {
+	return "task_executions"
+}
+
+// TaskAttemptGorm is a db representation of entity.TaskAttempt
+type TaskAttemptGorm struct {
+	ID          uint              `gorm:"primaryKey"`
+	ExecutionID uint              `gorm:"column:execution_id;index"`
+	Execution   TaskExecutionGorm `gorm:"foreignKey:ExecutionID;constraint:OnDelete:CASCADE;"`
+	Number      uint              `gorm:"column:number"`
+	Outcome     string            `gorm:"column:outcome;type:varchar(16);not null"`
+	Error       string            `gorm:"column:error;type:text"`
+	StartedAt   time.Time         `gorm:"column:started_at"`
+	FinishedAt  time.Time         `gorm:"column:finished_at"`
+}
+
+// TableName define gorm table name
+func (TaskAttemptGorm) TableName() string {
+	return "task_attempts"
+}
+
+// TaskExecutionRepository is a impl of domain/repository/taskExecutionRepository.go TaskExecutionRepository interface
+type TaskExecutionRepository struct {
+	db *gorm.DB
+}
+
+// NewTaskExecutionRepository ...
+func NewTaskExecutionRepository(db *gorm.DB) repository.TaskExecutionRepository {
+	return &TaskExecutionRepository{db: db}
+}
+
+// ToTaskExecutionEntity : TaskExecutionGorm -> entity.TaskExecution
+func ToTaskExecutionEntity(dto *TaskExecutionGorm) *entity.TaskExecution {
+	execution := new(entity.TaskExecution)
+	copier.Copy(&execution, &dto)
+	return execution
+}
+
+// ToTaskExecutionDTO : entity.TaskExecution -> TaskExecutionGorm
+func ToTaskExecutionDTO(execution *entity.TaskExecution) *TaskExecutionGorm {
+	dto := new(TaskExecutionGorm)
+	copier.Copy(&dto, &execution)
+	return dto
+}
+
+// Acquire locks the execution of (TaskID, Fingerprint) for lease duration. The row is created on the first delivery.
+// It returns entity.ErrTaskAlreadyExecuted if the execution is finished,
+// and entity.ErrTaskInProgress if another delivery holds the lock.
+func (ter *TaskExecutionRepository) Acquire(execution *entity.TaskExecution, now time.Time, lease time.Duration) (*entity.TaskExecution, error) {
+	dto := ToTaskExecutionDTO(execution)
+	if err := ter.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&dto).Error; err != nil {
+		return nil, err
+	}
+
+	where := ter.db.Where("task_id = ? AND fingerprint = ?", execution.TaskID, execution.Fingerprint)
+	result := ter.db.Model(&TaskExecutionGorm{}).
+		Where(where).
+		Where(ter.db.
+			Where("status IN ?", []string{string(entity.TaskPending), string(entity.TaskRetrying)}).
+			// lock of crashed delivery is expired
+			Or("status = ? AND locked_until < ?", string(entity.TaskRunning), now)).
+		Updates(map[string]interface{}{
+			"status":       string(entity.TaskRunning),
+			"attempts":     gorm.Expr("attempts + 1"),
+			"locked_until": now.Add(lease),
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	saved := TaskExecutionGorm{}
+	if err := ter.db.Where(where).First(&saved).Error; err != nil {
+		return nil, err
+	}
+	acquired := ToTaskExecutionEntity(&saved)
+	if result.RowsAffected == 0 {
+		if acquired.IsFinished() {
+			return acquired, entity.ErrTaskAlreadyExecuted
+		}
+		return acquired, entity.ErrTaskInProgress
+	}
+	return acquired, nil
+}
+
+// Finish saves the result of execution and its attempt in a transaction.
+func (ter *TaskExecutionRepository) Finish(execution *entity.TaskExecution, attempt *entity.TaskAttempt) error {
+	return ter.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&TaskExecutionGorm{ID: execution.ID}).Updates(map[string]interface{}{
+			"status":       string(execution.Status),
+			"last_error":   execution.LastError,
+			"locked_until": execution.LockedUntil,
+			"finished_at":  execution.FinishedAt,
+		}).Error; err != nil {
+			return err
+		}
+		return NewTaskExecutionRepository(tx).CreateAttempt(attempt)
+	})
+}
+
+// CreateAttempt ...
+func (ter *TaskExecutionRepository) CreateAttempt(attempt *entity.TaskAttempt) error {
+	dto := TaskAttemptGorm{
+		ExecutionID: attempt.ExecutionID,
+		Number:      attempt.Number,
+		Outcome:     string(attempt.Outcome),
+		Error:       attempt.Error,
+		StartedAt:   attempt.StartedAt,
+		FinishedAt:  attempt.FinishedAt,
+	}
+	if err := ter.db.Omit("Execution").Create(&dto).Error; err != nil {
+		return err
+	}
+	attempt.ID = dto.ID
+	return nil
+}
+
+// Exists reports whether the task of (taskID, fingerprint) is ever delivered.
+func (ter *TaskExecutionRepository) Exists(taskID, fingerprint string) (bool, error) {
+	var count int64
+	if err := ter.db.Model(&TaskExecutionGorm{}).
+		Where("task_id = ? AND fingerprint = ?", taskID, fingerprint).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,106 @@
+package persistence
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newTaskExecutionTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&TaskExecutionGorm{}, &TaskAttemptGorm{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// ledgerStep delivers a task of fingerprint at now + after. If finish is set, the acquired execution is finished with err.
+type ledgerStep struct {
+	after        time.Duration
+	fingerprint  string
+	finish       bool
+	err          error
+	wantErr      error // of Acquire
+	wantStatus   entity.TaskExecutionStatus
+	wantAttempts uint
+}
+
+func TestTaskExecutionRepositoryAcquire(t *testing.T) {
+	const lease = time.Minute
+	now := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC)
+	transient := errors.New("unavailable")
+	permanent := entity.Permanent(entity.ErrRoomNotFound)
+
+	tests := []struct {
+		name  string
+		steps []ledgerStep
+	}{
+		{"first delivery", []ledgerStep{
+			{0, "1", false, nil, nil, entity.TaskRunning, 1},
+		}},
+		{"duplicated delivery while running", []ledgerStep{
+			{0, "1", false, nil, nil, entity.TaskRunning, 1},
+			{time.Second, "1", false, nil, entity.ErrTaskInProgress, entity.TaskRunning, 1},
+		}},
+		{"lock of crashed delivery is expired", []ledgerStep{
+			{0, "1", false, nil, nil, entity.TaskRunning, 1},
+			{lease + time.Second, "1", false, nil, nil, entity.TaskRunning, 2},
+		}},
+		{"retry after transient error", []ledgerStep{
+			{0, "1", true, transient, nil, entity.TaskRetrying, 1},
+			{time.Second, "1", false, nil, nil, entity.TaskRunning, 2},
+		}},
+		{"duplicated delivery after success", []ledgerStep{
+			{0, "1", true, nil, nil, entity.TaskSucceeded, 1},
+			{time.Second, "1", false, nil, entity.ErrTaskAlreadyExecuted, entity.TaskSucceeded, 1},
+		}},
+		{"no retry after permanent error", []ledgerStep{
+			{0, "1", true, permanent, nil, entity.TaskFailed, 1},
+			{time.Second, "1", false, nil, entity.ErrTaskAlreadyExecuted, entity.TaskFailed, 1},
+		}},
+		{"next turn runs again", []ledgerStep{
+			{0, "1", true, nil, nil, entity.TaskSucceeded, 1},
+			{time.Second, "2", false, nil, nil, entity.TaskRunning, 1},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ter := NewTaskExecutionRepository(newTaskExecutionTestDB(t))
+			for i, step := range tt.steps {
+				at := now.Add(step.after)
+				execution, err := ter.Acquire(entity.NewTaskExecution("1-2-ROOM_PERIOD_FIN", step.fingerprint, 1, "ROOM_PERIOD_FIN"), at, lease)
+				if err != step.wantErr {
+					t.Fatalf("step %d: Acquire() = %v, want %v", i, err, step.wantErr)
+				}
+				if step.finish {
+					attempt := &entity.TaskAttempt{
+						ExecutionID: execution.ID,
+						Number:      execution.Attempts,
+						Outcome:     execution.Finish(step.err, at),
+						StartedAt:   at,
+						FinishedAt:  at,
+					}
+					if err := ter.Finish(execution, attempt); err != nil {
+						t.Fatalf("step %d: Finish() = %v", i, err)
+					}
+				}
+
+				saved := TaskExecutionGorm{}
+				if err := ter.(*TaskExecutionRepository).db.First(&saved, execution.ID).Error; err != nil {
+					t.Fatal(err)
+				}
+				if entity.TaskExecutionStatus(saved.Status) != step.wantStatus || saved.Attempts != step.wantAttempts {
+					t.Errorf("step %d: status = %s (%d attempts), want %s (%d attempts)",
+						i, saved.Status, saved.Attempts, step.wantStatus, step.wantAttempts)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package persistence
+
+import (
+	"errors"
+
+	"github.com/ExchangeDiary/exchange-diary/domain/entity"
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TaskNameGorm maps a task id to the name of its latest instance on google cloud tasks
+type TaskNameGorm struct {
+	TaskID string `gorm:"primaryKey;column:task_id;type:varchar(191)"`
+	Name   string `gorm:"column:name;type:varchar(255);not null"`
+	BaseGormModel
+}
+
+// TableName define gorm table name
+func (TaskNameGorm) TableName() string {
+	return "task_names"
+}
+
+// TaskNameRepository is a impl of domain/repository/taskNameRepository.go TaskNameRepository interface
+type TaskNameRepository struct {
+	db *gorm.DB
+}
+
+// NewTaskNameRepository ...
+func NewTaskNameRepository(db *gorm.DB) repository.TaskNameRepository {
+	return &TaskNameRepository{db: db}
+}
+
+// Save inserts or replaces the name of task id.
+func (tnr *TaskNameRepository) Save(taskID, name string) error {
+	return tnr.db.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"name", "updated_at"}),
+	}).Create(&TaskNameGorm{TaskID: taskID, Name: name}).Error
+}
+
+// Get ...
+func (tnr *TaskNameRepository) Get(taskID string) (string, error) {
+	dto := TaskNameGorm{}
+	if err := tnr.db.Where("task_id = ?", taskID).First(&dto).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", entity.ErrScheduledTaskNotFound
+		}
+		return "", err
+	}
+	return dto.Name, nil
+}
+
+// Delete ...
+func (tnr *TaskNameRepository) Delete(taskID, name string) error {
+	return tnr.db.Where("task_id = ? AND name = ?", taskID, name).Delete(&TaskNameGorm{}).Error
+}
@@ -0,0 +1,56 @@
+package persistence
+
+import (
+	"github.com/ExchangeDiary/exchange-diary/domain/repository"
+	"gorm.io/gorm"
+)
+
+// UnitOfWork is a impl of domain/repository/unitOfWork.go UnitOfWork interface
+type UnitOfWork struct {
+	db *gorm.DB
+}
+
+// NewUnitOfWork ...
+func NewUnitOfWork(db *gorm.DB) repository.UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+// Do ...
+func (uow *UnitOfWork) Do(fn func(tx repository.Transaction) error) error {
+	return uow.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&transaction{db: tx})
+	})
+}
+
+// transaction builds repositories with the transaction session
+type transaction struct {
+	db *gorm.DB
+}
+
+func (t *transaction) Rooms() repository.RoomRepository {
+	return NewRoomRepository(t.db)
+}
+
+func (t *transaction) RoomMembers() repository.RoomMemberRepository {
+	return NewRoomMemberRepository(t.db)
+}
+
+func (t *transaction) DiaryDrafts() repository.DiaryDraftRepository {
+	return NewDiaryDraftRepository(t.db)
+}
+
+func (t *transaction) Outbox() repository.OutboxRepository {
+	return NewOutboxRepository(t.db)
+}
+
+func (t *transaction) AdminAudits() repository.AdminAuditRepository {
+	return NewAdminAuditRepository(t.db)
+}
+
+func (t *transaction) SwapRequests() repository.SwapRequestRepository {
+	return NewSwapRequestRepository(t.db)
+}
+
+func (t *transaction) RoomInvites() repository.RoomInviteRepository {
+	return NewRoomInviteRepository(t.db)
+}